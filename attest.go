@@ -0,0 +1,374 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+const (
+	// AttestationChallengeSizeBytes is the size of the challenges generated by
+	// [NewAttestationChallenge].
+	AttestationChallengeSizeBytes = 32
+
+	minAttestationChallengeSizeBytes = 16
+
+	attestationDomain = "pedersen-attestation-v1"
+)
+
+var (
+	ErrInvalidChallenge   = errors.New("attestation challenge must be at least 16 bytes")
+	ErrNilAttestation     = errors.New("attestation cannot be nil")
+	ErrInvalidAttestation = errors.New("invalid attestation")
+)
+
+// Attestation represents the proof that a shareholder knows the secret parts of
+// its share, which does not reveal them.
+// For every chunk the proof is an Okamoto proof of knowledge of the opening (s, t)
+// of the commitment g^s*h^t derived from the commitments at the abscissa of the
+// shareholder, made non-interactive with the Fiat-Shamir transform.
+// The proofs of every chunk share the same challenge, which is bound to the
+// challenge nonce chosen by the auditor, so that an attestation cannot be replayed.
+type Attestation struct {
+	// Index is the index of the shareholder.
+	Index int
+
+	// Abscissa is the abscissa related to the shareholder.
+	Abscissa *big.Int
+
+	// SplitID is the unique identifier of the split, as in [Share].
+	SplitID []byte
+
+	// Proofs is the vector of commitment proofs of the share, as in [Share].
+	// It is set only when the transcript carries only the Merkle root.
+	Proofs []CommitmentProof
+
+	// Nonces is the vector of the commitments g^k_s*h^k_t to the random nonces
+	// of the proofs, so Nonces[chunkIdx] is related to the chunk with index chunkIdx.
+	Nonces []*big.Int
+
+	// Responses is the vector of the responses (k_s + c*s, k_t + c*t) of the proofs,
+	// so Responses[chunkIdx] is related to the chunk with index chunkIdx.
+	Responses []SecretPart
+}
+
+// NewAttestationChallenge returns a random challenge nonce, which an auditor sends
+// to a shareholder for requesting a fresh attestation.
+func NewAttestationChallenge() ([]byte, error) {
+	challenge := make([]byte, AttestationChallengeSizeBytes)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// share returns the public data of the share the attestation refers to.
+func (a *Attestation) share() Share {
+	return Share{
+		Index:    a.Index,
+		Abscissa: a.Abscissa,
+		SplitID:  a.SplitID,
+		Proofs:   a.Proofs,
+	}
+}
+
+// attestationChallenge computes the Fiat-Shamir challenge of the attestation
+// from the challenge nonce, the shareholder, the commitments of the secret parts
+// and the commitments to the random nonces.
+func (p *Pedersen) attestationChallenge(ctx *big.IntContext,
+	challenge []byte,
+	a *Attestation,
+	values []*big.Int,
+) (*big.Int, error) {
+	h := sha256.New()
+	h.Write([]byte(attestationDomain))
+
+	writeBytes := func(b []byte) {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(len(b)))
+		h.Write(buf[:])
+		h.Write(b)
+	}
+
+	writeInt := func(x *big.Int) error {
+		b, err := x.Bytes()
+		if err != nil {
+			return err
+		}
+
+		writeBytes(b)
+		return nil
+	}
+
+	writeBytes(challenge)
+	writeBytes(a.SplitID)
+
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], uint64(a.Index))
+	h.Write(index[:])
+
+	if err := writeInt(a.Abscissa); err != nil {
+		return nil, err
+	}
+
+	for chunkIdx := range values {
+		if err := writeInt(values[chunkIdx]); err != nil {
+			return nil, err
+		}
+
+		if err := writeInt(a.Nonces[chunkIdx]); err != nil {
+			return nil, err
+		}
+	}
+
+	c := new(big.Int).SetBytes(h.Sum(nil))
+
+	return p.field.NewElement(ctx, c)
+}
+
+// Attest proves that the shareholder knows the secret parts of its share, in
+// response to the challenge nonce of an auditor.
+// The share is verified against the transcript before being attested.
+func (p *Pedersen) Attest(share Share, transcript *Transcript, challenge []byte) (*Attestation, error) {
+	if len(challenge) < minAttestationChallengeSizeBytes {
+		return nil, ErrInvalidChallenge
+	}
+
+	if err := p.VerifyShare(share, transcript); err != nil {
+		return nil, err
+	}
+
+	a := &Attestation{
+		Index:     share.Index,
+		Abscissa:  share.Abscissa,
+		SplitID:   share.SplitID,
+		Nonces:    make([]*big.Int, len(share.Parts)),
+		Responses: make([]SecretPart, len(share.Parts)),
+	}
+
+	if transcript.isMerkle() {
+		a.Proofs = share.Proofs
+	}
+
+	w, err := p.pool.get()
+	if err != nil {
+		return nil, err
+	}
+	defer p.pool.put(w)
+
+	values := make([]*big.Int, len(share.Parts))
+	nonces := make([]SecretPart, len(share.Parts))
+
+	commitChunk := func(chunkIdx int, part SecretPart) error {
+		w.ctx.Attach()
+		defer w.ctx.Detach()
+
+		values[chunkIdx], err = p.commit(w.mont, w.ctx, part.SShare, part.TShare)
+		if err != nil {
+			return err
+		}
+
+		nonces[chunkIdx], err = p.randomPart()
+		if err != nil {
+			return err
+		}
+
+		a.Nonces[chunkIdx], err = p.commit(w.mont, w.ctx, nonces[chunkIdx].SShare, nonces[chunkIdx].TShare)
+
+		return err
+	}
+
+	for chunkIdx, part := range share.Parts {
+		if err := commitChunk(chunkIdx, part); err != nil {
+			return nil, err
+		}
+	}
+
+	c, err := p.attestationChallenge(w.ctx, challenge, a, values)
+	if err != nil {
+		return nil, err
+	}
+
+	response := func(k, x *big.Int) (*big.Int, error) {
+		z, err := big.NewInt()
+		if err != nil {
+			return nil, err
+		}
+		z.SetConstantTime()
+
+		if err := p.field.Mul(w.ctx, z, c, x); err != nil {
+			return nil, err
+		}
+
+		if err := p.field.Add(w.ctx, z, z, k); err != nil {
+			return nil, err
+		}
+
+		return z, nil
+	}
+
+	for chunkIdx, part := range share.Parts {
+		a.Responses[chunkIdx].SShare, err = response(nonces[chunkIdx].SShare, part.SShare)
+		if err != nil {
+			return nil, err
+		}
+
+		a.Responses[chunkIdx].TShare, err = response(nonces[chunkIdx].TShare, part.TShare)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// randomPart returns a pair of random values in the range [0, q).
+func (p *Pedersen) randomPart() (SecretPart, error) {
+	var part SecretPart
+
+	for _, x := range []**big.Int{&part.SShare, &part.TShare} {
+		v, err := big.NewInt()
+		if err != nil {
+			return SecretPart{}, err
+		}
+		v.SetConstantTime()
+
+		if err := v.RandRange(p.group.Q); err != nil {
+			return SecretPart{}, err
+		}
+
+		*x = v
+	}
+
+	return part, nil
+}
+
+// CheckAttestation verifies that the attestation proves the knowledge of the
+// secret parts of the share of the shareholder, for the provided challenge nonce.
+func (p *Pedersen) CheckAttestation(a *Attestation, transcript *Transcript, challenge []byte) error {
+	if a == nil {
+		return ErrNilAttestation
+	}
+
+	if transcript == nil {
+		return ErrNilTranscript
+	}
+
+	if len(challenge) < minAttestationChallengeSizeBytes {
+		return ErrInvalidChallenge
+	}
+
+	if a.Index < 0 || a.Index >= p.parts {
+		return ErrInvalidShareholder
+	}
+
+	if err := p.validateAbscissa(a.Abscissa); err != nil {
+		return err
+	}
+
+	shares := []Share{a.share()}
+
+	commitments, err := transcript.commitments(shares)
+	if err != nil {
+		return err
+	}
+
+	if len(a.Nonces) != len(commitments) || len(a.Responses) != len(commitments) {
+		return ErrWrongSharesLen
+	}
+
+	for chunkIdx := range commitments {
+		if len(commitments[chunkIdx]) != p.threshold {
+			return ErrInsufficientCommitments
+		}
+
+		if err := p.validatePart(a.Responses[chunkIdx]); err != nil {
+			return err
+		}
+	}
+
+	if err := p.validateTranscript(shares, transcript); err != nil {
+		return err
+	}
+
+	if err := p.validateCommitments(commitments); err != nil {
+		return err
+	}
+
+	if err := p.validateCommitments([][]*big.Int{a.Nonces}); err != nil {
+		return err
+	}
+
+	w, err := p.pool.get()
+	if err != nil {
+		return err
+	}
+	defer p.pool.put(w)
+
+	vandermondeAbscissa, err := p.vandermondeAbscissa(w.ctx, a.Abscissa)
+	if err != nil {
+		return err
+	}
+
+	values := make([]*big.Int, len(commitments))
+
+	for chunkIdx := range commitments {
+		values[chunkIdx], err = big.NewInt()
+		if err != nil {
+			return err
+		}
+
+		w.ctx.Attach()
+		err = p.evalCommitments(w.mont, w.ctx, values[chunkIdx], vandermondeAbscissa, commitments[chunkIdx])
+		w.ctx.Detach()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	c, err := p.attestationChallenge(w.ctx, challenge, a, values)
+	if err != nil {
+		return err
+	}
+
+	w.ctx.Attach()
+	defer w.ctx.Detach()
+
+	rhs, err := w.ctx.GetInt()
+	if err != nil {
+		return err
+	}
+
+	// g^z_s * h^z_t = A * C^c
+	for chunkIdx, value := range values {
+		if err := rhs.ModExpMont(w.mont, w.ctx, value, c, p.group.P); err != nil {
+			return err
+		}
+
+		if err := rhs.ModMul(w.ctx, rhs, a.Nonces[chunkIdx], p.group.P); err != nil {
+			return err
+		}
+
+		lhs, err := p.commit(w.mont, w.ctx, a.Responses[chunkIdx].SShare, a.Responses[chunkIdx].TShare)
+		if err != nil {
+			return err
+		}
+
+		if lhs.Cmp(rhs) != 0 {
+			return ErrInvalidAttestation
+		}
+	}
+
+	return nil
+}
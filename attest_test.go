@@ -0,0 +1,126 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPedersenAttestation(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+	secret := []byte("a secret that spans several chunks")
+
+	for _, options := range [][]pedersen.Option{
+		{pedersen.CyclicGroup(group)},
+		{pedersen.CyclicGroup(group), pedersen.MerkleCommitments()},
+		{pedersen.CyclicGroup(group), pedersen.ConstantTime()},
+	} {
+		p, err := pedersen.NewPedersen(5, 3, options...)
+		require.NoError(t, err)
+		defer p.Close()
+
+		shares, err := p.Split(secret, nil)
+		require.NoError(t, err)
+
+		transcript := shares.Transcript()
+		if p.IsMerkle() {
+			transcript.Commitments = nil
+			transcript.Bindings = nil
+		}
+
+		challenge, err := pedersen.NewAttestationChallenge()
+		require.NoError(t, err)
+		require.Len(t, challenge, pedersen.AttestationChallengeSizeBytes)
+
+		for i := range shares.Parts {
+			share, err := shares.Shareholder(i)
+			require.NoError(t, err)
+
+			attestation, err := p.Attest(share, transcript, challenge)
+			require.NoError(t, err)
+			require.Len(t, attestation.Nonces, len(shares.Commitments))
+
+			require.NoError(t, p.CheckAttestation(attestation, transcript, challenge))
+		}
+	}
+}
+
+func TestPedersenAttestationInvalid(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+	defer p.Close()
+
+	secret := []byte("a secret that spans several chunks")
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+
+	otherShares, err := p.Split(secret, shares.Abscissae)
+	require.NoError(t, err)
+
+	transcript := shares.Transcript()
+
+	challenge, err := pedersen.NewAttestationChallenge()
+	require.NoError(t, err)
+
+	share, err := shares.Shareholder(1)
+	require.NoError(t, err)
+
+	_, err = p.Attest(share, transcript, challenge[:8])
+	require.ErrorIs(t, err, pedersen.ErrInvalidChallenge)
+
+	// a share that does not match the commitments cannot be attested
+	wrong, err := otherShares.Shareholder(1)
+	require.NoError(t, err)
+	wrong.SplitID = shares.SplitID
+
+	_, err = p.Attest(wrong, transcript, challenge)
+	require.ErrorIs(t, err, pedersen.ErrWrongSecretPart)
+
+	attestation, err := p.Attest(share, transcript, challenge)
+	require.NoError(t, err)
+
+	// an attestation cannot be replayed for another challenge
+	otherChallenge, err := pedersen.NewAttestationChallenge()
+	require.NoError(t, err)
+	require.ErrorIs(t, p.CheckAttestation(attestation, transcript, otherChallenge), pedersen.ErrInvalidAttestation)
+
+	// an attestation cannot be claimed by another shareholder
+	claimed := *attestation
+	claimed.Index = 2
+	claimed.Abscissa = shares.Abscissae[2]
+	require.ErrorIs(t, p.CheckAttestation(&claimed, transcript, challenge), pedersen.ErrInvalidAttestation)
+
+	// an attestation is bound to the split
+	require.ErrorIs(t, p.CheckAttestation(attestation, otherShares.Transcript(), challenge), pedersen.ErrSplitMismatch)
+
+	tampered := *attestation
+	tampered.Responses = append([]pedersen.SecretPart(nil), attestation.Responses...)
+	tampered.Responses[0] = pedersen.SecretPart{
+		SShare: attestation.Responses[0].TShare,
+		TShare: attestation.Responses[0].SShare,
+	}
+	require.ErrorIs(t, p.CheckAttestation(&tampered, transcript, challenge), pedersen.ErrInvalidAttestation)
+
+	tampered = *attestation
+	tampered.Nonces = attestation.Nonces[1:]
+	require.ErrorIs(t, p.CheckAttestation(&tampered, transcript, challenge), pedersen.ErrWrongSharesLen)
+
+	tampered = *attestation
+	tampered.Nonces = append([]*big.Int(nil), attestation.Nonces...)
+	tampered.Nonces[0] = group.P
+	require.ErrorIs(t, p.CheckAttestation(&tampered, transcript, challenge), pedersen.ErrInvalidCommitment)
+
+	require.ErrorIs(t, p.CheckAttestation(nil, transcript, challenge), pedersen.ErrNilAttestation)
+}
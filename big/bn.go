@@ -101,6 +101,20 @@ func (i *Int) SetConstantTime() *Int {
 	return i
 }
 
+// IsConstantTime reports whether i carries the constant-time flag set by
+// [Int.SetConstantTime].
+func (i *Int) IsConstantTime() bool {
+	if i.bn == nil {
+		return false
+	}
+
+	if isGeq11() {
+		return C.go_openssl_BN_get_flags(i.bn, C.GO_BN_FLG_CONSTTIME) != 0
+	}
+
+	return C.legacy_1_0_BN_get_flags(i.bn, C.GO_BN_FLG_CONSTTIME) != 0
+}
+
 // String returns the decimal representation of i.
 func (i *Int) String() string {
 	return C.GoString(C.go_openssl_BN_bn2dec(i.bn))
@@ -307,6 +321,86 @@ func (z *Int) ModMul(ctx *IntContext, x, y, m *Int) error {
 	return nil
 }
 
+// NNMod sets z to the nonnegative remainder of x respective to modulus m (z=x mod m, 0<=z<|m|).
+// ctx is a previously allocated IntContext used for temporary variables.
+func (z *Int) NNMod(ctx *IntContext, x, m *Int) error {
+	err := z.init()
+	if err != nil {
+		return err
+	}
+
+	ret := C.go_openssl_BN_nnmod(z.bn, x.bn, m.bn, ctx.ctx)
+	if ret != 1 {
+		return newOpenSSLError("BN_nnmod")
+	}
+
+	return nil
+}
+
+// ModAdd adds x to y and finds the nonnegative remainder respective to modulus m (z=(x+y) mod m).
+// ctx is a previously allocated IntContext used for temporary variables.
+func (z *Int) ModAdd(ctx *IntContext, x, y, m *Int) error {
+	err := z.init()
+	if err != nil {
+		return err
+	}
+
+	ret := C.go_openssl_BN_mod_add(z.bn, x.bn, y.bn, m.bn, ctx.ctx)
+	if ret != 1 {
+		return newOpenSSLError("BN_mod_add")
+	}
+
+	return nil
+}
+
+// ModSub subtracts y from x and finds the nonnegative remainder respective to modulus m (z=(x-y) mod m).
+// ctx is a previously allocated IntContext used for temporary variables.
+func (z *Int) ModSub(ctx *IntContext, x, y, m *Int) error {
+	err := z.init()
+	if err != nil {
+		return err
+	}
+
+	ret := C.go_openssl_BN_mod_sub(z.bn, x.bn, y.bn, m.bn, ctx.ctx)
+	if ret != 1 {
+		return newOpenSSLError("BN_mod_sub")
+	}
+
+	return nil
+}
+
+// ModSqr squares x and finds the nonnegative remainder respective to modulus m (z=(x^2) mod m).
+// ctx is a previously allocated IntContext used for temporary variables.
+func (z *Int) ModSqr(ctx *IntContext, x, m *Int) error {
+	err := z.init()
+	if err != nil {
+		return err
+	}
+
+	ret := C.go_openssl_BN_mod_sqr(z.bn, x.bn, m.bn, ctx.ctx)
+	if ret != 1 {
+		return newOpenSSLError("BN_mod_sqr")
+	}
+
+	return nil
+}
+
+// ModLsh shifts x left by n bits and finds the nonnegative remainder respective to modulus m (z=(x*2^n) mod m).
+// ctx is a previously allocated IntContext used for temporary variables.
+func (z *Int) ModLsh(ctx *IntContext, x *Int, n uint, m *Int) error {
+	err := z.init()
+	if err != nil {
+		return err
+	}
+
+	ret := C.go_openssl_BN_mod_lshift(z.bn, x.bn, C.int(n), m.bn, ctx.ctx)
+	if ret != 1 {
+		return newOpenSSLError("BN_mod_lshift")
+	}
+
+	return nil
+}
+
 // ModMulMontgomery implement Montgomery multiplication.
 // It computes Mont(x,y):=x*y*R^-1 and places the result in z.
 // ctx is a previously allocated IntContext used for temporary variables.
@@ -324,6 +418,30 @@ func (z *Int) ModMulMontgomery(mont *MontgomeryContext, ctx *IntContext, x, y *I
 	return nil
 }
 
+// ToMontgomery sets z to the Montgomery form of x (z=x*R mod m), where m is the modulus
+// of the Montgomery context. x must be nonnegative and smaller than m.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (z *Int) ToMontgomery(mont *MontgomeryContext, ctx *IntContext, x *Int) error {
+	return z.ModMulMontgomery(mont, ctx, x, mont.rr)
+}
+
+// FromMontgomery sets z to the value of x converted from the Montgomery form
+// (z=x*R^-1 mod m), where m is the modulus of the Montgomery context.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (z *Int) FromMontgomery(mont *MontgomeryContext, ctx *IntContext, x *Int) error {
+	err := z.init()
+	if err != nil {
+		return err
+	}
+
+	ret := C.go_openssl_BN_from_montgomery(z.bn, x.bn, mont.ctx, ctx.ctx)
+	if ret != 1 {
+		return newOpenSSLError("BN_from_montgomery")
+	}
+
+	return nil
+}
+
 // Div divides z by y and places the result in z.
 // For division by powers of 2, use [Rsh].
 // ctx is a previously allocated IntContext used for temporary variables.
@@ -409,6 +527,57 @@ func (z *Int) ModExpMont(mont *MontgomeryContext, ctx *IntContext, x, y, m *Int)
 	return nil
 }
 
+// ModMultiExp computes the product of every bases[i] raised to the exps[i]-th power
+// modulo m (z=Π bases[i]^exps[i] % m).
+// It uses simultaneous exponentiation (Straus' algorithm), so the squarings are shared
+// among all the bases: this is considerably faster than separate calls to [ModExp]
+// when many bases are raised to small exponents.
+// Exponents must be nonnegative.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (z *Int) ModMultiExp(ctx *IntContext, bases, exps []*Int, m *Int) error {
+	err := z.init()
+	if err != nil {
+		return err
+	}
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	acc, err := ctx.GetInt()
+	if err != nil {
+		return err
+	}
+
+	if err := acc.SetUInt64(1); err != nil {
+		return err
+	}
+
+	bits := 0
+	for _, exp := range exps {
+		if expBits := exp.BitLen(); expBits > bits {
+			bits = expBits
+		}
+	}
+
+	for bit := bits - 1; bit >= 0; bit-- {
+		if err := acc.ModMul(ctx, acc, acc, m); err != nil {
+			return err
+		}
+
+		for i, exp := range exps {
+			if exp.Bit(bit) == 0 {
+				continue
+			}
+
+			if err := acc.ModMul(ctx, acc, bases[i], m); err != nil {
+				return err
+			}
+		}
+	}
+
+	return z.Set(acc)
+}
+
 // Mod sets z to the modulus x%y for y != 0.
 // ctx is a previously allocated IntContext used for temporary variables.
 func (z *Int) Mod(ctx *IntContext, x, y *Int) error {
@@ -441,6 +610,19 @@ func (z *Int) ModInverse(ctx *IntContext, g, n *Int) error {
 	return nil
 }
 
+// Kronecker returns the Kronecker symbol (x/y), which is the Legendre symbol
+// when y is an odd prime, i.e. 1 if x is a nonzero quadratic residue modulo y,
+// -1 if it is a non-residue and 0 if y divides x.
+// ctx is a previously allocated IntContext used for temporary variables.
+func Kronecker(ctx *IntContext, x, y *Int) (int, error) {
+	ret := C.go_openssl_BN_kronecker(x.bn, y.bn, ctx.ctx)
+	if ret == -2 {
+		return 0, newOpenSSLError("BN_kronecker")
+	}
+
+	return int(ret), nil
+}
+
 // BitLen returns the length of the absolute value of z in bits. The bit length of 0 is 0.
 func (z *Int) BitLen() int {
 	if z.bn == nil {
@@ -450,6 +632,15 @@ func (z *Int) BitLen() int {
 	return int(C.go_openssl_BN_num_bits(z.bn))
 }
 
+// Bit returns the value of the i'th bit of the absolute value of z.
+func (z *Int) Bit(i int) uint {
+	if z.bn == nil {
+		return 0
+	}
+
+	return uint(C.go_openssl_BN_is_bit_set(z.bn, C.int(i)))
+}
+
 // BytesLen returns the size of z in bytes.
 func (z *Int) BytesLen() int {
 	if z.bn == nil {
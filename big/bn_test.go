@@ -124,3 +124,91 @@ func TestExpMontValid(t *testing.T) {
 		require.Equal(t, 0, res.Cmp(expected))
 	})
 }
+
+func TestModMultiExpValid(t *testing.T) {
+	t.Run("valid multi exp bn", func(t *testing.T) {
+		ctx, err := big.NewIntContext()
+		require.NoError(t, err)
+		defer ctx.Destroy()
+
+		m, err := big.NewInt()
+		require.NoError(t, err)
+		err = m.SetDecString("17634709279010524619")
+		require.NoError(t, err)
+
+		values := []uint64{3, 5, 7, 11, 1 << 40, 0}
+		bases := make([]*big.Int, len(values))
+		exps := make([]*big.Int, len(values))
+
+		expected, err := big.NewInt()
+		require.NoError(t, err)
+		err = expected.SetUInt64(1)
+		require.NoError(t, err)
+
+		for i, v := range values {
+			bases[i], err = big.NewInt()
+			require.NoError(t, err)
+			err = bases[i].SetUInt64(v + 2)
+			require.NoError(t, err)
+
+			exps[i], err = big.NewInt()
+			require.NoError(t, err)
+			err = exps[i].SetUInt64(v)
+			require.NoError(t, err)
+
+			term, err := big.NewInt()
+			require.NoError(t, err)
+			err = term.ModExp(ctx, bases[i], exps[i], m)
+			require.NoError(t, err)
+
+			err = expected.ModMul(ctx, expected, term, m)
+			require.NoError(t, err)
+		}
+
+		res, err := big.NewInt()
+		require.NoError(t, err)
+
+		err = res.ModMultiExp(ctx, bases, exps, m)
+		require.NoError(t, err)
+
+		require.Equal(t, 0, res.Cmp(expected))
+	})
+}
+
+func TestKronecker(t *testing.T) {
+	ctx, err := big.NewIntContext()
+	require.NoError(t, err)
+	defer ctx.Destroy()
+
+	p := newTestInt(t, 23)
+	residues := map[uint64]bool{1: true, 2: true, 3: true, 4: true, 6: true, 8: true, 9: true, 12: true, 13: true, 16: true, 18: true}
+
+	for x := uint64(0); x < 23; x++ {
+		symbol, err := big.Kronecker(ctx, newTestInt(t, x), p)
+		require.NoError(t, err)
+
+		switch {
+		case x == 0:
+			require.Equal(t, 0, symbol)
+		case residues[x]:
+			require.Equal(t, 1, symbol, "x=%d", x)
+		default:
+			require.Equal(t, -1, symbol, "x=%d", x)
+		}
+	}
+}
+
+func TestConstantTimeFlag(t *testing.T) {
+	a, err := big.NewInt()
+	require.NoError(t, err)
+	require.False(t, a.IsConstantTime())
+
+	a.SetConstantTime()
+	require.True(t, a.IsConstantTime())
+
+	// the flag is kept across assignments
+	require.NoError(t, a.SetUInt64(7))
+	require.True(t, a.IsConstantTime())
+
+	require.False(t, new(big.Int).IsConstantTime())
+}
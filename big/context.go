@@ -10,6 +10,7 @@ package big
 import "C"
 import (
 	"runtime"
+	"unsafe"
 )
 
 // A IntContext is a structure that holds [Int] temporary variables
@@ -24,8 +25,13 @@ type IntContext struct {
 	ctx *C.GO_BN_CTX
 }
 
+// A MontgomeryContext holds the precomputed values used for Montgomery
+// multiplications modulo a given modulus.
 type MontgomeryContext struct {
 	ctx *C.GO_BN_MONT_CTX
+
+	// rr is R^2 mod m, which is used for converting values to the Montgomery form.
+	rr *Int
 }
 
 func finalizeIntContext(bnCtx *IntContext) {
@@ -128,12 +134,37 @@ func NewMontgomeryContext() (*MontgomeryContext, error) {
 	return wrapMontgomeryContext(ctx), nil
 }
 
+// Set initializes the Montgomery context for the modulus m, that must be odd.
+// ctx is a previously allocated IntContext used for temporary variables.
 func (c *MontgomeryContext) Set(m *Int, ctx *IntContext) error {
 	ret := C.go_openssl_BN_MONT_CTX_set(c.ctx, m.bn, ctx.ctx)
 	if ret != 1 {
 		return newOpenSSLError("BN_MONT_CTX_set")
 	}
 
+	// R = 2^ri where ri is the bit length of m rounded up to a multiple of the word size
+	wordBits := int(unsafe.Sizeof(C.GO_BN_ULONG(0))) * 8
+	ri := (m.BitLen() + wordBits - 1) / wordBits * wordBits
+
+	rr, err := NewInt()
+	if err != nil {
+		return err
+	}
+
+	if err := rr.SetUInt64(1); err != nil {
+		return err
+	}
+
+	if err := rr.Lsh(rr, uint(2*ri)); err != nil {
+		return err
+	}
+
+	if err := rr.Mod(ctx, rr, m); err != nil {
+		return err
+	}
+
+	c.rr = rr
+
 	return nil
 }
 
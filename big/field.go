@@ -0,0 +1,125 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+//go:build cgo
+// +build cgo
+
+package big
+
+// A Field performs modular arithmetic over the integers modulo a fixed modulus.
+// Every operation reduces its result into [0, modulus), so intermediate values
+// never grow beyond the size of the modulus.
+// The modulus and every result are flagged with [Int.SetConstantTime], so
+// that OpenSSL selects its constant-time code paths where they are available.
+// A Field is safe for concurrent use, while each goroutine must use its own
+// IntContext.
+type Field struct {
+	modulus *Int
+	zero    *Int
+}
+
+// NewField returns a Field for the provided modulus, that must be positive.
+// The modulus is copied, so later changes to it do not affect the Field.
+func NewField(modulus *Int) (*Field, error) {
+	m, err := NewInt()
+	if err != nil {
+		return nil, err
+	}
+	m.SetConstantTime()
+
+	if err := m.Set(modulus); err != nil {
+		return nil, err
+	}
+
+	zero, err := NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := zero.SetUInt64(0); err != nil {
+		return nil, err
+	}
+
+	return &Field{
+		modulus: m,
+		zero:    zero,
+	}, nil
+}
+
+// Modulus returns the modulus of the field.
+// The returned value must not be modified.
+func (f *Field) Modulus() *Int {
+	return f.modulus
+}
+
+// Contains reports whether x is a reduced element of the field, i.e. 0 <= x < m.
+func (f *Field) Contains(x *Int) bool {
+	return x.Cmp(f.zero) >= 0 && x.Cmp(f.modulus) < 0
+}
+
+// Reduce sets z to the nonnegative remainder of x respective to the modulus.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) Reduce(ctx *IntContext, z, x *Int) error {
+	return z.SetConstantTime().NNMod(ctx, x, f.modulus)
+}
+
+// Add sets z to (x+y) mod m.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) Add(ctx *IntContext, z, x, y *Int) error {
+	return z.SetConstantTime().ModAdd(ctx, x, y, f.modulus)
+}
+
+// Sub sets z to (x-y) mod m.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) Sub(ctx *IntContext, z, x, y *Int) error {
+	return z.SetConstantTime().ModSub(ctx, x, y, f.modulus)
+}
+
+// Neg sets z to (-x) mod m.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) Neg(ctx *IntContext, z, x *Int) error {
+	return z.SetConstantTime().ModSub(ctx, f.zero, x, f.modulus)
+}
+
+// Mul sets z to (x*y) mod m.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) Mul(ctx *IntContext, z, x, y *Int) error {
+	return z.SetConstantTime().ModMul(ctx, x, y, f.modulus)
+}
+
+// Sqr sets z to (x^2) mod m.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) Sqr(ctx *IntContext, z, x *Int) error {
+	return z.SetConstantTime().ModSqr(ctx, x, f.modulus)
+}
+
+// Lsh sets z to (x*2^n) mod m.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) Lsh(ctx *IntContext, z, x *Int, n uint) error {
+	return z.SetConstantTime().ModLsh(ctx, x, n, f.modulus)
+}
+
+// Inverse sets z to the multiplicative inverse of x modulo m.
+// An error is returned if x is not invertible.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) Inverse(ctx *IntContext, z, x *Int) error {
+	return z.SetConstantTime().ModInverse(ctx, x, f.modulus)
+}
+
+// NewElement returns a new Int set to x mod m.
+// ctx is a previously allocated IntContext used for temporary variables.
+func (f *Field) NewElement(ctx *IntContext, x *Int) (*Int, error) {
+	z, err := NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.Reduce(ctx, z, x); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package big_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen/big"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInt(t *testing.T, x uint64) *big.Int {
+	t.Helper()
+
+	z, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, z.SetUInt64(x))
+
+	return z
+}
+
+func TestFieldValid(t *testing.T) {
+	const m = 97
+
+	ctx, err := big.NewIntContext()
+	require.NoError(t, err)
+	defer ctx.Destroy()
+
+	field, err := big.NewField(newTestInt(t, m))
+	require.NoError(t, err)
+	require.Equal(t, uint64(m), field.Modulus().Uint64())
+
+	z, err := big.NewInt()
+	require.NoError(t, err)
+
+	for _, values := range [][2]uint64{{0, 0}, {1, 96}, {50, 60}, {96, 96}, {13, 200}, {500, 3}} {
+		x, y := values[0], values[1]
+		xInt, yInt := newTestInt(t, x), newTestInt(t, y)
+
+		require.NoError(t, field.Reduce(ctx, z, xInt))
+		require.Equal(t, x%m, z.Uint64())
+
+		require.NoError(t, field.Add(ctx, z, xInt, yInt))
+		require.Equal(t, (x+y)%m, z.Uint64())
+
+		require.NoError(t, field.Sub(ctx, z, xInt, yInt))
+		require.Equal(t, (x%m+m-y%m)%m, z.Uint64())
+
+		require.NoError(t, field.Neg(ctx, z, xInt))
+		require.Equal(t, (m-x%m)%m, z.Uint64())
+
+		require.NoError(t, field.Mul(ctx, z, xInt, yInt))
+		require.Equal(t, (x*y)%m, z.Uint64())
+
+		require.NoError(t, field.Sqr(ctx, z, xInt))
+		require.Equal(t, (x*x)%m, z.Uint64())
+
+		require.NoError(t, field.Lsh(ctx, z, xInt, 5))
+		require.Equal(t, (x<<5)%m, z.Uint64())
+
+		if x%m != 0 {
+			require.NoError(t, field.Inverse(ctx, z, xInt))
+			require.Equal(t, uint64(1), (z.Uint64()*x)%m)
+		}
+	}
+
+	// results can alias the operands
+	x := newTestInt(t, 90)
+	require.NoError(t, field.Add(ctx, x, x, x))
+	require.Equal(t, uint64(83), x.Uint64())
+
+	require.True(t, field.Contains(newTestInt(t, 0)))
+	require.True(t, field.Contains(newTestInt(t, m-1)))
+	require.False(t, field.Contains(newTestInt(t, m)))
+
+	negative, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, negative.Sub(newTestInt(t, 0), big.One()))
+	require.False(t, field.Contains(negative))
+
+	element, err := field.NewElement(ctx, newTestInt(t, 1000))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000%m), element.Uint64())
+}
+
+func TestFieldInvalid(t *testing.T) {
+	ctx, err := big.NewIntContext()
+	require.NoError(t, err)
+	defer ctx.Destroy()
+
+	field, err := big.NewField(newTestInt(t, 91))
+	require.NoError(t, err)
+
+	z, err := big.NewInt()
+	require.NoError(t, err)
+
+	// 7 divides 91, so it has no inverse
+	require.Error(t, field.Inverse(ctx, z, newTestInt(t, 7)))
+	require.Error(t, field.Inverse(ctx, z, newTestInt(t, 0)))
+}
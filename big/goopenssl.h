@@ -60,6 +60,7 @@ FOR_ALL_OPENSSL_FUNCTIONS
 #undef DEFINEFUNC_RENAMED_3_0
 
 void legacy_1_0_BN_set_flags(GO_BIGNUM *arg0, int arg1);
+int legacy_1_0_BN_get_flags(const GO_BIGNUM *arg0, int arg1);
 
 int go_openssl_BN_num_bytes(const GO_BIGNUM *a);
 int go_openssl_BN_mod(GO_BIGNUM *rem, const GO_BIGNUM *a, const GO_BIGNUM *m, GO_BN_CTX *ctx);
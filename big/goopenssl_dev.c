@@ -107,6 +107,11 @@ void legacy_1_0_BN_set_flags(GO_BIGNUM *arg0, int arg1)
 	BN_set_flags(arg0, arg1);
 }
 
+int legacy_1_0_BN_get_flags(const GO_BIGNUM *arg0, int arg1)
+{
+	return BN_get_flags(arg0, arg1);
+}
+
 int go_openssl_BN_num_bytes(const GO_BIGNUM *a)
 {
 	return (go_openssl_BN_num_bits(a) + 7) / 8;
@@ -146,12 +146,19 @@ typedef struct bn_gencb_st GO_BN_GENCB;
 	DEFINEFUNC(int, BN_sub, (GO_BIGNUM * r, const GO_BIGNUM *a, const GO_BIGNUM *b), (r, a, b))                                                                                                                          \
 	DEFINEFUNC(int, BN_mul, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, const GO_BIGNUM *arg2, GO_BN_CTX *arg3), (arg0, arg1, arg2, arg3))                                                                                 \
 	DEFINEFUNC(int, BN_mod_mul, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, const GO_BIGNUM *arg2, const GO_BIGNUM *arg3, GO_BN_CTX *arg4), (arg0, arg1, arg2, arg3, arg4))                                                \
+	DEFINEFUNC(int, BN_nnmod, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, const GO_BIGNUM *arg2, GO_BN_CTX *arg3), (arg0, arg1, arg2, arg3))                                                                               \
+	DEFINEFUNC(int, BN_mod_add, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, const GO_BIGNUM *arg2, const GO_BIGNUM *arg3, GO_BN_CTX *arg4), (arg0, arg1, arg2, arg3, arg4))                                                \
+	DEFINEFUNC(int, BN_mod_sub, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, const GO_BIGNUM *arg2, const GO_BIGNUM *arg3, GO_BN_CTX *arg4), (arg0, arg1, arg2, arg3, arg4))                                                \
+	DEFINEFUNC(int, BN_mod_sqr, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, const GO_BIGNUM *arg2, GO_BN_CTX *arg3), (arg0, arg1, arg2, arg3))                                                                             \
+	DEFINEFUNC(int, BN_mod_lshift, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, int arg2, const GO_BIGNUM *arg3, GO_BN_CTX *arg4), (arg0, arg1, arg2, arg3, arg4))                                                          \
 	DEFINEFUNC(int, BN_mod_mul_montgomery, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, const GO_BIGNUM *arg2, GO_BN_MONT_CTX *arg3, GO_BN_CTX *arg4), (arg0, arg1, arg2, arg3, arg4))                                      \
+	DEFINEFUNC(int, BN_from_montgomery, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, GO_BN_MONT_CTX *arg2, GO_BN_CTX *arg3), (arg0, arg1, arg2, arg3))                                                                     \
 	DEFINEFUNC(int, BN_div, (GO_BIGNUM * dv, GO_BIGNUM * rem, const GO_BIGNUM *m, const GO_BIGNUM *d, GO_BN_CTX *ctx), (dv, rem, m, d, ctx))                                                                             \
 	DEFINEFUNC(int, BN_exp, (GO_BIGNUM * r, GO_BIGNUM * a, GO_BIGNUM * p, GO_BN_CTX * ctx), (r, a, p, ctx))                                                                                                              \
 	DEFINEFUNC(int, BN_mod_exp, (GO_BIGNUM * r, const GO_BIGNUM *a, const GO_BIGNUM *p, const GO_BIGNUM *m, GO_BN_CTX *ctx), (r, a, p, m, ctx))                                                                          \
 	DEFINEFUNC(int, BN_mod_exp_mont, (GO_BIGNUM * r, const GO_BIGNUM *a, const GO_BIGNUM *p, const GO_BIGNUM *m, GO_BN_CTX *ctx, GO_BN_MONT_CTX *m_ctx), (r, a, p, m, ctx, m_ctx))                                       \
 	DEFINEFUNC(GO_BIGNUM *, BN_mod_inverse, (GO_BIGNUM * ret, const GO_BIGNUM *a, const GO_BIGNUM *n, GO_BN_CTX *ctx), (ret, a, n, ctx))                                                                                 \
+	DEFINEFUNC(int, BN_kronecker, (const GO_BIGNUM *arg0, const GO_BIGNUM *arg1, GO_BN_CTX *arg2), (arg0, arg1, arg2))                                                                                                   \
 	DEFINEFUNC(int, BN_num_bits, (const GO_BIGNUM *arg0), (arg0))                                                                                                                                                        \
 	DEFINEFUNC(GO_BIGNUM *, BN_bin2bn, (const unsigned char *arg0, int arg1, GO_BIGNUM *arg2), (arg0, arg1, arg2))                                                                                                       \
 	DEFINEFUNC(int, BN_dec2bn, (GO_BIGNUM * *arg0, const char *arg1), (arg0, arg1))                                                                                                                                      \
@@ -162,6 +169,7 @@ typedef struct bn_gencb_st GO_BN_GENCB;
 	DEFINEFUNC(int, BN_lshift, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, int arg2), (arg0, arg1, arg2))                                                                                                                  \
 	DEFINEFUNC(int, BN_rshift, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1, int arg2), (arg0, arg1, arg2))                                                                                                                  \
 	DEFINEFUNC(GO_BN_ULONG, BN_get_word, (const GO_BIGNUM *arg0), (arg0))                                                                                                                                                \
+	DEFINEFUNC(int, BN_is_bit_set, (const GO_BIGNUM *arg0, int arg1), (arg0, arg1))                                                                                                                                      \
 	DEFINEFUNC(GO_BIGNUM *, BN_copy, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1), (arg0, arg1))                                                                                                                            \
 	DEFINEFUNC(int, BN_rand_range, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1), (arg0, arg1))                                                                                                                              \
 	DEFINEFUNC(int, BN_cmp, (GO_BIGNUM * arg0, const GO_BIGNUM *arg1), (arg0, arg1))                                                                                                                                     \
@@ -174,6 +182,7 @@ typedef struct bn_gencb_st GO_BN_GENCB;
 	DEFINEFUNC(void, BN_CTX_end, (GO_BN_CTX * arg0), (arg0))                                                                                                                                                             \
 	DEFINEFUNC(GO_BIGNUM *, BN_CTX_get, (GO_BN_CTX * arg0), (arg0))                                                                                                                                                      \
 	DEFINEFUNC_1_1(void, BN_set_flags, (GO_BIGNUM * arg0, int arg1), (arg0, arg1), )                                                                                                                                     \
+	DEFINEFUNC_1_1(int, BN_get_flags, (const GO_BIGNUM *arg0, int arg1), (arg0, arg1), 0)                                                                                                                                \
 	DEFINEFUNC(GO_BN_MONT_CTX *, BN_MONT_CTX_new, (void), ())                                                                                                                                                            \
 	DEFINEFUNC(void, BN_MONT_CTX_free, (GO_BN_MONT_CTX * arg0), (arg0))                                                                                                                                                  \
 	DEFINEFUNC(int, BN_MONT_CTX_set, (GO_BN_MONT_CTX * arg0, const GO_BIGNUM *arg1, GO_BN_CTX *arg2), (arg0, arg1, arg2))
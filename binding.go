@@ -0,0 +1,245 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+const (
+	splitIDSizeBytes = 16
+
+	chunkBindingDomain = "pedersen-chunk-binding-v1"
+)
+
+var (
+	ErrNilTranscript     = errors.New("transcript cannot be nil")
+	ErrInvalidBinding    = errors.New("commitments are not bound to the split and to the chunk position")
+	ErrSplitMismatch     = errors.New("shares and commitments belong to different splits")
+	ErrUnboundTranscript = errors.New("transcript carries no binding of the commitments")
+)
+
+// Transcript represents the public data of a split, which is distributed to
+// every shareholder: the commitments of every chunk, bound to the split
+// identifier and to the position of the chunk.
+// For shares obtained with the [MerkleCommitments] option the transcript may
+// carry only the split identifier and the Merkle root, in which case the
+// commitments are taken from the proofs of the shares.
+type Transcript struct {
+	// SplitID is the unique identifier of the split.
+	SplitID []byte
+
+	// Commitments is the matrix of commitments, as in [Shares].
+	Commitments [][]*big.Int
+
+	// Bindings is the vector of hashes binding every commitments vector to the split
+	// and to the chunk position, so Bindings[chunkIdx] binds Commitments[chunkIdx]
+	// to SplitID and chunkIdx.
+	Bindings [][]byte
+
+	// Root is the root of the Merkle tree of the commitments, as in [Shares].
+	Root []byte
+
+	// Encoding is the encoding of the secret into chunks, as in [Shares].
+	Encoding ChunkEncoding
+}
+
+// Transcript returns the transcript of the split the shares are obtained from.
+func (s *Shares) Transcript() *Transcript {
+	return &Transcript{
+		SplitID:     s.SplitID,
+		Commitments: s.Commitments,
+		Bindings:    s.Bindings,
+		Root:        s.Root,
+		Encoding:    s.Encoding,
+	}
+}
+
+// isLegacy reports whether the transcript has been produced without binding
+// the commitments, as done by previous versions.
+func (t *Transcript) isLegacy() bool {
+	return t.SplitID == nil && t.Bindings == nil && t.Root == nil
+}
+
+// isMerkle reports whether the transcript carries only the Merkle root in
+// place of the commitments matrix.
+func (t *Transcript) isMerkle() bool {
+	return t.Root != nil && t.Commitments == nil
+}
+
+// commitments returns the commitments matrix of the transcript.
+// When the transcript carries only the Merkle root, the matrix is assembled from
+// the proofs of the first share, which are authenticated by validateTranscript
+// together with the proofs of every other share.
+func (t *Transcript) commitments(shares []Share) ([][]*big.Int, error) {
+	if !t.isMerkle() {
+		return t.Commitments, nil
+	}
+
+	if len(shares) == 0 {
+		return nil, ErrInsufficientSharesParts
+	}
+
+	commitments := make([][]*big.Int, len(shares[0].Proofs))
+
+	for _, share := range shares {
+		if len(share.Proofs) != len(commitments) {
+			return nil, ErrWrongSharesLen
+		}
+	}
+
+	for chunkIdx := range commitments {
+		commitments[chunkIdx] = shares[0].Proofs[chunkIdx].Commitments
+	}
+
+	return commitments, nil
+}
+
+func newSplitID() ([]byte, error) {
+	splitID := make([]byte, splitIDSizeBytes)
+	if _, err := rand.Read(splitID); err != nil {
+		return nil, err
+	}
+
+	return splitID, nil
+}
+
+// chunkBinding computes the hash binding the commitments of a chunk to the split
+// identifier, to the chunk encoding and to the chunk position.
+func chunkBinding(splitID []byte, encoding ChunkEncoding, chunkIdx int, commitments []*big.Int) ([]byte, error) {
+	h := sha256.New()
+	buf := make([]byte, 8)
+
+	writeField := func(data []byte) {
+		binary.BigEndian.PutUint64(buf, uint64(len(data)))
+		h.Write(buf)
+		h.Write(data)
+	}
+
+	writeField([]byte(chunkBindingDomain))
+	writeField(splitID)
+
+	binary.BigEndian.PutUint64(buf, uint64(encoding))
+	h.Write(buf)
+
+	binary.BigEndian.PutUint64(buf, uint64(chunkIdx))
+	h.Write(buf)
+
+	binary.BigEndian.PutUint64(buf, uint64(len(commitments)))
+	h.Write(buf)
+
+	for _, commitment := range commitments {
+		data, err := commitment.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		writeField(data)
+	}
+
+	return h.Sum(nil), nil
+}
+
+// chunkBindings computes the bindings of every chunk commitments.
+func chunkBindings(splitID []byte, encoding ChunkEncoding, commitments [][]*big.Int) ([][]byte, error) {
+	bindings := make([][]byte, len(commitments))
+
+	for chunkIdx, chunkCommitments := range commitments {
+		binding, err := chunkBinding(splitID, encoding, chunkIdx, chunkCommitments)
+		if err != nil {
+			return nil, err
+		}
+
+		bindings[chunkIdx] = binding
+	}
+
+	return bindings, nil
+}
+
+// validateTranscript checks that the shares belong to the split of the transcript
+// and that every commitments vector is bound to its chunk position, so that chunks
+// that are reordered or that come from another split are rejected.
+// When the transcript carries the Merkle root, the bindings must lead to the root,
+// and when it carries only the root, the proofs of every share are checked instead.
+// Legacy transcripts, which carry neither the split identifier nor the bindings,
+// are rejected unless the LegacyTranscripts option is set, and are accepted only
+// together with shares that carry no split identifier.
+func (p *Pedersen) validateTranscript(shares []Share, t *Transcript) error {
+	if t.isLegacy() {
+		if !p.legacyTranscripts {
+			return ErrUnboundTranscript
+		}
+
+		for _, share := range shares {
+			if share.SplitID != nil {
+				return ErrSplitMismatch
+			}
+		}
+
+		return nil
+	}
+
+	if len(t.SplitID) == 0 {
+		return ErrInvalidBinding
+	}
+
+	for _, share := range shares {
+		if !bytes.Equal(share.SplitID, t.SplitID) {
+			return ErrSplitMismatch
+		}
+	}
+
+	if t.isMerkle() {
+		for _, share := range shares {
+			// the number of proofs is committed to by the root, and a share
+			// without proofs would leave the root unchecked
+			if len(share.Proofs) == 0 {
+				return ErrInvalidProof
+			}
+
+			for chunkIdx, proof := range share.Proofs {
+				if err := p.validateProof(chunkIdx, len(share.Proofs), proof, t); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if len(t.Bindings) != len(t.Commitments) {
+		return ErrInvalidBinding
+	}
+
+	for chunkIdx, commitments := range t.Commitments {
+		binding, err := chunkBinding(t.SplitID, t.Encoding, chunkIdx, commitments)
+		if err != nil {
+			return err
+		}
+
+		if subtle.ConstantTimeCompare(binding, t.Bindings[chunkIdx]) != 1 {
+			return ErrInvalidBinding
+		}
+	}
+
+	if t.Root != nil {
+		root, _ := merkleTree(t.Bindings)
+
+		if subtle.ConstantTimeCompare(root, t.Root) != 1 {
+			return ErrInvalidProof
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// swapChunks returns a copy of the shares whose first two chunks are swapped
+// both in the secret parts and in the commitments, while bindings are kept.
+func swapChunks(s *pedersen.Shares) *pedersen.Shares {
+	swapped := &pedersen.Shares{
+		Abscissae:   s.Abscissae,
+		Parts:       make([][]pedersen.SecretPart, len(s.Parts)),
+		Commitments: append([][]*big.Int(nil), s.Commitments...),
+		SplitID:     s.SplitID,
+		Bindings:    s.Bindings,
+		Encoding:    s.Encoding,
+	}
+
+	swapped.Commitments[0], swapped.Commitments[1] = swapped.Commitments[1], swapped.Commitments[0]
+
+	for i, parts := range s.Parts {
+		swapped.Parts[i] = append([]pedersen.SecretPart(nil), parts...)
+		swapped.Parts[i][0], swapped.Parts[i][1] = swapped.Parts[i][1], swapped.Parts[i][0]
+	}
+
+	return swapped
+}
+
+func TestPedersenBinding(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+
+	secret := []byte("a secret that spans several chunks")
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+	require.Greater(t, len(shares.Commitments), 1)
+	require.Len(t, shares.Bindings, len(shares.Commitments))
+
+	otherShares, err := p.Split(secret, shares.Abscissae)
+	require.NoError(t, err)
+	require.NotEqual(t, shares.SplitID, otherShares.SplitID)
+
+	t.Run("reordered chunks", func(t *testing.T) {
+		swapped := swapChunks(shares)
+
+		require.ErrorIs(t, p.VerifyShares(swapped), pedersen.ErrInvalidBinding)
+
+		_, err := p.Combine(swapped)
+		require.ErrorIs(t, err, pedersen.ErrInvalidBinding)
+	})
+
+	t.Run("reordered chunks without bindings", func(t *testing.T) {
+		legacy, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group), pedersen.Encoding(pedersen.LegacyEncoding))
+		require.NoError(t, err)
+
+		legacyShares, err := legacy.Split(secret, nil)
+		require.NoError(t, err)
+
+		swapped := swapChunks(legacyShares)
+		swapped.SplitID = nil
+		swapped.Bindings = nil
+
+		// legacy shares cannot detect the reordering
+		require.NoError(t, p.VerifyShares(swapped))
+
+		reconstructed, err := p.Combine(swapped)
+		require.NoError(t, err)
+		assert.NotEqual(t, secret, reconstructed)
+	})
+
+	t.Run("shares of a different split", func(t *testing.T) {
+		share, err := otherShares.Shareholder(0)
+		require.NoError(t, err)
+
+		subset, err := shares.Subset(1, 2)
+		require.NoError(t, err)
+
+		mixed := append(subset, share)
+
+		err = p.VerifyShareholders(mixed, shares.Transcript())
+		require.ErrorIs(t, err, pedersen.ErrSplitMismatch)
+
+		_, err = p.CombineShareholders(mixed, shares.Transcript())
+		require.ErrorIs(t, err, pedersen.ErrSplitMismatch)
+	})
+
+	t.Run("transcript of a different split", func(t *testing.T) {
+		subset, err := shares.Subset(0, 1, 2)
+		require.NoError(t, err)
+
+		_, err = p.CombineShareholders(subset, otherShares.Transcript())
+		require.ErrorIs(t, err, pedersen.ErrSplitMismatch)
+
+		forged := otherShares.Transcript()
+		forged.SplitID = shares.SplitID
+
+		_, err = p.CombineShareholders(subset, forged)
+		require.ErrorIs(t, err, pedersen.ErrInvalidBinding)
+	})
+
+	t.Run("missing bindings", func(t *testing.T) {
+		transcript := shares.Transcript()
+		transcript.Bindings = nil
+
+		require.ErrorIs(t, p.VerifyShares(&pedersen.Shares{
+			Abscissae:   shares.Abscissae,
+			Parts:       shares.Parts,
+			Commitments: shares.Commitments,
+			SplitID:     shares.SplitID,
+		}), pedersen.ErrInvalidBinding)
+
+		subset, err := shares.Subset(0, 1, 2)
+		require.NoError(t, err)
+
+		_, err = p.CombineShareholders(subset, transcript)
+		require.ErrorIs(t, err, pedersen.ErrInvalidBinding)
+
+		_, err = p.CombineShareholders(subset, nil)
+		require.ErrorIs(t, err, pedersen.ErrNilTranscript)
+	})
+}
+
+func TestPedersenStrippedBindings(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+	require.False(t, p.AcceptsLegacyTranscripts())
+
+	secret := []byte("a secret that spans several chunks")
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+
+	// the split identifier and the bindings are removed from the transcript and
+	// from the shares, which are reordered
+	swapped := swapChunks(shares)
+	transcript := swapped.Transcript()
+	transcript.SplitID = nil
+	transcript.Bindings = nil
+
+	subset, err := swapped.Subset(0, 1, 2)
+	require.NoError(t, err)
+
+	for i := range subset {
+		subset[i].SplitID = nil
+	}
+
+	require.ErrorIs(t, p.VerifyShareholders(subset, transcript), pedersen.ErrUnboundTranscript)
+	require.ErrorIs(t, p.VerifyShare(subset[0], transcript), pedersen.ErrUnboundTranscript)
+
+	_, err = p.CombineShareholders(subset, transcript)
+	require.ErrorIs(t, err, pedersen.ErrUnboundTranscript)
+
+	// the transcripts of previous versions are accepted on request
+	legacy, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group), pedersen.LegacyTranscripts())
+	require.NoError(t, err)
+	require.True(t, legacy.AcceptsLegacyTranscripts())
+
+	require.NoError(t, legacy.VerifyShareholders(subset, transcript))
+}
+
+func TestPedersenVerifyShare(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+
+	shares, err := p.Split([]byte("a secret that spans several chunks"), nil)
+	require.NoError(t, err)
+
+	otherShares, err := p.Split([]byte("another secret"), nil)
+	require.NoError(t, err)
+
+	for i := range shares.Parts {
+		share, err := shares.Shareholder(i)
+		require.NoError(t, err)
+		require.NoError(t, p.VerifyShare(share, shares.Transcript()))
+	}
+
+	share, err := shares.Shareholder(0)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, p.VerifyShare(share, nil), pedersen.ErrNilTranscript)
+	require.ErrorIs(t, p.VerifyShare(share, otherShares.Transcript()), pedersen.ErrWrongSharesLen)
+
+	legacy := share
+	legacy.SplitID = nil
+	require.ErrorIs(t, p.VerifyShare(legacy, shares.Transcript()), pedersen.ErrSplitMismatch)
+
+	share.Parts = share.Parts[1:]
+	require.ErrorIs(t, p.VerifyShare(share, shares.Transcript()), pedersen.ErrWrongSharesLen)
+}
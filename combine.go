@@ -7,8 +7,13 @@
 package pedersen
 
 import (
+	"errors"
+
 	"github.com/matteoarella/pedersen/big"
-	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrCombineMismatch = errors.New("reconstructed secret does not match commitments")
 )
 
 type combineValue struct {
@@ -16,50 +21,108 @@ type combineValue struct {
 	value *big.Int
 }
 
+// lagrangeBasis holds the Lagrange basis coefficients at x=0 related to
+// a set of shareholders.
+type lagrangeBasis struct {
+	// shareholders are the positions of the shareholders within the shares slice.
+	shareholders []int
+	coefficients []*big.Int
+}
+
+// lagrangeBases computes the Lagrange basis of every chunk.
+// Chunks whose parts are provided by the same set of shareholders share the
+// same basis, so the coefficients are computed once for each distinct set.
+func (p *Pedersen) lagrangeBases(ctx *big.IntContext, shares []Share, chunks int) ([]*lagrangeBasis, error) {
+	bases := make([]*lagrangeBasis, chunks)
+	cache := map[string]*lagrangeBasis{}
+	key := make([]byte, len(shares))
+
+	for chunkIdx := 0; chunkIdx < chunks; chunkIdx++ {
+		for i, share := range shares {
+			key[i] = 0
+			if (SecretPart{}) != share.Parts[chunkIdx] {
+				key[i] = 1
+			}
+		}
+
+		if basis, ok := cache[string(key)]; ok {
+			bases[chunkIdx] = basis
+			continue
+		}
+
+		basis := &lagrangeBasis{}
+		xSamples := []*big.Int{}
+
+		for i, present := range key {
+			if present == 1 {
+				basis.shareholders = append(basis.shareholders, i)
+				xSamples = append(xSamples, shares[i].Abscissa)
+			}
+		}
+
+		coefficients, err := lagrangeCoefficients(ctx, xSamples, p.field)
+		if err != nil {
+			return nil, err
+		}
+
+		basis.coefficients = coefficients
+		cache[string(key)] = basis
+		bases[chunkIdx] = basis
+	}
+
+	return bases, nil
+}
+
 func (p *Pedersen) combine(
+	mont *big.MontgomeryContext,
 	ctx *big.IntContext,
 	index int,
-	abscissae []*big.Int,
-	parts []SecretPart,
+	shares []Share,
+	basis *lagrangeBasis,
+	commitments []*big.Int,
 ) (combineValue, error) {
-	var (
-		xSamples []*big.Int
-		ySamples []*big.Int
-	)
+	sSamples := make([]*big.Int, len(basis.shareholders))
+	tSamples := make([]*big.Int, len(basis.shareholders))
 
-	for idx, p := range parts {
-		if (SecretPart{}) == p {
-			continue
-		}
+	for i, shareIdx := range basis.shareholders {
+		part := shares[shareIdx].Parts[index]
 
-		ySamples = append(ySamples, p.SShare)
-		xSamples = append(xSamples, abscissae[idx])
+		sSamples[i] = part.SShare
+		tSamples[i] = part.TShare
 	}
 
 	ctx.Attach()
 	defer ctx.Detach()
 
-	zero, err := ctx.GetInt()
+	secret, err := interpolateAtZero(ctx, basis.coefficients, sSamples, p.field)
 	if err != nil {
 		return combineValue{}, err
 	}
 
-	if err := zero.SetUInt64(0); err != nil {
+	blinding, err := interpolateAtZero(ctx, basis.coefficients, tSamples, p.field)
+	if err != nil {
 		return combineValue{}, err
 	}
 
-	secret, err := interpolatePolynomial(ctx, xSamples, ySamples, zero, p.group.Q)
+	// since C_0 = g^s h^t, the reconstructed values must open the first commitment
+	commitment, err := p.commit(mont, ctx, secret, blinding)
 	if err != nil {
 		return combineValue{}, err
 	}
 
+	if commitment.Cmp(commitments[0]) != 0 {
+		return combineValue{}, ErrCombineMismatch
+	}
+
 	return combineValue{
 		index: index,
 		value: secret,
 	}, nil
 }
 
-func bigIntUnpadding(ctx *big.IntContext, n *big.Int) ([]byte, error) {
+// bigIntUnpadding decodes a chunk of the legacy encoding, whose count of leading
+// zero bytes cannot exceed maxZeros.
+func bigIntUnpadding(ctx *big.IntContext, n *big.Int, maxZeros uint64) ([]byte, error) {
 	ctx.Attach()
 	defer ctx.Detach()
 
@@ -77,6 +140,9 @@ func bigIntUnpadding(ctx *big.IntContext, n *big.Int) ([]byte, error) {
 	}
 
 	zeros := mask.Uint64()
+	if zeros > maxZeros {
+		return nil, ErrInvalidEncoding
+	}
 
 	// cut trailer
 	if err := n.Rsh(n, zerosInfoSizeBytes*8); err != nil {
@@ -98,68 +164,78 @@ func bigIntUnpadding(ctx *big.IntContext, n *big.Int) ([]byte, error) {
 }
 
 // Combine combines the secret shares into the original secret.
+// Both the secret and the blinding values are reconstructed for every chunk,
+// and if they do not open the first commitment of the chunk [ErrCombineMismatch]
+// is returned, so that a wrong shares set is never combined into a wrong secret.
+// Commitments that are reordered or that come from another split are rejected
+// with [ErrInvalidBinding] or [ErrSplitMismatch].
 func (p *Pedersen) Combine(shares *Shares) ([]byte, error) {
 	err := p.validateShares(shares)
 	if err != nil {
 		return nil, err
 	}
 
-	splittedLen := len(shares.Parts[0])
-	values := make([]*big.Int, splittedLen)
-	concLimit := p.adjustConcLimit(splittedLen)
-	chunksIndex := p.balanceIndices(splittedLen, concLimit)
-	group := errgroup.Group{}
-	group.SetLimit(concLimit)
-
-	for _, chunk := range chunksIndex {
-		chunk := chunk
-
-		group.Go(func() error {
-			ctx, err := big.NewIntContext()
-			if err != nil {
-				return err
-			}
-			defer ctx.Destroy()
-
-			parts := make([]SecretPart, p.parts)
+	return p.combineShareholders(shares.shareholders(), shares.Commitments, shares.Encoding)
+}
 
-			for idx := chunk.start; idx < chunk.end; idx++ {
-				for shareIdx := 0; shareIdx < p.parts; shareIdx++ {
-					parts[shareIdx] = shares.Parts[shareIdx][idx]
-				}
+// CombineShareholders combines the provided shareholders shares into the original secret.
+// Unlike [Pedersen.Combine], only the shares that have been collected need to be provided,
+// in any order.
+func (p *Pedersen) CombineShareholders(shares []Share, transcript *Transcript) ([]byte, error) {
+	commitments, err := p.validateShareholders(shares, transcript)
+	if err != nil {
+		return nil, err
+	}
 
-				value, err := p.combine(ctx, idx, shares.Abscissae, parts)
-				if err != nil {
-					return err
-				}
+	return p.combineShareholders(shares, commitments, transcript.Encoding)
+}
 
-				values[value.index] = value.value
-			}
+func (p *Pedersen) combineShareholders(shares []Share, commitments [][]*big.Int, encoding ChunkEncoding) ([]byte, error) {
+	values, err := p.combineChunks(shares, commitments)
+	if err != nil {
+		return nil, err
+	}
 
-			return nil
-		})
+	local, err := p.pool.get()
+	if err != nil {
+		return nil, err
 	}
+	defer p.pool.put(local)
+
+	return decodeSecret(local.ctx, encoding, values, p.group.Q)
+}
+
+// combineChunks reconstructs the value of every chunk.
+func (p *Pedersen) combineChunks(shares []Share, commitments [][]*big.Int) ([]*big.Int, error) {
+	splittedLen := len(commitments)
 
-	err = group.Wait()
+	local, err := p.pool.get()
 	if err != nil {
 		return nil, err
 	}
+	defer p.pool.put(local)
 
-	var res []byte
-	ctx, err := big.NewIntContext()
+	// the Lagrange coefficients are computed once and shared by every goroutine
+	bases, err := p.lagrangeBases(local.ctx, shares, splittedLen)
 	if err != nil {
 		return nil, err
 	}
-	defer ctx.Destroy()
 
-	for i := 0; i < splittedLen; i++ {
-		chunk, err := bigIntUnpadding(ctx, values[i])
+	values := make([]*big.Int, splittedLen)
+
+	err = p.runTasks(splittedLen, func(w *workerContext, chunkIdx int) error {
+		value, err := p.combine(w.mont, w.ctx, chunkIdx, shares, bases[chunkIdx], commitments[chunkIdx])
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		res = append(res, chunk...)
+		values[value.index] = value.value
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return res, nil
+	return values, nil
 }
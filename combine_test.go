@@ -75,6 +75,79 @@ func TestPedersenCombineInvalid(t *testing.T) {
 	}
 }
 
+func TestPedersenCombineMismatch(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+
+	secret := []byte("a secret that spans several chunks")
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+
+	otherShares, err := p.Split(secret, shares.Abscissae)
+	require.NoError(t, err)
+
+	t.Run("commitments of a different split", func(t *testing.T) {
+		mixed := &pedersen.Shares{
+			Abscissae:   shares.Abscissae,
+			Parts:       shares.Parts,
+			Commitments: otherShares.Commitments,
+		}
+
+		_, err := p.Combine(mixed)
+		require.ErrorIs(t, err, pedersen.ErrCombineMismatch)
+	})
+
+	t.Run("parts of a different split", func(t *testing.T) {
+		mixed := &pedersen.Shares{
+			Abscissae:   shares.Abscissae,
+			Parts:       append([][]pedersen.SecretPart{otherShares.Parts[0]}, shares.Parts[1:]...),
+			Commitments: shares.Commitments,
+		}
+
+		_, err := p.Combine(mixed)
+		require.ErrorIs(t, err, pedersen.ErrCombineMismatch)
+	})
+}
+
+func TestPedersenCombineShareholders(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(50, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+
+	secret := []byte("a secret shared among fifty shareholders")
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+
+	subset, err := shares.Subset(42, 7, 19)
+	require.NoError(t, err)
+	require.Len(t, subset, 3)
+	require.Equal(t, 42, subset[0].Index)
+
+	err = p.VerifyShareholders(subset, shares.Transcript())
+	require.NoError(t, err)
+
+	reconstructed, err := p.CombineShareholders(subset, shares.Transcript())
+	require.NoError(t, err)
+	assert.Equal(t, secret, reconstructed)
+
+	_, err = p.CombineShareholders(subset[:2], shares.Transcript())
+	require.ErrorIs(t, err, pedersen.ErrInsufficientSharesParts)
+
+	_, err = p.CombineShareholders(append(subset, subset[0]), shares.Transcript())
+	require.ErrorIs(t, err, pedersen.ErrDuplicateShareholder)
+
+	_, err = shares.Shareholder(50)
+	require.ErrorIs(t, err, pedersen.ErrInvalidShareholder)
+
+	_, err = shares.Subset(1, -1)
+	require.ErrorIs(t, err, pedersen.ErrInvalidShareholder)
+}
+
 func getRandomIndexSubset(n, size int) []int {
 	mrand.Seed(time.Now().Unix())
 	return mrand.Perm(n)[0:size]
@@ -230,3 +303,35 @@ func BenchmarkPedersenCombine_2048_7_4(b *testing.B) {
 func BenchmarkPedersenCombine_2048_10_5(b *testing.B) {
 	benchmarkCombineCase(b, 2048, 10, 5)
 }
+
+func benchmarkCombineLargeCase(b *testing.B, groupSize, parts, threshold, secretSize int) {
+	b.Helper()
+
+	group, err := pedersen.NewSchnorrGroup(groupSize)
+	require.NoError(b, err)
+
+	randomSecret := make([]byte, secretSize)
+	_, err = rand.Read(randomSecret)
+	require.NoError(b, err)
+
+	p, err := pedersen.NewPedersen(parts, threshold, pedersen.CyclicGroup(group))
+	require.NoError(b, err)
+
+	shares, err := p.Split(randomSecret, nil)
+	require.NoError(b, err)
+
+	subset, err := shares.Subset(getRandomIndexSubset(parts, threshold)...)
+	require.NoError(b, err)
+
+	b.SetBytes(int64(secretSize))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err = p.CombineShareholders(subset, shares.Transcript())
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkPedersenCombineLarge_1024_10_5(b *testing.B) {
+	benchmarkCombineLargeCase(b, 1024, 10, 5, 64*1024)
+}
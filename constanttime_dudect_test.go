@@ -0,0 +1,163 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+//go:build dudect
+// +build dudect
+
+package pedersen
+
+import (
+	"crypto/rand"
+	"math"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/matteoarella/pedersen/big"
+	"github.com/stretchr/testify/require"
+)
+
+// The timing tests measure the running time of the commitments as done by
+// dudect, so they need a quiet machine and are only run with the dudect tag:
+//
+//	go test -tags dudect -run Timing .
+
+const (
+	timingSamples = 20000
+
+	// timingThreshold is the Welch's t statistic above which dudect considers
+	// an implementation definitely not constant time.
+	timingThreshold = 10
+
+	// timingCropPercentile is the percentile above which measurements are
+	// discarded, since they are mostly due to interruptions.
+	timingCropPercentile = 0.9
+)
+
+// welchT computes Welch's t statistic of two samples.
+func welchT(a, b []float64) float64 {
+	meanVar := func(x []float64) (float64, float64) {
+		mean := 0.0
+		for _, v := range x {
+			mean += v
+		}
+		mean /= float64(len(x))
+
+		variance := 0.0
+		for _, v := range x {
+			variance += (v - mean) * (v - mean)
+		}
+		variance /= float64(len(x) - 1)
+
+		return mean, variance
+	}
+
+	meanA, varA := meanVar(a)
+	meanB, varB := meanVar(b)
+
+	return (meanA - meanB) / math.Sqrt(varA/float64(len(a))+varB/float64(len(b)))
+}
+
+// timingStatistic measures op on inputs of two classes, in a random interleaved
+// order as done by dudect, and returns Welch's t statistic of the two classes
+// running times.
+func timingStatistic(t *testing.T, fixed, random []*big.Int, op func(x *big.Int) error) float64 {
+	t.Helper()
+
+	classes := make([]byte, timingSamples)
+	_, err := rand.Read(classes)
+	require.NoError(t, err)
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var (
+		measurements [2][]float64
+		inputs       = [2][]*big.Int{fixed, random}
+	)
+
+	for _, c := range classes {
+		class := c & 1
+		input := inputs[class][len(measurements[class])%len(inputs[class])]
+
+		start := time.Now()
+		err := op(input)
+		elapsed := time.Since(start)
+		require.NoError(t, err)
+
+		measurements[class] = append(measurements[class], float64(elapsed.Nanoseconds()))
+	}
+
+	all := append(append([]float64{}, measurements[0]...), measurements[1]...)
+	sort.Float64s(all)
+	limit := all[int(float64(len(all))*timingCropPercentile)]
+
+	var cropped [2][]float64
+
+	for class := range measurements {
+		for _, m := range measurements[class] {
+			if m <= limit {
+				cropped[class] = append(cropped[class], m)
+			}
+		}
+	}
+
+	return welchT(cropped[0], cropped[1])
+}
+
+func TestConstantTimeCommitTiming(t *testing.T) {
+	group, err := NewSchnorrGroup(512)
+	require.NoError(t, err)
+
+	bits := group.Q.BitLen()
+
+	// the fixed class is an exponent with a single bit set, whose windows
+	// are almost all zero, while the random class has the same bit length
+	fixed, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, fixed.Lsh(big.One(), uint(bits-2)))
+
+	min, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, min.Set(fixed))
+
+	random := make([]*big.Int, 64)
+	require.NoError(t, randInts(random, min, group.Q, false))
+
+	blinding := make([]*big.Int, 1)
+	require.NoError(t, randInts(blinding, min, group.Q, false))
+
+	measure := func(options ...Option) float64 {
+		p, err := NewPedersen(5, 3, append([]Option{CyclicGroup(group)}, options...)...)
+		require.NoError(t, err)
+		defer p.Close()
+
+		w, err := p.pool.get()
+		require.NoError(t, err)
+		defer p.pool.put(w)
+
+		return timingStatistic(t, []*big.Int{fixed}, random, func(x *big.Int) error {
+			w.ctx.Attach()
+			defer w.ctx.Detach()
+
+			_, err := p.commit(w.mont, w.ctx, x, blinding[0])
+			return err
+		})
+	}
+
+	// the fixed-base tables skip the zero windows, so their leakage must be
+	// detected, otherwise the measurements are too noisy to be meaningful
+	control := measure()
+	t.Logf("fixed-base tables Welch's t statistic: %.2f", control)
+	require.Greater(t, math.Abs(control), float64(timingThreshold),
+		"timing measurements are too noisy for detecting a known leakage")
+
+	statistic := measure(ConstantTime())
+	t.Logf("constant-time mode Welch's t statistic: %.2f", statistic)
+
+	require.Less(t, math.Abs(statistic), float64(timingThreshold))
+}
@@ -0,0 +1,180 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen/big"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstantTimeCommitPath(t *testing.T) {
+	secret := []byte("secret committed by both modes")
+
+	for _, scenario := range []struct {
+		description  string
+		options      []Option
+		fixedBaseUse bool
+	}{
+		{
+			description:  "default mode",
+			fixedBaseUse: true,
+		},
+		{
+			description: "constant-time mode",
+			options:     []Option{ConstantTime()},
+		},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			// a new group, so that its tables are not built by other tests
+			group, err := NewSchnorrGroup(256)
+			require.NoError(t, err)
+
+			p, err := NewPedersen(5, 3, append([]Option{CyclicGroup(group)}, scenario.options...)...)
+			require.NoError(t, err)
+			defer p.Close()
+
+			shares, err := p.Split(secret, nil)
+			require.NoError(t, err)
+			require.NoError(t, p.VerifyShares(shares))
+
+			combined, err := p.Combine(shares)
+			require.NoError(t, err)
+			require.Equal(t, secret, combined)
+
+			// the tables are built only when the commitments are computed with them
+			require.Equal(t, scenario.fixedBaseUse, p.tables.g != nil)
+		})
+	}
+
+	// both modes compute the same commitments
+	group, err := NewSchnorrGroup(256)
+	require.NoError(t, err)
+
+	fast, err := NewPedersen(5, 3, CyclicGroup(group))
+	require.NoError(t, err)
+	defer fast.Close()
+
+	constant, err := NewPedersen(5, 3, CyclicGroup(group), ConstantTime())
+	require.NoError(t, err)
+	defer constant.Close()
+
+	exponents := make([]*big.Int, 2)
+	require.NoError(t, randInts(exponents, big.One(), group.Q, false))
+
+	mont, ctx := newCommitContexts(t, group)
+	defer mont.Destroy()
+	defer ctx.Destroy()
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	expected, err := fast.commit(mont, ctx, exponents[0], exponents[1])
+	require.NoError(t, err)
+
+	commitment, err := constant.commit(mont, ctx, exponents[0], exponents[1])
+	require.NoError(t, err)
+	require.Equal(t, 0, expected.Cmp(commitment))
+}
+
+func TestLeadingZeros(t *testing.T) {
+	require.Equal(t, uint64(0), leadingZeros(nil))
+	require.Equal(t, uint64(0), leadingZeros([]byte{1, 0, 0}))
+	require.Equal(t, uint64(2), leadingZeros([]byte{0, 0, 1, 0}))
+	require.Equal(t, uint64(3), leadingZeros([]byte{0, 0, 0}))
+}
+
+func TestConstantTimeSplitCombine(t *testing.T) {
+	group, err := NewSchnorrGroup(256)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(5, 3, CyclicGroup(group), ConstantTime())
+	require.NoError(t, err)
+	defer p.Close()
+	require.True(t, p.IsConstantTime())
+
+	secret := []byte("\x00\x00 secret with leading zeros")
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+	require.NoError(t, p.VerifyShares(shares))
+
+	combined, err := p.Combine(shares)
+	require.NoError(t, err)
+	require.Equal(t, secret, combined)
+}
+
+func TestConstantTimeIntermediates(t *testing.T) {
+	group, err := NewSchnorrGroup(256)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(5, 3, CyclicGroup(group), ConstantTime())
+	require.NoError(t, err)
+	defer p.Close()
+
+	secret := []byte("secret whose intermediates are flagged, long enough for several chunks")
+
+	ctx, err := big.NewIntContext()
+	require.NoError(t, err)
+	defer ctx.Destroy()
+
+	chunks, err := encodeSecret(ctx, p.encoding, secret, group.Q)
+	require.NoError(t, err)
+	require.Greater(t, len(chunks), 1)
+
+	for _, chunk := range chunks {
+		require.True(t, chunk.IsConstantTime())
+	}
+
+	poly, err := newPolynomial(chunks[0], p.threshold-1, p.field)
+	require.NoError(t, err)
+
+	for _, coefficient := range poly.coefficients {
+		require.True(t, coefficient.IsConstantTime())
+	}
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+
+	for _, parts := range shares.Parts {
+		for _, part := range parts {
+			require.True(t, part.SShare.IsConstantTime())
+			require.True(t, part.TShare.IsConstantTime())
+		}
+	}
+
+	// the weighted sums of the batch verification
+	share := shares.shareholders()[0]
+	indices := make([]int, len(share.Parts))
+	for chunkIdx := range indices {
+		indices[chunkIdx] = chunkIdx
+	}
+
+	weights, err := randomWeights(len(indices))
+	require.NoError(t, err)
+
+	ctx.Attach()
+	sSum, tSum, err := p.batchSums(ctx, weights, share, indices)
+	require.NoError(t, err)
+	require.True(t, sSum.IsConstantTime())
+	require.True(t, tSum.IsConstantTime())
+	ctx.Detach()
+
+	// the reconstructed chunks
+	values, err := p.combineChunks(shares.shareholders(), shares.Commitments)
+	require.NoError(t, err)
+
+	for chunkIdx, value := range values {
+		require.True(t, value.IsConstantTime())
+		require.Equal(t, 0, value.Cmp(chunks[chunkIdx]))
+	}
+
+	decoded, err := decodeCompact(values, group.Q)
+	require.NoError(t, err)
+	require.Equal(t, secret, decoded)
+}
@@ -0,0 +1,330 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+// Package elgamal implements hybrid ElGamal encryption over the Schnorr groups
+// of the pedersen package, with threshold decryption, so that data can be
+// encrypted to a key that no single party holds.
+//
+// The ElGamal shared secret Y^r is hashed into the key of an AES-256-GCM
+// encryption of the plaintext, where Y = g^x is the public key and r is the
+// ephemeral secret of the ciphertext.
+// The decryption key x is a threshold key of the frost package, generated with
+// [frost.KeyGen] or with a distributed key generation: every shareholder
+// computes a decryption share c1^(x_i) together with a Chaum-Pedersen proof of
+// its correctness, and any threshold decryption shares are combined with
+// Lagrange interpolation in the exponent.
+package elgamal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/frost"
+	"github.com/matteoarella/pedersen/internal/schnorr"
+)
+
+const (
+	contextString = "ELGAMAL-PEDERSEN-SCHNORR-SHA256-v1"
+)
+
+var (
+	ErrNilCiphertext                = errors.New("ciphertext cannot be nil")
+	ErrNilKeyShare                  = errors.New("key share cannot be nil")
+	ErrDecryption                   = errors.New("ciphertext cannot be decrypted")
+	ErrInsufficientDecryptionShares = errors.New("decryption shares cannot be less than threshold")
+	ErrInvalidDecryptionShare       = errors.New("invalid decryption share")
+	ErrDuplicateDecryptionShare     = errors.New("decryption shares must belong to distinct shareholders")
+)
+
+// Ciphertext represents a hybrid ElGamal ciphertext.
+type Ciphertext struct {
+	// Ephemeral is the ephemeral public key c1 = g^r.
+	Ephemeral *big.Int
+
+	// Data is the AES-256-GCM encryption of the plaintext, including the tag.
+	Data []byte
+}
+
+// DecryptionShare represents the share c1^(x_i) of the ElGamal shared secret
+// computed by a shareholder, with the proof that log_g(Y_i) = log_c1(c1^(x_i)),
+// where Y_i = g^(x_i) is the verifying share of the shareholder.
+type DecryptionShare struct {
+	// Index is the index of the shareholder.
+	Index int
+
+	// Share is the decryption share c1^(x_i).
+	Share *big.Int
+
+	// Challenge and Response are the Chaum-Pedersen proof of the decryption share.
+	Challenge *big.Int
+	Response  *big.Int
+}
+
+func newSuite(group *pedersen.Group) (*schnorr.Suite, error) {
+	return schnorr.NewSuite(group, contextString)
+}
+
+// newAEAD returns the AEAD keyed by the hash of the ephemeral public key and
+// of the shared secret. Every key encrypts a single plaintext, so the nonce is zero.
+func newAEAD(s *schnorr.Suite, ephemeral, secret *big.Int) (cipher.AEAD, []byte, error) {
+	c1, err := s.EncodeElement(ephemeral)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shared, err := s.EncodeElement(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(s.Digest("key", append(c1, shared...)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return aead, c1, nil
+}
+
+// Encrypt encrypts the plaintext to the public key of the group.
+func Encrypt(group *pedersen.Group, publicKey *big.Int, plaintext []byte) (*Ciphertext, error) {
+	s, err := newSuite(group)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	if err := s.ValidateElement(ctx, publicKey); err != nil {
+		return nil, err
+	}
+
+	r, err := s.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeral, err := s.BaseExp(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := s.SecretExp(ctx, publicKey, r)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, c1, err := newAEAD(s, ephemeral, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{
+		Ephemeral: ephemeral,
+		Data:      aead.Seal(nil, make([]byte, aead.NonceSize()), plaintext, c1),
+	}, nil
+}
+
+// DecryptShare computes the decryption share of the ciphertext with the key
+// share of a shareholder.
+func DecryptShare(key *frost.KeyShare, ciphertext *Ciphertext) (*DecryptionShare, error) {
+	if key == nil {
+		return nil, ErrNilKeyShare
+	}
+
+	if ciphertext == nil {
+		return nil, ErrNilCiphertext
+	}
+
+	s, err := newSuite(key.Public.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	if err := s.ValidateElement(ctx, ciphertext.Ephemeral); err != nil {
+		return nil, err
+	}
+
+	share, err := s.SecretExp(ctx, ciphertext.Ephemeral, key.SigningShare)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := s.ProveDLEQ(ctx,
+		s.Group.G, key.Public.VerifyingShares[key.Index],
+		ciphertext.Ephemeral, share,
+		key.SigningShare,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DecryptionShare{
+		Index:     key.Index,
+		Share:     share,
+		Challenge: proof.Challenge,
+		Response:  proof.Response,
+	}, nil
+}
+
+// verifyShare verifies the proof of the decryption share.
+func verifyShare(
+	ctx *big.IntContext,
+	s *schnorr.Suite,
+	public *frost.PublicKeyPackage,
+	ciphertext *Ciphertext,
+	share *DecryptionShare,
+) error {
+	if share == nil || share.Index < 0 || share.Index >= len(public.VerifyingShares) {
+		return ErrInvalidDecryptionShare
+	}
+
+	if s.ValidateElement(ctx, share.Share) != nil {
+		return ErrInvalidDecryptionShare
+	}
+
+	proof := &schnorr.DLEQProof{
+		Challenge: share.Challenge,
+		Response:  share.Response,
+	}
+
+	if s.VerifyDLEQ(ctx,
+		s.Group.G, public.VerifyingShares[share.Index],
+		ciphertext.Ephemeral, share.Share,
+		proof,
+	) != nil {
+		return ErrInvalidDecryptionShare
+	}
+
+	return nil
+}
+
+// VerifyDecryptionShare verifies that the decryption share has been computed
+// with the key share of the shareholder.
+func VerifyDecryptionShare(public *frost.PublicKeyPackage, ciphertext *Ciphertext, share *DecryptionShare) error {
+	if ciphertext == nil {
+		return ErrNilCiphertext
+	}
+
+	s, err := newSuite(public.Group)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return err
+	}
+	defer ctx.Destroy()
+
+	if err := s.ValidateElement(ctx, ciphertext.Ephemeral); err != nil {
+		return err
+	}
+
+	return verifyShare(ctx, s, public, ciphertext, share)
+}
+
+// Combine verifies the decryption shares and combines them into the shared
+// secret, which decrypts the ciphertext.
+// [ErrInvalidDecryptionShare] is returned if any decryption share is invalid.
+func Combine(public *frost.PublicKeyPackage, ciphertext *Ciphertext, shares []*DecryptionShare) ([]byte, error) {
+	if ciphertext == nil {
+		return nil, ErrNilCiphertext
+	}
+
+	if err := public.Validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := newSuite(public.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(shares) < public.Threshold {
+		return nil, ErrInsufficientDecryptionShares
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	if err := s.ValidateElement(ctx, ciphertext.Ephemeral); err != nil {
+		return nil, err
+	}
+
+	identifiers := make([]*big.Int, len(shares))
+	seen := make(map[int]struct{}, len(shares))
+
+	for i, share := range shares {
+		if err := verifyShare(ctx, s, public, ciphertext, share); err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[share.Index]; ok {
+			return nil, ErrDuplicateDecryptionShare
+		}
+		seen[share.Index] = struct{}{}
+
+		identifiers[i] = public.Identifiers[share.Index]
+	}
+
+	origin, err := schnorr.Zero()
+	if err != nil {
+		return nil, err
+	}
+
+	// Y^r = prod((c1^(x_i))^lambda_i)
+	secret := big.One()
+
+	for i, share := range shares {
+		lambda, err := s.LagrangeCoefficient(ctx, origin, identifiers[i], identifiers)
+		if err != nil {
+			return nil, err
+		}
+
+		term, err := s.Exp(ctx, share.Share, lambda)
+		if err != nil {
+			return nil, err
+		}
+
+		secret, err = s.Mul(ctx, secret, term)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	aead, c1, err := newAEAD(s, ciphertext.Ephemeral, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext.Data, c1)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+
+	return plaintext, nil
+}
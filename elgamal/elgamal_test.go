@@ -0,0 +1,141 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package elgamal_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/elgamal"
+	"github.com/matteoarella/pedersen/frost"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getTestSchnorrGroup(t *testing.T) *pedersen.Group {
+	group := &pedersen.Group{}
+
+	for _, v := range []struct {
+		x   **big.Int
+		dec string
+	}{
+		{&group.P, "17634709279010524619"},
+		{&group.Q, "8817354639505262309"},
+		{&group.G, "8414335786771157015"},
+		{&group.H, "15078279289296123424"},
+	} {
+		x, err := big.NewInt()
+		require.NoError(t, err)
+		require.NoError(t, x.SetDecString(v.dec))
+		*v.x = x
+	}
+
+	return group
+}
+
+// getTestKeys generates a key shared among 5 shareholders with threshold 3.
+func getTestKeys(t *testing.T) (*frost.PublicKeyPackage, []*frost.KeyShare) {
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(getTestSchnorrGroup(t)))
+	require.NoError(t, err)
+	defer p.Close()
+
+	shares, public, err := frost.KeyGen(p, nil)
+	require.NoError(t, err)
+
+	keys := make([]*frost.KeyShare, len(shares.Parts))
+
+	for i := range shares.Parts {
+		share, err := shares.Shareholder(i)
+		require.NoError(t, err)
+
+		keys[i], err = frost.NewKeyShare(share, public)
+		require.NoError(t, err)
+	}
+
+	return public, keys
+}
+
+func decryptShares(t *testing.T, ciphertext *elgamal.Ciphertext, keys ...*frost.KeyShare) []*elgamal.DecryptionShare {
+	shares := make([]*elgamal.DecryptionShare, len(keys))
+
+	for i, key := range keys {
+		var err error
+		shares[i], err = elgamal.DecryptShare(key, ciphertext)
+		require.NoError(t, err)
+	}
+
+	return shares
+}
+
+func TestElGamalThresholdDecryption(t *testing.T) {
+	public, keys := getTestKeys(t)
+	plaintext := []byte("threshold encrypted message")
+
+	ciphertext, err := elgamal.Encrypt(public.Group, public.PublicKey, plaintext)
+	require.NoError(t, err)
+
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 3}, {0, 1, 2, 3, 4}} {
+		holders := make([]*frost.KeyShare, len(subset))
+		for i, idx := range subset {
+			holders[i] = keys[idx]
+		}
+
+		shares := decryptShares(t, ciphertext, holders...)
+		for _, share := range shares {
+			require.NoError(t, elgamal.VerifyDecryptionShare(public, ciphertext, share))
+		}
+
+		decrypted, err := elgamal.Combine(public, ciphertext, shares)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	}
+
+	// every encryption uses a fresh ephemeral key
+	other, err := elgamal.Encrypt(public.Group, public.PublicKey, plaintext)
+	require.NoError(t, err)
+	require.NotEqual(t, 0, other.Ephemeral.Cmp(ciphertext.Ephemeral))
+}
+
+func TestElGamalThresholdDecryptionInvalid(t *testing.T) {
+	public, keys := getTestKeys(t)
+	plaintext := []byte("threshold encrypted message")
+
+	ciphertext, err := elgamal.Encrypt(public.Group, public.PublicKey, plaintext)
+	require.NoError(t, err)
+
+	shares := decryptShares(t, ciphertext, keys[0], keys[1], keys[2])
+
+	_, err = elgamal.Combine(public, ciphertext, shares[:2])
+	require.ErrorIs(t, err, elgamal.ErrInsufficientDecryptionShares)
+
+	_, err = elgamal.Combine(public, ciphertext, []*elgamal.DecryptionShare{shares[0], shares[1], shares[0]})
+	require.ErrorIs(t, err, elgamal.ErrDuplicateDecryptionShare)
+
+	// a decryption share attributed to another shareholder
+	forged := *shares[1]
+	forged.Index = 3
+	require.ErrorIs(t, elgamal.VerifyDecryptionShare(public, ciphertext, &forged), elgamal.ErrInvalidDecryptionShare)
+
+	// a wrong decryption share with a proof for another ciphertext
+	other, err := elgamal.Encrypt(public.Group, public.PublicKey, plaintext)
+	require.NoError(t, err)
+
+	wrong := decryptShares(t, other, keys[2])[0]
+	_, err = elgamal.Combine(public, ciphertext, []*elgamal.DecryptionShare{shares[0], shares[1], wrong})
+	require.ErrorIs(t, err, elgamal.ErrInvalidDecryptionShare)
+
+	tampered := *ciphertext
+	tampered.Data = append([]byte(nil), ciphertext.Data...)
+	tampered.Data[0] ^= 1
+
+	_, err = elgamal.Combine(public, &tampered, shares)
+	require.ErrorIs(t, err, elgamal.ErrDecryption)
+
+	_, err = elgamal.Encrypt(public.Group, big.One(), plaintext)
+	require.ErrorIs(t, err, frost.ErrInvalidElement)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+// ChunkEncoding is the version of the encoding of a secret into the chunks that
+// are split into secret parts.
+type ChunkEncoding int
+
+const (
+	// CompactEncoding prefixes the secret with its length, which is stored once,
+	// and packs every chunk to the full capacity of Q, padding the last chunk
+	// with zeros. It is the zero value, so it is the encoding of shares that do
+	// not state one.
+	CompactEncoding ChunkEncoding = iota
+
+	// LegacyEncoding appends to every chunk a trailer of 4 bytes holding the count
+	// of its leading zero bytes. It is the encoding of the shares produced by
+	// previous versions.
+	LegacyEncoding
+)
+
+const (
+	defaultChunkEncoding = CompactEncoding
+
+	compactLengthSizeBytes = 8
+)
+
+var (
+	ErrInvalidEncoding = errors.New("invalid chunk encoding")
+)
+
+// valid reports whether the encoding is known.
+func (e ChunkEncoding) valid() bool {
+	return e == LegacyEncoding || e == CompactEncoding
+}
+
+// compactChunkSize returns the number of bytes packed in every chunk by the
+// compact encoding, which is the largest one whose values are always less than max.
+func compactChunkSize(max *big.Int) int {
+	size := (max.BitLen() - 1) / 8
+	if size <= 0 {
+		size = 1
+	}
+
+	return size
+}
+
+// encodeCompact encodes the secret with the compact encoding.
+func encodeCompact(secret []byte, max *big.Int) []*big.Int {
+	chunkSize := compactChunkSize(max)
+
+	encodedLen := compactLengthSizeBytes + len(secret)
+	chunksCount := (encodedLen + chunkSize - 1) / chunkSize
+
+	buf := make([]byte, chunksCount*chunkSize)
+	binary.BigEndian.PutUint64(buf, uint64(len(secret)))
+	copy(buf[compactLengthSizeBytes:], secret)
+
+	chunks := make([]*big.Int, chunksCount)
+
+	for i := range chunks {
+		chunks[i] = new(big.Int).SetBytes(buf[i*chunkSize : (i+1)*chunkSize])
+		chunks[i].SetConstantTime()
+	}
+
+	return chunks
+}
+
+// decodeCompact decodes the chunks of a secret encoded with the compact encoding.
+func decodeCompact(chunks []*big.Int, max *big.Int) ([]byte, error) {
+	chunkSize := compactChunkSize(max)
+	buf := make([]byte, len(chunks)*chunkSize)
+
+	// every chunk is written left-padded to the chunk size, so that the number
+	// of bytes copied does not depend on its value
+	for i, chunk := range chunks {
+		if chunk.BytesLen() > chunkSize {
+			return nil, ErrInvalidEncoding
+		}
+
+		if err := chunk.FillBytes(buf[i*chunkSize : (i+1)*chunkSize]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(buf) < compactLengthSizeBytes {
+		return nil, ErrInvalidEncoding
+	}
+
+	secretLen := binary.BigEndian.Uint64(buf)
+	if secretLen > uint64(len(buf)-compactLengthSizeBytes) {
+		return nil, ErrInvalidEncoding
+	}
+
+	encodedLen := compactLengthSizeBytes + int(secretLen)
+
+	// only the last chunk can be padded
+	if len(buf)-encodedLen >= chunkSize {
+		return nil, ErrInvalidEncoding
+	}
+
+	return buf[compactLengthSizeBytes:encodedLen], nil
+}
+
+// encodeSecret splits the secret into chunks according to the encoding.
+func encodeSecret(ctx *big.IntContext, encoding ChunkEncoding, secret []byte, max *big.Int) ([]*big.Int, error) {
+	switch encoding {
+	case LegacyEncoding:
+		return splitSecret(ctx, secret, max)
+	case CompactEncoding:
+		return encodeCompact(secret, max), nil
+	default:
+		return nil, ErrInvalidEncoding
+	}
+}
+
+// decodeSecret reassembles the secret from its chunks according to the encoding.
+func decodeSecret(ctx *big.IntContext, encoding ChunkEncoding, chunks []*big.Int, max *big.Int) ([]byte, error) {
+	switch encoding {
+	case LegacyEncoding:
+		var res []byte
+
+		maxZeros := uint64(legacyChunkSize(max))
+
+		for _, chunk := range chunks {
+			data, err := bigIntUnpadding(ctx, chunk, maxZeros)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, data...)
+		}
+
+		return res, nil
+	case CompactEncoding:
+		return decodeCompact(chunks, max)
+	default:
+		return nil, ErrInvalidEncoding
+	}
+}
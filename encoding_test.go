@@ -0,0 +1,134 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/matteoarella/pedersen/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactEncoding(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	chunkSize := compactChunkSize(group.Q)
+	require.Equal(t, 15, chunkSize)
+
+	for secretLen := 1; secretLen <= 4*chunkSize; secretLen++ {
+		secret := make([]byte, secretLen)
+		_, err := rand.Read(secret)
+		require.NoError(t, err)
+
+		chunks := encodeCompact(secret, group.Q)
+		require.Len(t, chunks, (compactLengthSizeBytes+secretLen+chunkSize-1)/chunkSize)
+
+		for _, chunk := range chunks {
+			require.Equal(t, -1, chunk.Cmp(group.Q))
+		}
+
+		decoded, err := decodeCompact(chunks, group.Q)
+		require.NoError(t, err)
+		require.Equal(t, secret, decoded)
+	}
+
+	// leading zeros of the secret and of the chunks are preserved
+	secret := make([]byte, 3*chunkSize)
+	secret[len(secret)-1] = 1
+
+	decoded, err := decodeCompact(encodeCompact(secret, group.Q), group.Q)
+	require.NoError(t, err)
+	require.Equal(t, secret, decoded)
+
+	t.Run("invalid", func(t *testing.T) {
+		chunks := encodeCompact([]byte("secret"), group.Q)
+
+		zero, err := big.NewInt()
+		require.NoError(t, err)
+		require.NoError(t, zero.SetUInt64(0))
+
+		// a trailing chunk that is made only of padding
+		_, err = decodeCompact(append(chunks, zero), group.Q)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+
+		// a length that exceeds the chunks
+		long := new(big.Int).SetBytes(bytes.Repeat([]byte{0xff}, chunkSize))
+		_, err = decodeCompact([]*big.Int{long}, group.Q)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+
+		// a chunk that does not fit the chunk size
+		_, err = decodeCompact([]*big.Int{group.Q}, group.Q)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+
+		_, err = decodeCompact(nil, group.Q)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+	})
+}
+
+func TestChunkEncodings(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	_, err = NewPedersen(5, 3, CyclicGroup(group), Encoding(ChunkEncoding(42)))
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	compact, err := NewPedersen(5, 3, CyclicGroup(group))
+	require.NoError(t, err)
+	defer compact.Close()
+	require.Equal(t, CompactEncoding, compact.GetEncoding())
+
+	legacy, err := NewPedersen(5, 3, CyclicGroup(group), Encoding(LegacyEncoding))
+	require.NoError(t, err)
+	defer legacy.Close()
+
+	secret := bytes.Repeat([]byte{0xff}, 200)
+
+	compactShares, err := compact.Split(secret, nil)
+	require.NoError(t, err)
+	require.Equal(t, CompactEncoding, compactShares.Encoding)
+
+	legacyShares, err := legacy.Split(secret, nil)
+	require.NoError(t, err)
+	require.Equal(t, LegacyEncoding, legacyShares.Encoding)
+
+	// the compact encoding packs the secret into fewer chunks
+	assert.Less(t, len(compactShares.Commitments), len(legacyShares.Commitments))
+
+	// both encodings are combined by any Pedersen struct
+	for _, p := range []*Pedersen{compact, legacy} {
+		for _, shares := range []*Shares{compactShares, legacyShares} {
+			reconstructed, err := p.Combine(shares)
+			require.NoError(t, err)
+			assert.Equal(t, secret, reconstructed)
+		}
+	}
+
+	t.Run("encoding bound to the commitments", func(t *testing.T) {
+		transcript := compactShares.Transcript()
+		transcript.Encoding = LegacyEncoding
+
+		subset, err := compactShares.Subset(0, 1, 2)
+		require.NoError(t, err)
+
+		_, err = compact.CombineShareholders(subset, transcript)
+		require.ErrorIs(t, err, ErrInvalidBinding)
+	})
+
+	t.Run("compact chunks decoded as legacy", func(t *testing.T) {
+		unbound := *compactShares
+		unbound.SplitID = nil
+		unbound.Bindings = nil
+		unbound.Encoding = LegacyEncoding
+
+		_, err := compact.Combine(&unbound)
+		require.ErrorIs(t, err, ErrInvalidEncoding)
+	})
+}
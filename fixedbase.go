@@ -0,0 +1,229 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"sync"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+const (
+	// fixedBaseWindowBits is the number of exponent bits consumed by each
+	// table lookup. It is fixed to 4 so that every window is a nibble of the
+	// big-endian exponent bytes.
+	fixedBaseWindowBits = 4
+	fixedBaseWindowSize = 1 << fixedBaseWindowBits
+)
+
+// fixedBaseTable holds the precomputed powers of a fixed base b modulo p
+// in Montgomery form, so that b^e can be computed with one Montgomery
+// multiplication for each nonzero window of e and no squarings.
+// powers[i][d-1] = b^(d*2^(w*i)) for 1 <= d < 2^w, where w is fixedBaseWindowBits.
+type fixedBaseTable struct {
+	powers [][]*big.Int
+}
+
+func newFixedBaseTable(
+	mont *big.MontgomeryContext,
+	ctx *big.IntContext,
+	base *big.Int,
+	bits int,
+) (*fixedBaseTable, error) {
+	windows := (bits + fixedBaseWindowBits - 1) / fixedBaseWindowBits
+	table := &fixedBaseTable{
+		powers: make([][]*big.Int, windows),
+	}
+
+	// current is b^(2^(w*i)) in Montgomery form
+	current, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := current.ToMontgomery(mont, ctx, base); err != nil {
+		return nil, err
+	}
+
+	for i := range table.powers {
+		row := make([]*big.Int, fixedBaseWindowSize-1)
+
+		row[0], err = big.NewInt()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := row[0].Set(current); err != nil {
+			return nil, err
+		}
+
+		for d := 1; d < len(row); d++ {
+			row[d], err = big.NewInt()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := row[d].ModMulMontgomery(mont, ctx, row[d-1], current); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := current.ModMulMontgomery(mont, ctx, row[len(row)-1], current); err != nil {
+			return nil, err
+		}
+
+		table.powers[i] = row
+	}
+
+	return table, nil
+}
+
+// fixedBaseTables holds the precomputation tables for the generators G and H
+// of a group, which are built on first use and are read-only afterwards,
+// so that they can be shared by any number of goroutines.
+type fixedBaseTables struct {
+	once sync.Once
+	err  error
+
+	// bits is the maximum bit length of the exponents supported by the tables.
+	bits int
+
+	// one is the Montgomery form of 1.
+	one *big.Int
+
+	g *fixedBaseTable
+	h *fixedBaseTable
+}
+
+// get returns the tables, building them for the group on the first call.
+func (f *fixedBaseTables) get(group *Group) (*fixedBaseTables, error) {
+	f.once.Do(func() {
+		f.err = f.build(group)
+	})
+
+	return f, f.err
+}
+
+func (f *fixedBaseTables) build(group *Group) error {
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return err
+	}
+	defer ctx.Destroy()
+
+	mont, err := big.NewMontgomeryContext()
+	if err != nil {
+		return err
+	}
+	defer mont.Destroy()
+
+	if err := mont.Set(group.P, ctx); err != nil {
+		return err
+	}
+
+	bits := group.Q.BitLen()
+
+	one, err := big.NewInt()
+	if err != nil {
+		return err
+	}
+
+	if err := one.ToMontgomery(mont, ctx, big.One()); err != nil {
+		return err
+	}
+
+	g, err := newFixedBaseTable(mont, ctx, group.G, bits)
+	if err != nil {
+		return err
+	}
+
+	h, err := newFixedBaseTable(mont, ctx, group.H, bits)
+	if err != nil {
+		return err
+	}
+
+	f.bits = bits
+	f.one = one
+	f.g = g
+	f.h = h
+
+	return nil
+}
+
+// supports reports whether the exponent can be handled by the tables.
+func (f *fixedBaseTables) supports(exp *big.Int) bool {
+	return exp.BitLen() <= f.bits
+}
+
+// mulWindows multiplies acc by the table entries selected by the windows of exp.
+func (f *fixedBaseTables) mulWindows(
+	mont *big.MontgomeryContext,
+	ctx *big.IntContext,
+	acc *big.Int,
+	table *fixedBaseTable,
+	exp *big.Int,
+) error {
+	buf, err := exp.Bytes()
+	if err != nil {
+		return err
+	}
+
+	// the least significant window is the low nibble of the last byte
+	for i := range buf {
+		b := buf[len(buf)-1-i]
+
+		for j, d := range [2]byte{b & 0x0f, b >> fixedBaseWindowBits} {
+			if d == 0 {
+				continue
+			}
+
+			if err := acc.ModMulMontgomery(mont, ctx, acc, table.powers[2*i+j][d-1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// commit computes g^s*h^t mod p accumulating both exponentiations in the same
+// Montgomery product. mont must be set for the modulus p of the group and
+// s and t must be nonnegative and supported by the tables.
+func (f *fixedBaseTables) commit(
+	mont *big.MontgomeryContext,
+	ctx *big.IntContext,
+	s *big.Int,
+	t *big.Int,
+) (*big.Int, error) {
+	acc, err := ctx.GetInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := acc.Set(f.one); err != nil {
+		return nil, err
+	}
+
+	if err := f.mulWindows(mont, ctx, acc, f.g, s); err != nil {
+		return nil, err
+	}
+
+	if err := f.mulWindows(mont, ctx, acc, f.h, t); err != nil {
+		return nil, err
+	}
+
+	result, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.FromMontgomery(mont, ctx, acc); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
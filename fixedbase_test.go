@@ -0,0 +1,188 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/matteoarella/pedersen/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func naiveCommit(ctx *big.IntContext, group *Group, s, t *big.Int) (*big.Int, error) {
+	gs, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	ht, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gs.ModExp(ctx, group.G, s, group.P); err != nil {
+		return nil, err
+	}
+
+	if err := ht.ModExp(ctx, group.H, t, group.P); err != nil {
+		return nil, err
+	}
+
+	if err := gs.ModMul(ctx, gs, ht, group.P); err != nil {
+		return nil, err
+	}
+
+	return gs, nil
+}
+
+func newCommitContexts(tb testing.TB, group *Group) (*big.MontgomeryContext, *big.IntContext) {
+	tb.Helper()
+
+	ctx, err := big.NewIntContext()
+	require.NoError(tb, err)
+
+	mont, err := big.NewMontgomeryContext()
+	require.NoError(tb, err)
+	require.NoError(tb, mont.Set(group.P, ctx))
+
+	return mont, ctx
+}
+
+func TestFixedBaseCommit(t *testing.T) {
+	group, err := NewSchnorrGroup(256)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(5, 3, CyclicGroup(group))
+	require.NoError(t, err)
+
+	qMinus, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, qMinus.Sub(group.Q, big.One()))
+
+	zero, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, zero.SetUInt64(0))
+
+	// exponents larger than the order fall back to the generic exponentiation
+	large, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, large.Lsh(group.Q, 3))
+
+	exponents := []*big.Int{zero, big.One(), qMinus, large}
+	randoms := make([]*big.Int, 16)
+	require.NoError(t, randInts(randoms, zero, group.Q, false))
+	exponents = append(exponents, randoms...)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		mont, ctx := newCommitContexts(t, group)
+		defer mont.Destroy()
+		defer ctx.Destroy()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j, s := range exponents {
+				tt := exponents[len(exponents)-1-j]
+
+				ctx.Attach()
+				commitment, err := p.commit(mont, ctx, s, tt)
+				ctx.Detach()
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				expected, err := naiveCommit(ctx, group, s, tt)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, 0, commitment.Cmp(expected), "s=%s t=%s", s, tt)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestFixedBaseTablesCopy(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	tables, err := group.fixedBase()
+	require.NoError(t, err)
+
+	// copies of the group share its tables
+	copied := *group
+
+	copiedTables, err := copied.fixedBase()
+	require.NoError(t, err)
+	require.Same(t, tables, copiedTables)
+
+	p, err := NewPedersen(5, 3, CyclicGroup(&copied))
+	require.NoError(t, err)
+	require.Same(t, tables, p.tables)
+
+	// groups which are not obtained from NewSchnorrGroup build their tables on first use
+	literal := &Group{P: group.P, Q: group.Q, G: group.G, H: group.H}
+
+	literalTables, err := literal.fixedBase()
+	require.NoError(t, err)
+	require.NotSame(t, tables, literalTables)
+	require.Equal(t, tables.bits, literalTables.bits)
+}
+
+func benchmarkCommit(b *testing.B, bits int, naive bool) {
+	group, err := NewSchnorrGroup(bits)
+	require.NoError(b, err)
+
+	p, err := NewPedersen(5, 3, CyclicGroup(group))
+	require.NoError(b, err)
+
+	mont, ctx := newCommitContexts(b, group)
+	defer mont.Destroy()
+	defer ctx.Destroy()
+
+	exponents := make([]*big.Int, 2)
+	require.NoError(b, randInts(exponents, big.One(), group.Q, false))
+
+	// build the tables outside of the measured loop
+	_, err = group.fixedBase()
+	require.NoError(b, err)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if naive {
+			_, err = naiveCommit(ctx, group, exponents[0], exponents[1])
+		} else {
+			ctx.Attach()
+			_, err = p.commit(mont, ctx, exponents[0], exponents[1])
+			ctx.Detach()
+		}
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkCommitModExp_1024(b *testing.B) {
+	benchmarkCommit(b, 1024, true)
+}
+
+func BenchmarkCommitFixedBase_1024(b *testing.B) {
+	benchmarkCommit(b, 1024, false)
+}
+
+func BenchmarkCommitModExp_2048(b *testing.B) {
+	benchmarkCommit(b, 2048, true)
+}
+
+func BenchmarkCommitFixedBase_2048(b *testing.B) {
+	benchmarkCommit(b, 2048, false)
+}
@@ -0,0 +1,263 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package frost
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrNilTransport        = errors.New("transport cannot be nil")
+	ErrInsufficientSigners = errors.New("signers cannot be less than threshold")
+	ErrDuplicateSigner     = errors.New("signers must be distinct")
+	ErrUnknownParticipant  = errors.New("unknown participant")
+	ErrInvalidDigestLen    = errors.New("digest length does not match the hash function")
+)
+
+// Transport delivers the requests of the coordinator to the participants, which
+// are addressed by their index.
+type Transport interface {
+	// Commit asks the participant to perform the first round of the protocol.
+	Commit(ctx context.Context, participant int) (NonceCommitment, error)
+
+	// Sign asks the participant to perform the second round of the protocol.
+	Sign(ctx context.Context, participant int, message []byte, commitments []NonceCommitment) (SignatureShare, error)
+}
+
+// MemoryTransport is a [Transport] which calls the participants directly,
+// and is meant for tests and for participants living in the same process.
+type MemoryTransport struct {
+	mu           sync.RWMutex
+	participants map[int]*Participant
+}
+
+// NewMemoryTransport creates a new MemoryTransport for the provided participants.
+func NewMemoryTransport(participants ...*Participant) *MemoryTransport {
+	t := &MemoryTransport{
+		participants: make(map[int]*Participant, len(participants)),
+	}
+
+	for _, participant := range participants {
+		t.participants[participant.Index()] = participant
+	}
+
+	return t
+}
+
+func (t *MemoryTransport) participant(ctx context.Context, index int) (*Participant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	participant, ok := t.participants[index]
+	if !ok {
+		return nil, ErrUnknownParticipant
+	}
+
+	return participant, nil
+}
+
+// Commit implements [Transport].
+func (t *MemoryTransport) Commit(ctx context.Context, participant int) (NonceCommitment, error) {
+	p, err := t.participant(ctx, participant)
+	if err != nil {
+		return NonceCommitment{}, err
+	}
+
+	return p.Commit()
+}
+
+// Sign implements [Transport].
+func (t *MemoryTransport) Sign(
+	ctx context.Context,
+	participant int,
+	message []byte,
+	commitments []NonceCommitment,
+) (SignatureShare, error) {
+	p, err := t.participant(ctx, participant)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	return p.Sign(message, commitments)
+}
+
+// A Coordinator runs the two rounds of the protocol with a fixed set of signers,
+// and aggregates their signature shares.
+type Coordinator struct {
+	public    *PublicKeyPackage
+	transport Transport
+	signers   []int
+}
+
+// NewCoordinator creates a new Coordinator, which signs with the participants
+// whose indices are signers, reached through the provided transport.
+func NewCoordinator(public *PublicKeyPackage, transport Transport, signers []int) (*Coordinator, error) {
+	if transport == nil {
+		return nil, ErrNilTransport
+	}
+
+	if err := public.Validate(); err != nil {
+		return nil, err
+	}
+
+	if len(signers) < public.Threshold {
+		return nil, ErrInsufficientSigners
+	}
+
+	seen := make(map[int]struct{}, len(signers))
+
+	for _, signer := range signers {
+		if signer < 0 || signer >= len(public.Identifiers) {
+			return nil, ErrInvalidParticipant
+		}
+
+		if _, ok := seen[signer]; ok {
+			return nil, ErrDuplicateSigner
+		}
+		seen[signer] = struct{}{}
+	}
+
+	return &Coordinator{
+		public:    public,
+		transport: transport,
+		signers:   append([]int(nil), signers...),
+	}, nil
+}
+
+// Public returns the public key package of the coordinator.
+func (c *Coordinator) Public() *PublicKeyPackage {
+	return c.public
+}
+
+// Sign signs the message with the signers of the coordinator.
+// The signature is verified against the public key before being returned.
+func (c *Coordinator) Sign(ctx context.Context, message []byte) (*Signature, error) {
+	commitments := make([]NonceCommitment, len(c.signers))
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, signer := range c.signers {
+		i, signer := i, signer
+
+		g.Go(func() error {
+			commitment, err := c.transport.Commit(gctx, signer)
+			if err != nil {
+				return err
+			}
+
+			if commitment.Identifier == nil || commitment.Identifier.Cmp(c.public.Identifiers[signer]) != 0 {
+				return ErrInvalidIdentifier
+			}
+
+			commitments[i] = commitment
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	shares := make([]SignatureShare, len(c.signers))
+
+	g, gctx = errgroup.WithContext(ctx)
+	for i, signer := range c.signers {
+		i, signer := i, signer
+
+		g.Go(func() error {
+			share, err := c.transport.Sign(gctx, signer, message, commitments)
+			if err != nil {
+				return err
+			}
+
+			shares[i] = share
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	signature, err := Aggregate(c.public, message, commitments, shares)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Verify(c.public.Group, c.public.PublicKey, message, signature); err != nil {
+		return nil, err
+	}
+
+	return signature, nil
+}
+
+// PublicKey is the public key of a shared signing key.
+type PublicKey struct {
+	Group *pedersen.Group
+	Y     *big.Int
+}
+
+// Verify verifies the signature of the message, encoded with [Signature.Bytes].
+func (pub *PublicKey) Verify(message, signature []byte) error {
+	sig, err := ParseSignature(pub.Group, signature)
+	if err != nil {
+		return err
+	}
+
+	return Verify(pub.Group, pub.Y, message, sig)
+}
+
+// Signer adapts a [Coordinator] to the [crypto.Signer] interface.
+type Signer struct {
+	coordinator *Coordinator
+}
+
+// NewSigner creates a new Signer backed by the provided coordinator.
+func NewSigner(coordinator *Coordinator) *Signer {
+	return &Signer{
+		coordinator: coordinator,
+	}
+}
+
+// Public returns the *[PublicKey] of the shared signing key.
+func (s *Signer) Public() crypto.PublicKey {
+	public := s.coordinator.Public()
+
+	return &PublicKey{
+		Group: public.Group,
+		Y:     public.PublicKey,
+	}
+}
+
+// Sign signs the digest with the signers of the coordinator, and returns the
+// signature encoded with [Signature.Bytes].
+// The digest is signed as the message, so it must be the hash of the message if
+// opts.HashFunc() is not zero, or the message itself otherwise.
+// The nonces are generated by the participants, so rand is not used.
+func (s *Signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != 0 && len(digest) != opts.HashFunc().Size() {
+		return nil, ErrInvalidDigestLen
+	}
+
+	signature, err := s.coordinator.Sign(context.Background(), digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return signature.Bytes(s.coordinator.Public().Group)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+// Package frost implements the two-round FROST threshold Schnorr signature
+// protocol (RFC 9591) over the Schnorr groups of the pedersen package, so that
+// a signing key shared among the shareholders is never reconstructed.
+//
+// The signing key is shared as a scalar with [pedersen.Pedersen.SplitScalar],
+// and the identifier of every participant is the abscissa of its share.
+// Only the generator G of the group is used for signing, while H is used only
+// by the Pedersen commitments of the key shares.
+// Elliptic curve groups are not supported, since the pedersen package only
+// provides Schnorr groups.
+package frost
+
+import (
+	"errors"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/schnorr"
+)
+
+const (
+	contextString = "FROST-PEDERSEN-SCHNORR-SHA256-v1"
+)
+
+var (
+	ErrNilGroup          = schnorr.ErrNilGroup
+	ErrInvalidElement    = schnorr.ErrInvalidElement
+	ErrInvalidScalar     = schnorr.ErrInvalidScalar
+	ErrInvalidIdentifier = schnorr.ErrInvalidIdentifier
+	ErrInvalidSignature  = errors.New("invalid signature")
+)
+
+func newSuite(group *pedersen.Group) (*schnorr.Suite, error) {
+	return schnorr.NewSuite(group, contextString)
+}
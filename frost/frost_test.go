@@ -0,0 +1,233 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package frost_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/frost"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getTestSchnorrGroup(t *testing.T) *pedersen.Group {
+	group := &pedersen.Group{}
+
+	for _, v := range []struct {
+		x   **big.Int
+		dec string
+	}{
+		{&group.P, "17634709279010524619"},
+		{&group.Q, "8817354639505262309"},
+		{&group.G, "8414335786771157015"},
+		{&group.H, "15078279289296123424"},
+	} {
+		x, err := big.NewInt()
+		require.NoError(t, err)
+		require.NoError(t, x.SetDecString(v.dec))
+		*v.x = x
+	}
+
+	return group
+}
+
+// getTestParticipants generates a key shared among 5 participants with
+// threshold 3, and returns the public key package and the participants.
+func getTestParticipants(t *testing.T) (*frost.PublicKeyPackage, []*frost.Participant) {
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(getTestSchnorrGroup(t)))
+	require.NoError(t, err)
+	defer p.Close()
+
+	shares, public, err := frost.KeyGen(p, nil)
+	require.NoError(t, err)
+	require.NoError(t, public.Validate())
+
+	participants := make([]*frost.Participant, len(shares.Parts))
+
+	for i := range shares.Parts {
+		share, err := shares.Shareholder(i)
+		require.NoError(t, err)
+		require.NoError(t, p.VerifyScalar(share, shares.Commitments))
+
+		key, err := frost.NewKeyShare(share, public)
+		require.NoError(t, err)
+
+		participants[i], err = frost.NewParticipant(key)
+		require.NoError(t, err)
+	}
+
+	return public, participants
+}
+
+// signRound1 performs the first round of the protocol for the provided participants.
+func signRound1(t *testing.T, participants ...*frost.Participant) []frost.NonceCommitment {
+	commitments := make([]frost.NonceCommitment, len(participants))
+
+	for i, participant := range participants {
+		var err error
+		commitments[i], err = participant.Commit()
+		require.NoError(t, err)
+	}
+
+	return commitments
+}
+
+func TestFROSTSign(t *testing.T) {
+	public, participants := getTestParticipants(t)
+	message := []byte("message")
+
+	for _, signers := range [][]int{{0, 1, 2}, {4, 2, 0}, {0, 1, 2, 3, 4}} {
+		subset := make([]*frost.Participant, len(signers))
+		for i, idx := range signers {
+			subset[i] = participants[idx]
+		}
+
+		commitments := signRound1(t, subset...)
+		shares := make([]frost.SignatureShare, len(subset))
+
+		for i, participant := range subset {
+			var err error
+			shares[i], err = participant.Sign(message, commitments)
+			require.NoError(t, err)
+		}
+
+		signature, err := frost.Aggregate(public, message, commitments, shares)
+		require.NoError(t, err)
+		require.NoError(t, frost.Verify(public.Group, public.PublicKey, message, signature))
+
+		require.ErrorIs(t,
+			frost.Verify(public.Group, public.PublicKey, []byte("other message"), signature),
+			frost.ErrInvalidSignature)
+
+		data, err := signature.Bytes(public.Group)
+		require.NoError(t, err)
+
+		parsed, err := frost.ParseSignature(public.Group, data)
+		require.NoError(t, err)
+		require.NoError(t, frost.Verify(public.Group, public.PublicKey, message, parsed))
+
+		_, err = frost.ParseSignature(public.Group, data[1:])
+		require.ErrorIs(t, err, frost.ErrInvalidSignatureLen)
+	}
+}
+
+func TestFROSTSignInvalid(t *testing.T) {
+	public, participants := getTestParticipants(t)
+	message := []byte("message")
+
+	commitments := signRound1(t, participants[0], participants[1])
+
+	_, err := participants[0].Sign(message, commitments)
+	require.ErrorIs(t, err, frost.ErrInsufficientCommitments)
+
+	commitments = signRound1(t, participants[0], participants[1], participants[2])
+
+	_, err = participants[3].Sign(message, commitments)
+	require.ErrorIs(t, err, frost.ErrMissingCommitment)
+
+	_, err = participants[0].Sign(message, []frost.NonceCommitment{commitments[0], commitments[1], commitments[0]})
+	require.ErrorIs(t, err, frost.ErrDuplicateIdentifier)
+
+	shares := make([]frost.SignatureShare, len(commitments))
+	for i := range commitments {
+		shares[i], err = participants[i].Sign(message, commitments)
+		require.NoError(t, err)
+	}
+
+	// the nonces of a commitment are used only once
+	_, err = participants[0].Sign(message, commitments)
+	require.ErrorIs(t, err, frost.ErrNonceNotFound)
+
+	_, err = frost.Aggregate(public, message, commitments, shares[:2])
+	require.ErrorIs(t, err, frost.ErrWrongSignatureSharesLen)
+
+	// the shares are bound to the message
+	_, err = frost.Aggregate(public, []byte("other message"), commitments, shares)
+	require.ErrorIs(t, err, frost.ErrInvalidSignatureShare)
+
+	tampered := append([]frost.SignatureShare(nil), shares...)
+	tampered[1].Share = shares[2].Share
+
+	_, err = frost.Aggregate(public, message, commitments, tampered)
+	require.ErrorIs(t, err, frost.ErrInvalidSignatureShare)
+
+	signature, err := frost.Aggregate(public, message, commitments, shares)
+	require.NoError(t, err)
+
+	signature.Z = shares[0].Share
+	require.ErrorIs(t, frost.Verify(public.Group, public.PublicKey, message, signature), frost.ErrInvalidSignature)
+}
+
+func TestFROSTPublicKeyPackage(t *testing.T) {
+	public, _ := getTestParticipants(t)
+
+	inconsistent := *public
+	inconsistent.VerifyingShares = append([]*big.Int(nil), public.VerifyingShares...)
+	inconsistent.VerifyingShares[4] = public.VerifyingShares[3]
+	require.ErrorIs(t, inconsistent.Validate(), frost.ErrInvalidPublicKey)
+
+	inconsistent = *public
+	inconsistent.PublicKey = public.VerifyingShares[0]
+	require.ErrorIs(t, inconsistent.Validate(), frost.ErrInvalidPublicKey)
+
+	inconsistent = *public
+	inconsistent.Threshold = 1
+	require.ErrorIs(t, inconsistent.Validate(), frost.ErrInvalidThreshold)
+
+	inconsistent = *public
+	inconsistent.VerifyingShares = public.VerifyingShares[:4]
+	require.ErrorIs(t, inconsistent.Validate(), frost.ErrWrongVerifyingShareLen)
+
+	inconsistent = *public
+	inconsistent.Identifiers = append([]*big.Int(nil), public.Identifiers...)
+	inconsistent.Identifiers[1] = public.Identifiers[0]
+	require.ErrorIs(t, inconsistent.Validate(), frost.ErrDuplicateIdentifier)
+}
+
+func TestFROSTSigner(t *testing.T) {
+	public, participants := getTestParticipants(t)
+	transport := frost.NewMemoryTransport(participants...)
+
+	_, err := frost.NewCoordinator(public, transport, []int{0, 1})
+	require.ErrorIs(t, err, frost.ErrInsufficientSigners)
+
+	_, err = frost.NewCoordinator(public, transport, []int{0, 1, 1})
+	require.ErrorIs(t, err, frost.ErrDuplicateSigner)
+
+	_, err = frost.NewCoordinator(public, transport, []int{0, 1, 5})
+	require.ErrorIs(t, err, frost.ErrInvalidParticipant)
+
+	coordinator, err := frost.NewCoordinator(public, transport, []int{3, 1, 4})
+	require.NoError(t, err)
+
+	var signer crypto.Signer = frost.NewSigner(coordinator)
+
+	digest := sha256.Sum256([]byte("message"))
+
+	signature, err := signer.Sign(nil, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	publicKey, ok := signer.Public().(*frost.PublicKey)
+	require.True(t, ok)
+	require.NoError(t, publicKey.Verify(digest[:], signature))
+	require.ErrorIs(t, publicKey.Verify([]byte("message"), signature), frost.ErrInvalidSignature)
+
+	_, err = signer.Sign(nil, digest[:16], crypto.SHA256)
+	require.ErrorIs(t, err, frost.ErrInvalidDigestLen)
+
+	// participants not reachable by the transport
+	coordinator, err = frost.NewCoordinator(public, frost.NewMemoryTransport(participants[:2]...), []int{0, 1, 2})
+	require.NoError(t, err)
+
+	_, err = coordinator.Sign(context.Background(), []byte("message"))
+	require.ErrorIs(t, err, frost.ErrUnknownParticipant)
+}
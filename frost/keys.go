@@ -0,0 +1,273 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package frost
+
+import (
+	"errors"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/internal/schnorr"
+)
+
+var (
+	ErrInvalidThreshold       = errors.New("threshold must be at least 2 and at most the number of participants")
+	ErrInvalidPublicKey       = errors.New("verifying shares are not consistent with the public key")
+	ErrInvalidKeyShare        = errors.New("signing share does not match its verifying share")
+	ErrInvalidParticipant     = errors.New("invalid participant index")
+	ErrDuplicateIdentifier    = errors.New("identifiers must be distinct")
+	ErrWrongVerifyingShareLen = errors.New("identifiers length and verifying shares length must be equal")
+)
+
+// PublicKeyPackage holds the public data of a shared signing key, which is
+// distributed to every participant and to the signature aggregator.
+type PublicKeyPackage struct {
+	// Group is the group of the signing key.
+	Group *pedersen.Group
+
+	// Threshold is the minimum number of participants required for signing.
+	Threshold int
+
+	// PublicKey is the public key g^s, where s is the signing key.
+	PublicKey *big.Int
+
+	// Identifiers is the vector of the identifiers of the participants, so
+	// Identifiers[participantIdx] is the abscissa of the share of the
+	// participant with index participantIdx.
+	Identifiers []*big.Int
+
+	// VerifyingShares is the vector of the verifying shares g^(s_i) of the
+	// participants, where s_i is the signing share of the participant with
+	// index i.
+	VerifyingShares []*big.Int
+}
+
+// KeyShare holds the signing share of a participant.
+type KeyShare struct {
+	// Index is the index of the participant, which ranges from 0 to parts-1.
+	Index int
+
+	// Identifier is the identifier of the participant.
+	Identifier *big.Int
+
+	// SigningShare is the share of the signing key of the participant.
+	SigningShare *big.Int
+
+	// Public is the public key package of the signing key.
+	Public *PublicKeyPackage
+}
+
+// KeyGen generates a signing key, or uses the provided one if not nil, and
+// shares it among the shareholders of p with [pedersen.Pedersen.SplitScalar].
+// The scalar shares are meant to be distributed to the participants, which can
+// verify them against their commitments and build their [KeyShare] with
+// [NewKeyShare], while the public key package is distributed to everyone.
+func KeyGen(p *pedersen.Pedersen, key *big.Int) (*pedersen.ScalarShares, *PublicKeyPackage, error) {
+	s, err := newSuite(p.GetGroup())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if key == nil {
+		key, err = s.RandomScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	shares, err := p.SplitScalar(key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer ctx.Destroy()
+
+	public := &PublicKeyPackage{
+		Group:           p.GetGroup(),
+		Threshold:       p.GetThreshold(),
+		Identifiers:     shares.Abscissae[:len(shares.Parts)],
+		VerifyingShares: make([]*big.Int, len(shares.Parts)),
+	}
+
+	public.PublicKey, err = s.BaseExp(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, part := range shares.Parts {
+		public.VerifyingShares[i], err = s.BaseExp(ctx, part.SShare)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return shares, public, nil
+}
+
+// NewKeyShare returns the key share of a participant from its scalar share,
+// which can be obtained from [KeyGen] or from a distributed key generation.
+// The signing share must match the verifying share of the participant.
+func NewKeyShare(share pedersen.ScalarShare, public *PublicKeyPackage) (*KeyShare, error) {
+	if err := public.Validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := newSuite(public.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	if share.Index < 0 || share.Index >= len(public.Identifiers) {
+		return nil, ErrInvalidParticipant
+	}
+
+	if share.Abscissa == nil || share.Abscissa.Cmp(public.Identifiers[share.Index]) != 0 {
+		return nil, ErrInvalidIdentifier
+	}
+
+	if err := s.ValidateScalar(share.Part.SShare); err != nil {
+		return nil, err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	verifyingShare, err := s.BaseExp(ctx, share.Part.SShare)
+	if err != nil {
+		return nil, err
+	}
+
+	if verifyingShare.Cmp(public.VerifyingShares[share.Index]) != 0 {
+		return nil, ErrInvalidKeyShare
+	}
+
+	return &KeyShare{
+		Index:        share.Index,
+		Identifier:   share.Abscissa,
+		SigningShare: share.Part.SShare,
+		Public:       public,
+	}, nil
+}
+
+// Validate checks that the public key package is well formed, and that the
+// verifying shares are the evaluations in the exponent of a polynomial of degree
+// threshold-1 whose constant term is the public key, so that any threshold
+// participants produce signatures for the same public key.
+func (pk *PublicKeyPackage) Validate() error {
+	s, err := newSuite(pk.Group)
+	if err != nil {
+		return err
+	}
+
+	if len(pk.Identifiers) != len(pk.VerifyingShares) {
+		return ErrWrongVerifyingShareLen
+	}
+
+	if pk.Threshold < 2 || pk.Threshold > len(pk.Identifiers) {
+		return ErrInvalidThreshold
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return err
+	}
+	defer ctx.Destroy()
+
+	if err := s.ValidateElement(ctx, pk.PublicKey); err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(pk.Identifiers))
+
+	for i, identifier := range pk.Identifiers {
+		if err := s.ValidateIdentifier(identifier); err != nil {
+			return err
+		}
+
+		key := identifier.Hex()
+		if _, ok := seen[key]; ok {
+			return ErrDuplicateIdentifier
+		}
+		seen[key] = struct{}{}
+
+		if err := s.ValidateElement(ctx, pk.VerifyingShares[i]); err != nil {
+			return err
+		}
+	}
+
+	// the first threshold verifying shares determine the polynomial, so every
+	// other verifying share and the public key are interpolated from them
+	basis := pk.Identifiers[:pk.Threshold]
+
+	interpolate := func(x *big.Int) (*big.Int, error) {
+		result := big.One()
+
+		for i, identifier := range basis {
+			coefficient, err := s.LagrangeCoefficient(ctx, x, identifier, basis)
+			if err != nil {
+				return nil, err
+			}
+
+			term, err := s.Exp(ctx, pk.VerifyingShares[i], coefficient)
+			if err != nil {
+				return nil, err
+			}
+
+			result, err = s.Mul(ctx, result, term)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return result, nil
+	}
+
+	origin, err := schnorr.Zero()
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := interpolate(origin)
+	if err != nil {
+		return err
+	}
+
+	if publicKey.Cmp(pk.PublicKey) != 0 {
+		return ErrInvalidPublicKey
+	}
+
+	for i := pk.Threshold; i < len(pk.Identifiers); i++ {
+		verifyingShare, err := interpolate(pk.Identifiers[i])
+		if err != nil {
+			return err
+		}
+
+		if verifyingShare.Cmp(pk.VerifyingShares[i]) != 0 {
+			return ErrInvalidPublicKey
+		}
+	}
+
+	return nil
+}
+
+// index returns the index of the participant with the provided identifier.
+func (pk *PublicKeyPackage) index(identifier *big.Int) (int, error) {
+	for i, id := range pk.Identifiers {
+		if id.Cmp(identifier) == 0 {
+			return i, nil
+		}
+	}
+
+	return 0, ErrInvalidIdentifier
+}
@@ -0,0 +1,669 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package frost
+
+import (
+	"crypto/rand"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/internal/schnorr"
+)
+
+const (
+	nonceRandomSizeBytes = 32
+)
+
+var (
+	ErrNilKeyShare             = errors.New("key share cannot be nil")
+	ErrNonceNotFound           = errors.New("no pending nonces match the signing commitment of the participant")
+	ErrMissingCommitment       = errors.New("signing commitments do not include the participant")
+	ErrInsufficientCommitments = errors.New("signing commitments cannot be less than threshold")
+	ErrInvalidSignatureShare   = errors.New("invalid signature share")
+	ErrWrongSignatureSharesLen = errors.New("signature shares length and signing commitments length must be equal")
+	ErrInvalidSignatureLen     = errors.New("invalid signature length")
+)
+
+// NonceCommitment represents the commitment to the nonces of a participant,
+// which is sent to the coordinator in the first round of the protocol.
+type NonceCommitment struct {
+	// Identifier is the identifier of the participant.
+	Identifier *big.Int
+
+	// Hiding is the commitment g^d to the hiding nonce d.
+	Hiding *big.Int
+
+	// Binding is the commitment g^e to the binding nonce e.
+	Binding *big.Int
+}
+
+// SignatureShare represents the share of a signature computed by a participant
+// in the second round of the protocol.
+type SignatureShare struct {
+	// Identifier is the identifier of the participant.
+	Identifier *big.Int
+
+	// Share is the signature share z_i of the participant.
+	Share *big.Int
+}
+
+// Signature represents a Schnorr signature (R, z), which is valid for the
+// message m and the public key Y if g^z = R*Y^c, where c is the challenge
+// computed from R, Y and m.
+type Signature struct {
+	R *big.Int
+	Z *big.Int
+}
+
+// nonces holds the secret nonces of a pending signing commitment.
+type nonces struct {
+	hiding  *big.Int
+	binding *big.Int
+}
+
+// A Participant holds the key share of a participant and the nonces of the
+// signing commitments that have not been used yet.
+// Every nonce is used for a single signature share, and is discarded as soon
+// as it is used, even if the signing fails.
+// A Participant is safe for concurrent use.
+type Participant struct {
+	key   *KeyShare
+	suite *schnorr.Suite
+
+	mu      sync.Mutex
+	pending map[string]nonces
+}
+
+// NewParticipant creates a new Participant with the provided key share.
+func NewParticipant(key *KeyShare) (*Participant, error) {
+	if key == nil {
+		return nil, ErrNilKeyShare
+	}
+
+	s, err := newSuite(key.Public.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Participant{
+		key:     key,
+		suite:   s,
+		pending: map[string]nonces{},
+	}, nil
+}
+
+// Index returns the index of the participant.
+func (p *Participant) Index() int {
+	return p.key.Index
+}
+
+// Identifier returns the identifier of the participant.
+func (p *Participant) Identifier() *big.Int {
+	return p.key.Identifier
+}
+
+// generateNonce derives a nonce from fresh randomness and from the signing share,
+// so that a weak random source alone does not reveal the nonce.
+func (p *Participant) generateNonce(ctx *big.IntContext) (*big.Int, error) {
+	random := make([]byte, nonceRandomSizeBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+
+	secret, err := p.suite.EncodeScalar(p.key.SigningShare)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := p.suite.HashToScalar(ctx, "nonce", random, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return nonce.SetConstantTime(), nil
+}
+
+// commitmentKey returns the key identifying the nonces of a signing commitment.
+func commitmentKey(s *schnorr.Suite, c NonceCommitment) (string, error) {
+	hiding, err := s.EncodeElement(c.Hiding)
+	if err != nil {
+		return "", err
+	}
+
+	binding, err := s.EncodeElement(c.Binding)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hiding) + string(binding), nil
+}
+
+// Commit generates a pair of nonces and returns their commitment, performing the
+// first round of the protocol. The nonces are kept until they are used by [Participant.Sign].
+func (p *Participant) Commit() (NonceCommitment, error) {
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return NonceCommitment{}, err
+	}
+	defer ctx.Destroy()
+
+	hiding, err := p.generateNonce(ctx)
+	if err != nil {
+		return NonceCommitment{}, err
+	}
+
+	binding, err := p.generateNonce(ctx)
+	if err != nil {
+		return NonceCommitment{}, err
+	}
+
+	commitment := NonceCommitment{
+		Identifier: p.key.Identifier,
+	}
+
+	commitment.Hiding, err = p.suite.BaseExp(ctx, hiding)
+	if err != nil {
+		return NonceCommitment{}, err
+	}
+
+	commitment.Binding, err = p.suite.BaseExp(ctx, binding)
+	if err != nil {
+		return NonceCommitment{}, err
+	}
+
+	key, err := commitmentKey(p.suite, commitment)
+	if err != nil {
+		return NonceCommitment{}, err
+	}
+
+	p.mu.Lock()
+	p.pending[key] = nonces{hiding: hiding, binding: binding}
+	p.mu.Unlock()
+
+	return commitment, nil
+}
+
+// takeNonces removes and returns the nonces of the provided signing commitment.
+func (p *Participant) takeNonces(commitment NonceCommitment) (nonces, error) {
+	key, err := commitmentKey(p.suite, commitment)
+	if err != nil {
+		return nonces{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n, ok := p.pending[key]
+	if !ok {
+		return nonces{}, ErrNonceNotFound
+	}
+	delete(p.pending, key)
+
+	return n, nil
+}
+
+// Sign computes the signature share of the message, performing the second round
+// of the protocol. The signing commitments are the ones that the coordinator
+// collected in the first round, and they must include a commitment previously
+// returned by [Participant.Commit], whose nonces are consumed.
+func (p *Participant) Sign(message []byte, commitments []NonceCommitment) (SignatureShare, error) {
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return SignatureShare{}, err
+	}
+	defer ctx.Destroy()
+
+	pkg, err := newSigningPackage(ctx, p.suite, p.key.Public, message, commitments)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	position := pkg.position(p.key.Identifier)
+	if position < 0 {
+		return SignatureShare{}, ErrMissingCommitment
+	}
+
+	n, err := p.takeNonces(pkg.commitments[position])
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	lambda, err := p.suite.LagrangeCoefficient(ctx, pkg.origin, p.key.Identifier, pkg.identifiers)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	// z_i = d + e*rho_i + lambda_i*s_i*c
+	z, err := big.NewInt()
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	field := p.suite.Field
+
+	if err := field.Mul(ctx, z, lambda, p.key.SigningShare); err != nil {
+		return SignatureShare{}, err
+	}
+
+	if err := field.Mul(ctx, z, z, pkg.challenge); err != nil {
+		return SignatureShare{}, err
+	}
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	term, err := ctx.GetInt()
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	if err := field.Mul(ctx, term, n.binding, pkg.bindingFactors[position]); err != nil {
+		return SignatureShare{}, err
+	}
+
+	if err := field.Add(ctx, z, z, term); err != nil {
+		return SignatureShare{}, err
+	}
+
+	if err := field.Add(ctx, z, z, n.hiding); err != nil {
+		return SignatureShare{}, err
+	}
+
+	return SignatureShare{
+		Identifier: p.key.Identifier,
+		Share:      z,
+	}, nil
+}
+
+// signingPackage holds the values shared by every participant for signing
+// a message with a set of signing commitments.
+type signingPackage struct {
+	suite  *schnorr.Suite
+	public *PublicKeyPackage
+
+	// commitments are sorted by identifier.
+	commitments    []NonceCommitment
+	identifiers    []*big.Int
+	bindingFactors []*big.Int
+
+	// groupCommitment is the commitment R of the signature.
+	groupCommitment *big.Int
+	challenge       *big.Int
+	origin          *big.Int
+}
+
+func newSigningPackage(
+	ctx *big.IntContext,
+	s *schnorr.Suite,
+	public *PublicKeyPackage,
+	message []byte,
+	commitments []NonceCommitment,
+) (*signingPackage, error) {
+	if len(commitments) < public.Threshold {
+		return nil, ErrInsufficientCommitments
+	}
+
+	sorted := append([]NonceCommitment(nil), commitments...)
+
+	for _, c := range sorted {
+		if err := s.ValidateIdentifier(c.Identifier); err != nil {
+			return nil, err
+		}
+
+		if _, err := public.index(c.Identifier); err != nil {
+			return nil, err
+		}
+
+		if err := s.ValidateElement(ctx, c.Hiding); err != nil {
+			return nil, err
+		}
+
+		if err := s.ValidateElement(ctx, c.Binding); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Identifier.Cmp(sorted[j].Identifier) < 0
+	})
+
+	pkg := &signingPackage{
+		suite:          s,
+		public:         public,
+		commitments:    sorted,
+		identifiers:    make([]*big.Int, len(sorted)),
+		bindingFactors: make([]*big.Int, len(sorted)),
+	}
+
+	// the binding factors bind every nonce to the message and to the whole
+	// set of signing commitments
+	encoded := []byte{}
+
+	for i, c := range sorted {
+		if i > 0 && c.Identifier.Cmp(sorted[i-1].Identifier) == 0 {
+			return nil, ErrDuplicateIdentifier
+		}
+
+		pkg.identifiers[i] = c.Identifier
+
+		for _, x := range []*big.Int{c.Hiding, c.Binding} {
+			data, err := s.EncodeElement(x)
+			if err != nil {
+				return nil, err
+			}
+
+			encoded = append(encoded, data...)
+		}
+
+		data, err := s.EncodeScalar(c.Identifier)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded = append(encoded, data...)
+	}
+
+	publicKey, err := s.EncodeElement(public.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	messageDigest := s.Digest("msg", message)
+	commitmentsDigest := s.Digest("com", encoded)
+
+	groupCommitment := big.One()
+
+	for i, c := range sorted {
+		identifier, err := s.EncodeScalar(c.Identifier)
+		if err != nil {
+			return nil, err
+		}
+
+		pkg.bindingFactors[i], err = s.HashToScalar(ctx, "rho", publicKey, messageDigest, commitmentsDigest, identifier)
+		if err != nil {
+			return nil, err
+		}
+
+		// R = prod(D_j * E_j^rho_j)
+		term, err := s.Exp(ctx, c.Binding, pkg.bindingFactors[i])
+		if err != nil {
+			return nil, err
+		}
+
+		term, err = s.Mul(ctx, term, c.Hiding)
+		if err != nil {
+			return nil, err
+		}
+
+		groupCommitment, err = s.Mul(ctx, groupCommitment, term)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pkg.groupCommitment = groupCommitment
+
+	pkg.challenge, err = challenge(s, ctx, groupCommitment, public.PublicKey, message)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.origin, err = schnorr.Zero()
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg, nil
+}
+
+// position returns the position of the participant with the provided identifier
+// within the sorted signing commitments, or -1 if it is not found.
+func (pkg *signingPackage) position(identifier *big.Int) int {
+	for i, id := range pkg.identifiers {
+		if id.Cmp(identifier) == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// challenge computes the challenge c of the signature from R, Y and the message.
+func challenge(s *schnorr.Suite, ctx *big.IntContext, groupCommitment, publicKey *big.Int, message []byte) (*big.Int, error) {
+	r, err := s.EncodeElement(groupCommitment)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := s.EncodeElement(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.HashToScalar(ctx, "chal", r, y, message)
+}
+
+// verifyShare checks that g^z_i = D_i * E_i^rho_i * Y_i^(c*lambda_i).
+func (pkg *signingPackage) verifyShare(ctx *big.IntContext, position int, share *big.Int) error {
+	s := pkg.suite
+	c := pkg.commitments[position]
+
+	if err := s.ValidateScalar(share); err != nil {
+		return err
+	}
+
+	index, err := pkg.public.index(c.Identifier)
+	if err != nil {
+		return err
+	}
+
+	lambda, err := s.LagrangeCoefficient(ctx, pkg.origin, c.Identifier, pkg.identifiers)
+	if err != nil {
+		return err
+	}
+
+	exp, err := big.NewInt()
+	if err != nil {
+		return err
+	}
+
+	if err := s.Field.Mul(ctx, exp, lambda, pkg.challenge); err != nil {
+		return err
+	}
+
+	rhs, err := s.Exp(ctx, pkg.public.VerifyingShares[index], exp)
+	if err != nil {
+		return err
+	}
+
+	term, err := s.Exp(ctx, c.Binding, pkg.bindingFactors[position])
+	if err != nil {
+		return err
+	}
+
+	rhs, err = s.Mul(ctx, rhs, term)
+	if err != nil {
+		return err
+	}
+
+	rhs, err = s.Mul(ctx, rhs, c.Hiding)
+	if err != nil {
+		return err
+	}
+
+	lhs, err := s.Exp(ctx, s.Group.G, share)
+	if err != nil {
+		return err
+	}
+
+	if lhs.Cmp(rhs) != 0 {
+		return ErrInvalidSignatureShare
+	}
+
+	return nil
+}
+
+// Aggregate verifies the signature shares of the participants and combines them
+// into the signature of the message. Every signing commitment must have exactly
+// one signature share, and [ErrInvalidSignatureShare] is returned if any share
+// is invalid.
+func Aggregate(
+	public *PublicKeyPackage,
+	message []byte,
+	commitments []NonceCommitment,
+	shares []SignatureShare,
+) (*Signature, error) {
+	s, err := newSuite(public.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	pkg, err := newSigningPackage(ctx, s, public, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(shares) != len(pkg.commitments) {
+		return nil, ErrWrongSignatureSharesLen
+	}
+
+	z, err := schnorr.Zero()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make([]bool, len(pkg.commitments))
+
+	for _, share := range shares {
+		if share.Identifier == nil {
+			return nil, ErrInvalidIdentifier
+		}
+
+		position := pkg.position(share.Identifier)
+		if position < 0 {
+			return nil, ErrMissingCommitment
+		}
+
+		if seen[position] {
+			return nil, ErrDuplicateIdentifier
+		}
+		seen[position] = true
+
+		if err := pkg.verifyShare(ctx, position, share.Share); err != nil {
+			return nil, err
+		}
+
+		if err := s.Field.Add(ctx, z, z, share.Share); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Signature{
+		R: pkg.groupCommitment,
+		Z: z,
+	}, nil
+}
+
+// Verify verifies the signature of the message for the public key of the group.
+func Verify(group *pedersen.Group, publicKey *big.Int, message []byte, signature *Signature) error {
+	s, err := newSuite(group)
+	if err != nil {
+		return err
+	}
+
+	if signature == nil {
+		return ErrInvalidSignature
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return err
+	}
+	defer ctx.Destroy()
+
+	if err := s.ValidateElement(ctx, publicKey); err != nil {
+		return err
+	}
+
+	if s.ValidateElement(ctx, signature.R) != nil || s.ValidateScalar(signature.Z) != nil {
+		return ErrInvalidSignature
+	}
+
+	c, err := challenge(s, ctx, signature.R, publicKey, message)
+	if err != nil {
+		return err
+	}
+
+	// g^z = R*Y^c
+	lhs, err := s.Exp(ctx, group.G, signature.Z)
+	if err != nil {
+		return err
+	}
+
+	rhs, err := s.Exp(ctx, publicKey, c)
+	if err != nil {
+		return err
+	}
+
+	rhs, err = s.Mul(ctx, rhs, signature.R)
+	if err != nil {
+		return err
+	}
+
+	if lhs.Cmp(rhs) != 0 {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Bytes returns the encoding of the signature, which is the concatenation of
+// the big-endian encodings of R and z, padded to the byte lengths of p and q.
+func (sig *Signature) Bytes(group *pedersen.Group) ([]byte, error) {
+	s, err := newSuite(group)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.EncodeElement(sig.R)
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := s.EncodeScalar(sig.Z)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(r, z...), nil
+}
+
+// ParseSignature parses a signature encoded with [Signature.Bytes].
+func ParseSignature(group *pedersen.Group, data []byte) (*Signature, error) {
+	s, err := newSuite(group)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) != s.ElementLen+s.ScalarLen {
+		return nil, ErrInvalidSignatureLen
+	}
+
+	return &Signature{
+		R: new(big.Int).SetBytes(data[:s.ElementLen]),
+		Z: new(big.Int).SetBytes(data[s.ElementLen:]),
+	}, nil
+}
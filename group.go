@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/matteoarella/pedersen/big"
 )
@@ -27,14 +28,23 @@ var (
 	ErrInvalidGenerator = errors.New("invalid generator")
 )
 
+// fixedBaseMu guards the creation of the precomputation tables of the groups
+// which are not obtained from NewSchnorrGroup.
+var fixedBaseMu sync.Mutex
+
 // Group represents a cyclic group.
 // P and Q are large primes s.t. p=mq+1 where m is an integer.
 // G and H are two generators of the unique subgroup of ℤ*q.
+// The fields of a Group must not be modified once the group is in use,
+// since the precomputation tables for G and H are built on first use.
 type Group struct {
 	P *big.Int
 	Q *big.Int
 	G *big.Int
 	H *big.Int
+
+	// tables is shared by the copies of the group made after its first use.
+	tables *fixedBaseTables
 }
 
 func (g *Group) String() string {
@@ -42,6 +52,43 @@ func (g *Group) String() string {
 	return string(data)
 }
 
+// fixedBase returns the precomputation tables for G and H, building them
+// on the first call. It is safe for concurrent use.
+func (g *Group) fixedBase() (*fixedBaseTables, error) {
+	return g.fixedBaseTables().get(g)
+}
+
+// fixedBaseTables returns the precomputation tables for G and H, which may
+// not be built yet.
+func (g *Group) fixedBaseTables() *fixedBaseTables {
+	fixedBaseMu.Lock()
+	defer fixedBaseMu.Unlock()
+
+	if g.tables == nil {
+		g.tables = &fixedBaseTables{}
+	}
+
+	return g.tables
+}
+
+// isSafePrime reports whether p=2q+1.
+func (g *Group) isSafePrime() (bool, error) {
+	n, err := big.NewInt()
+	if err != nil {
+		return false, err
+	}
+
+	if err := n.Lsh(g.Q, 1); err != nil {
+		return false, err
+	}
+
+	if err := n.Add(n, big.One()); err != nil {
+		return false, err
+	}
+
+	return n.Cmp(g.P) == 0, nil
+}
+
 func (g *Group) validatePrimes(ctx *big.IntContext) error {
 	if g.P == nil || g.Q == nil {
 		return ErrNilPrime
@@ -273,9 +320,10 @@ func NewSchnorrGroup(bits int) (*Group, error) {
 	}
 
 	return &Group{
-		P: p,
-		Q: q,
-		G: g,
-		H: h,
+		P:      p,
+		Q:      q,
+		G:      g,
+		H:      h,
+		tables: &fixedBaseTables{},
 	}, nil
 }
@@ -0,0 +1,84 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/matteoarella/pedersen/internal/io/armor"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArmorCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	generateCmd, err := cmd.NewGenerateCommand(fs)
+	require.NoError(t, err)
+
+	generateCmd.SetArgs([]string{"-o", "group.asc", "-b", "256"})
+	require.NoError(t, generateCmd.Execute())
+
+	group, err := afero.ReadFile(fs, "group.asc")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(group), "-----BEGIN PEDERSEN GROUP-----\n"))
+
+	secret := []byte("secret pasted into an email")
+	require.NoError(t, afero.WriteFile(fs, "secret", secret, 0o600))
+
+	splitCmd, err := cmd.NewSplitCommand(fs)
+	require.NoError(t, err)
+
+	splitCmd.SetArgs([]string{
+		"-g", "group.asc", "-i", "secret", "-p", "5", "-t", "3",
+		"--shares", "armor/shareholder-*", "--commitments", "armor/commitments",
+		"--format", "armor", "--perm", "600",
+	})
+	require.NoError(t, splitCmd.Execute())
+
+	share, err := afero.ReadFile(fs, "armor/shareholder-2.asc")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(share), "-----BEGIN PEDERSEN SHARE-----\nIndex: 2\nSplit-ID: "))
+	require.True(t, strings.HasSuffix(string(share), "-----END PEDERSEN SHARE-----\n"))
+
+	commitments, err := afero.ReadFile(fs, "armor/commitments.asc")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(commitments), "-----BEGIN PEDERSEN COMMITMENTS-----\nSplit-ID: "))
+
+	combineCmd, err := cmd.NewCombineCommand(fs)
+	require.NoError(t, err)
+
+	combineCmd.SetArgs([]string{
+		"-g", "group.asc", "-o", "reconstructed", "-p", "5", "-t", "3",
+		"--shares", "armor/shareholder-0.asc,armor/shareholder-2.asc,armor/shareholder-4.asc",
+		"--commitments", "armor/commitments.asc", "--perm", "600",
+	})
+	require.NoError(t, combineCmd.Execute())
+
+	reconstructed, err := afero.ReadFile(fs, "reconstructed")
+	require.NoError(t, err)
+	require.Equal(t, secret, reconstructed)
+
+	verifyPart := func(share string) error {
+		verifyCmd, err := cmd.NewVerifyCommand(fs)
+		require.NoError(t, err)
+
+		verifyCmd.SetArgs([]string{
+			"part", "-g", "group.asc", "-p", "5", "-t", "3",
+			"--share", share, "--commitments", "armor/commitments.asc",
+		})
+
+		return verifyCmd.Execute()
+	}
+
+	require.NoError(t, verifyPart("armor/shareholder-2.asc"))
+
+	// the commitments in place of the share
+	require.ErrorIs(t, verifyPart("armor/commitments.asc"), armor.ErrBlockType)
+}
@@ -0,0 +1,301 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"errors"
+	iofs "io/fs"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ErrMissingAttestationIndex = errors.New("attestation file does not contain the shareholder index")
+)
+
+type attestChallengeFlags struct {
+	challenge string
+}
+
+func (a *attestChallengeFlags) register(cmd *cobra.Command) error {
+	cmd.PersistentFlags().StringVarP(&a.challenge, "challenge", "", "", "base64 challenge nonce of the auditor")
+
+	return cmd.MarkPersistentFlagRequired("challenge")
+}
+
+func (a *attestChallengeFlags) decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(a.challenge)
+}
+
+type AttestChallengeCommand struct {
+	cobra.Command
+}
+
+func NewAttestChallengeCommand() (*AttestChallengeCommand, error) {
+	challengeCmd := &AttestChallengeCommand{}
+
+	challengeCmd.Command = cobra.Command{
+		Use:   "challenge",
+		Short: "Generate a challenge nonce for an attestation",
+		RunE: func(*cobra.Command, []string) error {
+			return challengeCmd.execute()
+		},
+	}
+
+	return challengeCmd, nil
+}
+
+func (a *AttestChallengeCommand) execute() error {
+	challenge, err := pedersen.NewAttestationChallenge()
+	if err != nil {
+		return err
+	}
+
+	a.Println(base64.StdEncoding.EncodeToString(challenge))
+
+	return nil
+}
+
+type AttestProveCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	pedersenFlags
+	secretShareFlags
+	attestChallengeFlags
+	legacyFlags
+	outFile string
+	fs      afero.Fs
+}
+
+func NewAttestProveCommand(fs afero.Fs) (*AttestProveCommand, error) {
+	proveCmd := &AttestProveCommand{fs: fs}
+
+	proveCmd.Command = cobra.Command{
+		Use:   "prove",
+		Short: "Prove the knowledge of a share",
+		RunE: func(*cobra.Command, []string) error {
+			return proveCmd.execute()
+		},
+	}
+
+	if err := proveCmd.pedersenFlags.register(&proveCmd.Command); err != nil {
+		return nil, err
+	}
+
+	if err := proveCmd.secretShareFlags.register(&proveCmd.Command); err != nil {
+		return nil, err
+	}
+
+	if err := proveCmd.attestChallengeFlags.register(&proveCmd.Command); err != nil {
+		return nil, err
+	}
+
+	proveCmd.fileFmtFlags.register(&proveCmd.Command)
+	proveCmd.legacyFlags.register(&proveCmd.Command)
+
+	proveCmd.PersistentFlags().StringVarP(&proveCmd.outFile, "out", "o", "", "attestation file")
+
+	if err := proveCmd.MarkPersistentFlagRequired("out"); err != nil {
+		return nil, err
+	}
+
+	return proveCmd, nil
+}
+
+func (a *AttestProveCommand) execute() error {
+	challenge, err := a.decode()
+	if err != nil {
+		return err
+	}
+
+	group := pedersen.Group{}
+	if err := readFileAutofmt(a.fs, a.groupFile, &group); err != nil {
+		return err
+	}
+
+	commitments := schema.Commitments{}
+	if err := readFileAutofmt(a.fs, a.commitmentsFile, &commitments); err != nil {
+		return err
+	}
+
+	parts, err := a.readShare(a.fs)
+	if err != nil {
+		return err
+	}
+
+	if parts.Index == nil {
+		return ErrMissingShareIndex
+	}
+
+	// an attestation proves the knowledge of the share of a single abscissa
+	if len(parts.Bundle) > 0 {
+		return ErrUnexpectedBundle
+	}
+
+	p, err := pedersen.NewPedersen(a.parts, a.threshold, a.pedersenOptions(pedersen.CyclicGroup(&group))...)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	attestation, err := p.Attest(toShare(*parts.Index, &parts), transcript(&commitments), challenge)
+	if err != nil {
+		return err
+	}
+
+	proofs := make([]schema.Proof, len(attestation.Proofs))
+	for chunkIdx, proof := range attestation.Proofs {
+		proofs[chunkIdx] = schema.Proof{
+			Commitments: proof.Commitments,
+			Path:        toSchemaBytes(proof.Path),
+		}
+	}
+
+	return writeFileAutofmt(a.fs,
+		a.fileFmt,
+		a.outFile,
+		schema.Attestation{
+			Index:     &attestation.Index,
+			Abscissa:  attestation.Abscissa,
+			SplitID:   attestation.SplitID,
+			Proofs:    proofs,
+			Nonces:    attestation.Nonces,
+			Responses: attestation.Responses,
+		},
+		iofs.FileMode(a.filePerm),
+	)
+}
+
+type AttestCheckCommand struct {
+	cobra.Command
+
+	pedersenFlags
+	attestChallengeFlags
+	legacyFlags
+	attestationFile string
+	commitmentsFile string
+	fs              afero.Fs
+}
+
+func NewAttestCheckCommand(fs afero.Fs) (*AttestCheckCommand, error) {
+	checkCmd := &AttestCheckCommand{fs: fs}
+
+	checkCmd.Command = cobra.Command{
+		Use:   "check",
+		Short: "Check the attestation of a shareholder",
+		RunE: func(*cobra.Command, []string) error {
+			return checkCmd.execute()
+		},
+	}
+
+	if err := checkCmd.pedersenFlags.register(&checkCmd.Command); err != nil {
+		return nil, err
+	}
+
+	if err := checkCmd.attestChallengeFlags.register(&checkCmd.Command); err != nil {
+		return nil, err
+	}
+
+	checkCmd.legacyFlags.register(&checkCmd.Command)
+
+	checkCmd.PersistentFlags().StringVarP(&checkCmd.attestationFile, "attestation", "", "", "attestation file")
+	checkCmd.PersistentFlags().StringVarP(&checkCmd.commitmentsFile, "commitments", "", "", "commitments file")
+
+	for _, flag := range []string{"attestation", "commitments"} {
+		if err := checkCmd.MarkPersistentFlagRequired(flag); err != nil {
+			return nil, err
+		}
+	}
+
+	return checkCmd, nil
+}
+
+func (a *AttestCheckCommand) execute() error {
+	challenge, err := a.decode()
+	if err != nil {
+		return err
+	}
+
+	group := pedersen.Group{}
+	if err := readFileAutofmt(a.fs, a.groupFile, &group); err != nil {
+		return err
+	}
+
+	commitments := schema.Commitments{}
+	if err := readFileAutofmt(a.fs, a.commitmentsFile, &commitments); err != nil {
+		return err
+	}
+
+	attestation := schema.Attestation{}
+	if err := readFileAutofmt(a.fs, a.attestationFile, &attestation); err != nil {
+		return err
+	}
+
+	if attestation.Index == nil {
+		return ErrMissingAttestationIndex
+	}
+
+	p, err := pedersen.NewPedersen(a.parts, a.threshold, a.pedersenOptions(pedersen.CyclicGroup(&group))...)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	share := toShare(*attestation.Index, &schema.Shares{
+		Abscissa: attestation.Abscissa,
+		SplitID:  attestation.SplitID,
+		Proofs:   attestation.Proofs,
+	})
+
+	return p.CheckAttestation(&pedersen.Attestation{
+		Index:     share.Index,
+		Abscissa:  share.Abscissa,
+		SplitID:   share.SplitID,
+		Proofs:    share.Proofs,
+		Nonces:    attestation.Nonces,
+		Responses: attestation.Responses,
+	}, transcript(&commitments), challenge)
+}
+
+type AttestCommand struct {
+	cobra.Command
+
+	fs afero.Fs
+}
+
+func NewAttestCommand(fs afero.Fs) (*AttestCommand, error) {
+	attestCmd := &AttestCommand{fs: fs}
+
+	attestCmd.Command = cobra.Command{
+		Use:   "attest",
+		Short: "Prove or check that a shareholder still holds a valid share",
+	}
+
+	challengeCmd, err := NewAttestChallengeCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	proveCmd, err := NewAttestProveCommand(attestCmd.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	checkCmd, err := NewAttestCheckCommand(attestCmd.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	attestCmd.AddCommand(&challengeCmd.Command, &proveCmd.Command, &checkCmd.Command)
+
+	return attestCmd, nil
+}
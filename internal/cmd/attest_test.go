@@ -0,0 +1,90 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func attestTestChallenge(t *testing.T) string {
+	t.Helper()
+
+	attestCmd, err := cmd.NewAttestCommand(afero.NewMemMapFs())
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	attestCmd.SetOut(buf)
+	attestCmd.SetArgs([]string{"challenge"})
+	require.NoError(t, attestCmd.Execute())
+
+	return strings.TrimSpace(buf.String())
+}
+
+func TestAttestCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	splitTestSecret(t, fs, []byte("secret"), "plain")
+	splitTestSecret(t, fs, []byte("secret"), "merkle", "--merkle")
+
+	challenge := attestTestChallenge(t)
+	otherChallenge := attestTestChallenge(t)
+	require.NotEqual(t, challenge, otherChallenge)
+
+	for _, prefix := range []string{"plain", "merkle"} {
+		attestCmd, err := cmd.NewAttestCommand(fs)
+		require.NoError(t, err)
+
+		attestCmd.SetArgs([]string{
+			"prove", "-g", "group.json", "-p", "5", "-t", "3",
+			"--share", prefix + "/shareholder-3.yaml", "--commitments", prefix + "/commitments.yaml",
+			"--challenge", challenge, "-o", prefix + "/attestation.yaml", "--perm", "600",
+		})
+		require.NoError(t, attestCmd.Execute())
+
+		for _, scenario := range []struct {
+			description string
+			challenge   string
+			err         error
+		}{
+			{
+				description: prefix + " attestation",
+				challenge:   challenge,
+			},
+			{
+				description: prefix + " replayed attestation",
+				challenge:   otherChallenge,
+				err:         pedersen.ErrInvalidAttestation,
+			},
+		} {
+			t.Run(scenario.description, func(t *testing.T) {
+				attestCmd, err := cmd.NewAttestCommand(fs)
+				require.NoError(t, err)
+
+				attestCmd.SetArgs([]string{
+					"check", "-g", "group.json", "-p", "5", "-t", "3",
+					"--attestation", prefix + "/attestation.yaml", "--commitments", prefix + "/commitments.yaml",
+					"--challenge", scenario.challenge,
+				})
+
+				err = attestCmd.Execute()
+				if scenario.err != nil {
+					require.ErrorIs(t, err, scenario.err)
+					return
+				}
+				require.NoError(t, err)
+			})
+		}
+	}
+}
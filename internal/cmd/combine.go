@@ -7,11 +7,9 @@
 package cmd
 
 import (
-	"errors"
 	iofs "io/fs"
 
 	"github.com/matteoarella/pedersen"
-	"github.com/matteoarella/pedersen/big"
 	"github.com/matteoarella/pedersen/internal/schema"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -22,7 +20,9 @@ type CombineCommand struct {
 
 	pedersenFlags
 	fileFmtFlags
-	secretSharesFlags
+	shareFilesFlags
+	sharingModeFlags
+	legacyFlags
 	outFile string
 	verify  bool
 	fs      afero.Fs
@@ -31,7 +31,7 @@ type CombineCommand struct {
 func NewCombineCommand(fs afero.Fs) (*CombineCommand, error) {
 	combineCmd := &CombineCommand{
 		fs: fs,
-		secretSharesFlags: secretSharesFlags{
+		shareFilesFlags: shareFilesFlags{
 			fs: fs,
 		},
 	}
@@ -49,12 +49,14 @@ func NewCombineCommand(fs afero.Fs) (*CombineCommand, error) {
 		return nil, err
 	}
 
-	err = combineCmd.secretSharesFlags.register(&combineCmd.Command)
+	err = combineCmd.shareFilesFlags.register(&combineCmd.Command)
 	if err != nil {
 		return nil, err
 	}
 
 	combineCmd.fileFmtFlags.register(&combineCmd.Command)
+	combineCmd.sharingModeFlags.register(&combineCmd.Command)
+	combineCmd.legacyFlags.register(&combineCmd.Command)
 
 	combineCmd.PersistentFlags().StringVarP(&combineCmd.outFile, "out", "o", "", "output file")
 	combineCmd.PersistentFlags().BoolVarP(&combineCmd.verify, "verify", "v", true, "verify shares before combine")
@@ -79,46 +81,48 @@ func (c *CombineCommand) execute() error {
 		return err
 	}
 
-	shares := &pedersen.Shares{
-		Abscissae:   make([]*big.Int, c.parts),
-		Commitments: commitments.Commitments,
-		Parts:       make([][]pedersen.SecretPart, c.parts),
+	if err := c.readRoster(c.fs, &c.parts); err != nil {
+		return err
 	}
 
-	for i := 0; i < c.parts; i++ {
-		parts := schema.Shares{}
-
-		shareFile := c.share(i)
-
-		if err := readFileAutofmt(c.fs, shareFile, &parts); err != nil {
-			if errors.Is(err, iofs.ErrNotExist) {
-				continue
-			}
-
-			return err
-		}
-
-		shares.Abscissae[i] = parts.Abscissa
-		shares.Parts[i] = parts.Parts
+	shares, fragments, err := c.readShares(c.parts)
+	if err != nil {
+		return err
 	}
 
 	p, err := pedersen.NewPedersen(c.parts,
 		c.threshold,
-		pedersen.CyclicGroup(&group),
+		c.pedersenOptions(pedersen.CyclicGroup(&group))...,
 	)
 	if err != nil {
 		return err
 	}
+	defer p.Close()
 
 	if c.verify {
-		if err := p.VerifyShares(shares); err != nil {
+		if err := p.VerifyShareholders(shares, transcript(&commitments)); err != nil {
 			return err
 		}
 	}
 
-	reconstructed, err := p.Combine(shares)
-	if err != nil {
-		return err
+	var reconstructed []byte
+
+	switch c.mode {
+	case ShortMode:
+		dispersal, err := c.dispersal(shares, fragments, transcript(&commitments))
+		if err != nil {
+			return err
+		}
+
+		reconstructed, err = p.CombineShortShareholders(shares, transcript(&commitments), dispersal)
+		if err != nil {
+			return err
+		}
+	default:
+		reconstructed, err = p.CombineShareholders(shares, transcript(&commitments))
+		if err != nil {
+			return err
+		}
 	}
 
 	if err := afero.WriteFile(c.fs, c.outFile, reconstructed, iofs.FileMode(c.filePerm)); err != nil {
@@ -127,3 +131,22 @@ func (c *CombineCommand) execute() error {
 
 	return nil
 }
+
+// dispersal arranges the fragments of the provided shares by shareholder index.
+func (c *CombineCommand) dispersal(
+	shares []pedersen.Share,
+	fragments []*schema.Fragment,
+	t *pedersen.Transcript,
+) (*pedersen.Dispersal, error) {
+	indexed := make([]*schema.Fragment, c.parts)
+
+	for i, share := range shares {
+		if share.Index < 0 || share.Index >= c.parts {
+			return nil, pedersen.ErrInvalidShareholder
+		}
+
+		indexed[share.Index] = fragments[i]
+	}
+
+	return shareDispersal(indexed, t)
+}
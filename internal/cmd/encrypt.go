@@ -0,0 +1,314 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"errors"
+	iofs "io/fs"
+	"strings"
+
+	"github.com/matteoarella/pedersen/elgamal"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ErrMissingDecryptionShareIndex = errors.New("decryption share file does not contain the shareholder index")
+)
+
+type EncryptCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	thresholdKeyFlags
+	inFile  string
+	outFile string
+	fs      afero.Fs
+}
+
+func NewEncryptCommand(fs afero.Fs) (*EncryptCommand, error) {
+	encryptCmd := &EncryptCommand{
+		fs: fs,
+		thresholdKeyFlags: thresholdKeyFlags{
+			fs: fs,
+		},
+	}
+
+	encryptCmd.Command = cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt data to a threshold public key",
+		RunE: func(*cobra.Command, []string) error {
+			return encryptCmd.execute()
+		},
+	}
+
+	err := encryptCmd.thresholdKeyFlags.register(&encryptCmd.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptCmd.fileFmtFlags.register(&encryptCmd.Command)
+
+	encryptCmd.PersistentFlags().StringVarP(&encryptCmd.inFile, "in", "i", "", "input file")
+	encryptCmd.PersistentFlags().StringVarP(&encryptCmd.outFile, "out", "o", "", "ciphertext file")
+
+	err = encryptCmd.MarkPersistentFlagRequired("in")
+	if err != nil {
+		return nil, err
+	}
+
+	err = encryptCmd.MarkPersistentFlagRequired("out")
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptCmd, nil
+}
+
+func (e *EncryptCommand) execute() error {
+	public, err := e.readPublic()
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := afero.ReadFile(e.fs, e.inFile)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := elgamal.Encrypt(public.Group, public.PublicKey, plaintext)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAutofmt(e.fs,
+		e.fileFmt,
+		e.outFile,
+		schema.Ciphertext{
+			Ephemeral: ciphertext.Ephemeral,
+			Data:      ciphertext.Data,
+		},
+		iofs.FileMode(e.filePerm),
+	)
+}
+
+// readCiphertext reads the ciphertext file.
+func readCiphertext(fs afero.Fs, name string) (*elgamal.Ciphertext, error) {
+	ciphertext := schema.Ciphertext{}
+	if err := readFileAutofmt(fs, name, &ciphertext); err != nil {
+		return nil, err
+	}
+
+	return &elgamal.Ciphertext{
+		Ephemeral: ciphertext.Ephemeral,
+		Data:      ciphertext.Data,
+	}, nil
+}
+
+type DecryptShareCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	thresholdKeyFlags
+	keyFile string
+	inFile  string
+	outFile string
+	fs      afero.Fs
+}
+
+func NewDecryptShareCommand(fs afero.Fs) (*DecryptShareCommand, error) {
+	decryptShareCmd := &DecryptShareCommand{
+		fs: fs,
+		thresholdKeyFlags: thresholdKeyFlags{
+			fs: fs,
+		},
+	}
+
+	decryptShareCmd.Command = cobra.Command{
+		Use:   "decrypt-share",
+		Short: "Compute the decryption share of a ciphertext",
+		RunE: func(*cobra.Command, []string) error {
+			return decryptShareCmd.execute()
+		},
+	}
+
+	err := decryptShareCmd.thresholdKeyFlags.register(&decryptShareCmd.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	decryptShareCmd.fileFmtFlags.register(&decryptShareCmd.Command)
+
+	decryptShareCmd.PersistentFlags().StringVarP(&decryptShareCmd.keyFile, "key", "", "", "key share file")
+	decryptShareCmd.PersistentFlags().StringVarP(&decryptShareCmd.inFile, "in", "i", "", "ciphertext file")
+	decryptShareCmd.PersistentFlags().StringVarP(&decryptShareCmd.outFile, "out", "o", "", "decryption share file")
+
+	for _, flag := range []string{"key", "in", "out"} {
+		if err := decryptShareCmd.MarkPersistentFlagRequired(flag); err != nil {
+			return nil, err
+		}
+	}
+
+	return decryptShareCmd, nil
+}
+
+func (d *DecryptShareCommand) execute() error {
+	public, err := d.readPublic()
+	if err != nil {
+		return err
+	}
+
+	key, err := d.readKeyShare(d.keyFile, public)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := readCiphertext(d.fs, d.inFile)
+	if err != nil {
+		return err
+	}
+
+	share, err := elgamal.DecryptShare(key, ciphertext)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAutofmt(d.fs,
+		d.fileFmt,
+		d.outFile,
+		schema.DecryptionShare{
+			Index:     &share.Index,
+			Share:     share.Share,
+			Challenge: share.Challenge,
+			Response:  share.Response,
+		},
+		iofs.FileMode(d.filePerm),
+	)
+}
+
+type DecryptCombineCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	thresholdKeyFlags
+	shareFiles []string
+	inFile     string
+	outFile    string
+	fs         afero.Fs
+}
+
+func NewDecryptCombineCommand(fs afero.Fs) (*DecryptCombineCommand, error) {
+	decryptCombineCmd := &DecryptCombineCommand{
+		fs: fs,
+		thresholdKeyFlags: thresholdKeyFlags{
+			fs: fs,
+		},
+	}
+
+	decryptCombineCmd.Command = cobra.Command{
+		Use:   "decrypt-combine",
+		Short: "Combine decryption shares and decrypt a ciphertext",
+		RunE: func(*cobra.Command, []string) error {
+			return decryptCombineCmd.execute()
+		},
+	}
+
+	err := decryptCombineCmd.thresholdKeyFlags.register(&decryptCombineCmd.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	decryptCombineCmd.fileFmtFlags.register(&decryptCombineCmd.Command)
+
+	decryptCombineCmd.PersistentFlags().StringSliceVarP(&decryptCombineCmd.shareFiles, "shares", "", nil, `decryption shares files.
+Either a comma separated list of decryption share files,
+or a single pattern expression using '*' as placeholder
+for the index of the share (e.g. decrypted/shareholder-*)`)
+	decryptCombineCmd.PersistentFlags().StringVarP(&decryptCombineCmd.inFile, "in", "i", "", "ciphertext file")
+	decryptCombineCmd.PersistentFlags().StringVarP(&decryptCombineCmd.outFile, "out", "o", "", "output file")
+
+	for _, flag := range []string{"shares", "in", "out"} {
+		if err := decryptCombineCmd.MarkPersistentFlagRequired(flag); err != nil {
+			return nil, err
+		}
+	}
+
+	return decryptCombineCmd, nil
+}
+
+// readShares reads the decryption shares.
+// If a pattern expression is provided, every share from index 0 to parts-1 is probed and
+// the missing ones are skipped.
+func (d *DecryptCombineCommand) readShares(parts int) ([]*elgamal.DecryptionShare, error) {
+	names := d.shareFiles
+	probe := len(d.shareFiles) == 1 && strings.Contains(d.shareFiles[0], "*")
+
+	if probe {
+		names = nil
+		pattern := secretSharesFlags{sharesFilePattern: d.shareFiles[0]}
+
+		for i := 0; i < parts; i++ {
+			names = append(names, pattern.share(i))
+		}
+	}
+
+	var shares []*elgamal.DecryptionShare
+
+	for i, name := range names {
+		share := schema.DecryptionShare{}
+
+		if err := readFileAutofmt(d.fs, name, &share); err != nil {
+			if probe && errors.Is(err, iofs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if share.Index == nil {
+			return nil, ErrMissingDecryptionShareIndex
+		}
+
+		if probe && *share.Index != i {
+			return nil, ErrShareIndexMismatch
+		}
+
+		shares = append(shares, &elgamal.DecryptionShare{
+			Index:     *share.Index,
+			Share:     share.Share,
+			Challenge: share.Challenge,
+			Response:  share.Response,
+		})
+	}
+
+	return shares, nil
+}
+
+func (d *DecryptCombineCommand) execute() error {
+	public, err := d.readPublic()
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := readCiphertext(d.fs, d.inFile)
+	if err != nil {
+		return err
+	}
+
+	shares, err := d.readShares(len(public.Identifiers))
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := elgamal.Combine(public, ciphertext, shares)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(d.fs, d.outFile, plaintext, iofs.FileMode(d.filePerm))
+}
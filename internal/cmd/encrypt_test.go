@@ -0,0 +1,120 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/matteoarella/pedersen/elgamal"
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func decryptTestShare(t *testing.T, fs afero.Fs, index int) error {
+	t.Helper()
+
+	decryptShareCmd, err := cmd.NewDecryptShareCommand(fs)
+	require.NoError(t, err)
+
+	decryptShareCmd.SetArgs([]string{
+		"-g", "group.json", "--public", "public.yaml",
+		"--key", fmt.Sprintf("keys/shareholder-%d.yaml", index),
+		"-i", "ciphertext.yaml", "-o", fmt.Sprintf("decrypted/shareholder-%d.yaml", index),
+		"--perm", "600",
+	})
+
+	return decryptShareCmd.Execute()
+}
+
+func TestEncryptDecryptCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	plaintext := []byte("threshold encrypted message")
+	require.NoError(t, afero.WriteFile(fs, "plaintext", plaintext, 0o600))
+
+	keygenCmd, err := cmd.NewKeygenCommand(fs)
+	require.NoError(t, err)
+
+	keygenCmd.SetArgs([]string{
+		"-g", "group.json", "-p", "5", "-t", "3",
+		"--keys", "keys/shareholder-*.yaml", "--public", "public.yaml", "--perm", "600",
+	})
+	require.NoError(t, keygenCmd.Execute())
+
+	encryptCmd, err := cmd.NewEncryptCommand(fs)
+	require.NoError(t, err)
+
+	encryptCmd.SetArgs([]string{
+		"-g", "group.json", "--public", "public.yaml",
+		"-i", "plaintext", "-o", "ciphertext.yaml", "--perm", "600",
+	})
+	require.NoError(t, encryptCmd.Execute())
+
+	for _, index := range []int{0, 2, 4} {
+		require.NoError(t, decryptTestShare(t, fs, index))
+	}
+
+	for _, scenario := range []struct {
+		description string
+		shares      string
+		err         error
+	}{
+		{
+			description: "shares pattern",
+			shares:      "decrypted/shareholder-*.yaml",
+		},
+		{
+			description: "shares list",
+			shares:      "decrypted/shareholder-4.yaml,decrypted/shareholder-0.yaml,decrypted/shareholder-2.yaml",
+		},
+		{
+			description: "insufficient shares",
+			shares:      "decrypted/shareholder-4.yaml,decrypted/shareholder-0.yaml",
+			err:         elgamal.ErrInsufficientDecryptionShares,
+		},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			decryptCombineCmd, err := cmd.NewDecryptCombineCommand(fs)
+			require.NoError(t, err)
+
+			decryptCombineCmd.SetArgs([]string{
+				"-g", "group.json", "--public", "public.yaml",
+				"-i", "ciphertext.yaml", "--shares", scenario.shares,
+				"-o", "decrypted/plaintext", "--perm", "600",
+			})
+
+			err = decryptCombineCmd.Execute()
+			if scenario.err != nil {
+				require.ErrorIs(t, err, scenario.err)
+				return
+			}
+			require.NoError(t, err)
+
+			decrypted, err := afero.ReadFile(fs, "decrypted/plaintext")
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+		})
+	}
+
+	// a decryption share written under the name of another shareholder
+	data, err := afero.ReadFile(fs, "decrypted/shareholder-4.yaml")
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "decrypted/shareholder-1.yaml", data, 0o600))
+
+	decryptCombineCmd, err := cmd.NewDecryptCombineCommand(fs)
+	require.NoError(t, err)
+
+	decryptCombineCmd.SetArgs([]string{
+		"-g", "group.json", "--public", "public.yaml",
+		"-i", "ciphertext.yaml", "--shares", "decrypted/shareholder-*.yaml",
+		"-o", "decrypted/plaintext", "--perm", "600",
+	})
+	require.ErrorIs(t, decryptCombineCmd.Execute(), cmd.ErrShareIndexMismatch)
+}
@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"github.com/matteoarella/pedersen/internal/io"
+	"github.com/matteoarella/pedersen/internal/io/armor"
 	"github.com/matteoarella/pedersen/internal/io/json"
 	"github.com/matteoarella/pedersen/internal/io/xml"
 	"github.com/matteoarella/pedersen/internal/io/yaml"
@@ -27,16 +28,18 @@ type FileFmt string
 type FilePerm uint32
 
 const (
-	YAML FileFmt = "yaml"
-	JSON FileFmt = "json"
-	XML  FileFmt = "xml"
+	YAML  FileFmt = "yaml"
+	JSON  FileFmt = "json"
+	XML   FileFmt = "xml"
+	ARMOR FileFmt = "armor"
 )
 
 var (
 	fmts = map[string]struct{}{
-		string(YAML): {},
-		string(JSON): {},
-		string(XML):  {},
+		string(YAML):  {},
+		string(JSON):  {},
+		string(XML):   {},
+		string(ARMOR): {},
 	}
 )
 
@@ -103,9 +106,16 @@ func (f *fileFmtFlags) register(cmd *cobra.Command) {
 	})
 }
 
+// readFileAutofmt reads the file by trying all the file formats.
+// Armored files are tried first, since their text would be read as a YAML
+// string, and errors of armored files are reported as they are.
 func readFileAutofmt(fs afero.Fs, name string, v interface{}) error {
+	err := armor.New(fs).ReadFile(name, v)
+	if err == nil || !(errors.Is(err, armor.ErrNotArmored) || errors.Is(err, iofs.ErrNotExist)) {
+		return err
+	}
+
 	bios := []io.IO{yaml.New(fs), json.New(fs), xml.New(fs)}
-	var err error
 
 	for _, b := range bios {
 		err = b.ReadFile(name, v)
@@ -127,6 +137,8 @@ func writeFileAutofmt(fs afero.Fs, fileFmt FileFmt, name string, v interface{},
 		bios = append(bios, json.New(fs))
 	case XML:
 		bios = append(bios, xml.New(fs))
+	case ARMOR:
+		bios = append(bios, armor.New(fs))
 	default:
 		ext := filepath.Ext(name)
 		if ext == "" {
@@ -139,6 +151,8 @@ func writeFileAutofmt(fs afero.Fs, fileFmt FileFmt, name string, v interface{},
 				bios = append(bios, json.New(fs))
 			case xml.Ext():
 				bios = append(bios, xml.New(fs))
+			case armor.Ext():
+				bios = append(bios, armor.New(fs))
 			}
 		}
 	}
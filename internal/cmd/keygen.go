@@ -0,0 +1,108 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	iofs "io/fs"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/frost"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+type KeygenCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	pedersenFlags
+	keysFilePattern string
+	publicFile      string
+	fs              afero.Fs
+}
+
+func NewKeygenCommand(fs afero.Fs) (*KeygenCommand, error) {
+	keygenCmd := &KeygenCommand{fs: fs}
+
+	keygenCmd.Command = cobra.Command{
+		Use:   "keygen",
+		Short: "Generate a threshold key shared among the shareholders",
+		RunE: func(*cobra.Command, []string) error {
+			return keygenCmd.execute()
+		},
+	}
+
+	err := keygenCmd.pedersenFlags.register(&keygenCmd.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	keygenCmd.fileFmtFlags.register(&keygenCmd.Command)
+
+	keygenCmd.PersistentFlags().StringVarP(&keygenCmd.keysFilePattern, "keys", "", "", `key shares files pattern expression.
+Use '*' as placeholder for the index of the share
+(e.g. keys/shareholder-*)`)
+	keygenCmd.PersistentFlags().StringVarP(&keygenCmd.publicFile, "public", "", "", "threshold public key file")
+
+	err = keygenCmd.MarkPersistentFlagRequired("keys")
+	if err != nil {
+		return nil, err
+	}
+
+	err = keygenCmd.MarkPersistentFlagRequired("public")
+	if err != nil {
+		return nil, err
+	}
+
+	return keygenCmd, nil
+}
+
+func (k *KeygenCommand) execute() error {
+	group := pedersen.Group{}
+	if err := readFileAutofmt(k.fs, k.groupFile, &group); err != nil {
+		return err
+	}
+
+	p, err := pedersen.NewPedersen(k.parts, k.threshold, pedersen.CyclicGroup(&group))
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	shares, public, err := frost.KeyGen(p, nil)
+	if err != nil {
+		return err
+	}
+
+	pattern := secretSharesFlags{sharesFilePattern: k.keysFilePattern}
+
+	for i := range shares.Parts {
+		index := i
+		key := schema.KeyShare{
+			Index:        &index,
+			Identifier:   shares.Abscissae[i],
+			SigningShare: shares.Parts[i].SShare,
+		}
+
+		if err := writeFileAutofmt(k.fs, k.fileFmt, pattern.share(i), key, iofs.FileMode(k.filePerm)); err != nil {
+			return err
+		}
+	}
+
+	return writeFileAutofmt(k.fs,
+		k.fileFmt,
+		k.publicFile,
+		schema.PublicKey{
+			Threshold:       public.Threshold,
+			PublicKey:       public.PublicKey,
+			Identifiers:     public.Identifiers,
+			VerifyingShares: public.VerifyingShares,
+		},
+		iofs.FileMode(k.filePerm),
+	)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"fmt"
+	iofs "io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/matteoarella/pedersen/internal/mnemonic"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+const mnemonicWordsPerLine = 6
+
+// mnemonicNumber matches the numbers of the lines of a mnemonic file.
+var mnemonicNumber = regexp.MustCompile(`^[0-9]+[.:)]?$`)
+
+// formatMnemonic arranges the words into numbered lines, for being written on paper.
+func formatMnemonic(words []string) string {
+	var b strings.Builder
+
+	width := len(fmt.Sprintf("%d", len(words)))
+
+	for i := 0; i < len(words); i += mnemonicWordsPerLine {
+		end := i + mnemonicWordsPerLine
+		if end > len(words) {
+			end = len(words)
+		}
+
+		fmt.Fprintf(&b, "%*d. %s\n", width, i+1, strings.Join(words[i:end], " "))
+	}
+
+	return b.String()
+}
+
+// parseMnemonic returns the words of a mnemonic, skipping the line numbers.
+func parseMnemonic(text string) []string {
+	var words []string
+
+	for _, field := range strings.Fields(text) {
+		if mnemonicNumber.MatchString(field) {
+			continue
+		}
+
+		words = append(words, field)
+	}
+
+	return words
+}
+
+type ShareExportCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	shareOpenFlags
+	inFile  string
+	outFile string
+	fs      afero.Fs
+}
+
+func NewShareExportCommand(fs afero.Fs) (*ShareExportCommand, error) {
+	exportCmd := &ShareExportCommand{fs: fs}
+
+	exportCmd.Command = cobra.Command{
+		Use:   "export",
+		Short: "Export a share file to mnemonic words",
+		Long: `Export a share file to mnemonic words, for being written on paper.
+The words encode the whole share, including the shareholder index, the abscissa
+and the split identifier, and the last 3 words are a checksum.
+Words can be abbreviated to their first 4 letters.`,
+		RunE: func(*cobra.Command, []string) error {
+			return exportCmd.execute()
+		},
+	}
+
+	exportCmd.fileFmtFlags.register(&exportCmd.Command)
+	exportCmd.shareOpenFlags.register(&exportCmd.Command)
+
+	exportCmd.PersistentFlags().StringVarP(&exportCmd.inFile, "in", "i", "", "share file")
+	exportCmd.PersistentFlags().StringVarP(&exportCmd.outFile, "out", "o", "", "mnemonic file")
+
+	if err := exportCmd.MarkPersistentFlagRequired("in"); err != nil {
+		return nil, err
+	}
+
+	if err := exportCmd.MarkPersistentFlagRequired("out"); err != nil {
+		return nil, err
+	}
+
+	return exportCmd, nil
+}
+
+func (s *ShareExportCommand) execute() error {
+	share, err := s.readShareFile(s.fs, s.inFile)
+	if err != nil {
+		return err
+	}
+
+	words, err := mnemonic.EncodeShare(share)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(s.fs, s.outFile, []byte(formatMnemonic(words)), iofs.FileMode(s.filePerm))
+}
+
+type ShareImportCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	inFile  string
+	outFile string
+	fs      afero.Fs
+}
+
+func NewShareImportCommand(fs afero.Fs) (*ShareImportCommand, error) {
+	importCmd := &ShareImportCommand{fs: fs}
+
+	importCmd.Command = cobra.Command{
+		Use:   "import",
+		Short: "Import a share file from mnemonic words or QR code content",
+		Long: `Import a share file from mnemonic words, or from the content of the QR code
+of a printed share.
+Mistyped words are reported along with their position, and the checksum detects
+the words which were swapped or replaced by other words of the list.`,
+		RunE: func(*cobra.Command, []string) error {
+			return importCmd.execute()
+		},
+	}
+
+	importCmd.fileFmtFlags.register(&importCmd.Command)
+
+	importCmd.PersistentFlags().StringVarP(&importCmd.inFile, "in", "i", "", "mnemonic or QR code content file")
+	importCmd.PersistentFlags().StringVarP(&importCmd.outFile, "out", "o", "", "share file")
+
+	if err := importCmd.MarkPersistentFlagRequired("in"); err != nil {
+		return nil, err
+	}
+
+	if err := importCmd.MarkPersistentFlagRequired("out"); err != nil {
+		return nil, err
+	}
+
+	return importCmd, nil
+}
+
+func (s *ShareImportCommand) execute() error {
+	text, err := afero.ReadFile(s.fs, s.inFile)
+	if err != nil {
+		return err
+	}
+
+	var share schema.Shares
+
+	if isSharePayload(string(text)) {
+		share, err = parseSharePayload(string(text))
+	} else {
+		share, err = mnemonic.DecodeShare(parseMnemonic(string(text)))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeFileAutofmt(s.fs, s.fileFmt, s.outFile, share, iofs.FileMode(s.filePerm))
+}
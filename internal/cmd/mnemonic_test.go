@@ -0,0 +1,121 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/matteoarella/pedersen/internal/mnemonic"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestShare(t *testing.T, fs afero.Fs, share, words string, args ...string) {
+	t.Helper()
+
+	shareCmd, err := cmd.NewShareCommand(fs)
+	require.NoError(t, err)
+
+	shareCmd.SetArgs(append([]string{"export", "-i", share, "-o", words, "--perm", "600"}, args...))
+	require.NoError(t, shareCmd.Execute())
+}
+
+func importTestShare(fs afero.Fs, words, share string) error {
+	shareCmd, err := cmd.NewShareCommand(fs)
+	if err != nil {
+		return err
+	}
+
+	shareCmd.SetArgs([]string{"import", "-i", words, "-o", share, "--perm", "600"})
+
+	return shareCmd.Execute()
+}
+
+func TestShareMnemonicCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	secret := []byte("secret written on paper")
+
+	splitTestSecret(t, fs, secret, "plain")
+	splitTestSecret(t, fs, secret, "short", "--mode", "short")
+	splitTestSecret(t, fs, secret, "merkle", "--merkle")
+
+	for _, scenario := range []struct {
+		prefix string
+		args   []string
+	}{
+		{prefix: "plain"},
+		{prefix: "short", args: []string{"--mode", "short"}},
+		{prefix: "merkle"},
+	} {
+		t.Run(scenario.prefix, func(t *testing.T) {
+			for _, index := range []string{"0", "2"} {
+				exportTestShare(t, fs, scenario.prefix+"/shareholder-"+index+".yaml", scenario.prefix+"/words-"+index+".txt")
+				require.NoError(t, importTestShare(fs,
+					scenario.prefix+"/words-"+index+".txt",
+					scenario.prefix+"/imported/shareholder-"+index+".yaml",
+				))
+			}
+
+			combineCmd, err := cmd.NewCombineCommand(fs)
+			require.NoError(t, err)
+
+			combineCmd.SetArgs(append([]string{
+				"-g", "group.json", "-o", scenario.prefix + "/reconstructed", "-p", "5", "-t", "3",
+				"--shares", strings.Join([]string{
+					scenario.prefix + "/imported/shareholder-0.yaml",
+					scenario.prefix + "/imported/shareholder-2.yaml",
+					scenario.prefix + "/shareholder-4.yaml",
+				}, ","),
+				"--commitments", scenario.prefix + "/commitments.yaml", "--perm", "600",
+			}, scenario.args...))
+			require.NoError(t, combineCmd.Execute())
+
+			reconstructed, err := afero.ReadFile(fs, scenario.prefix+"/reconstructed")
+			require.NoError(t, err)
+			require.Equal(t, secret, reconstructed)
+
+			verifyCmd, err := cmd.NewVerifyCommand(fs)
+			require.NoError(t, err)
+
+			verifyCmd.SetArgs([]string{
+				"part", "-g", "group.json", "-p", "5", "-t", "3",
+				"--share", scenario.prefix + "/imported/shareholder-2.yaml",
+				"--commitments", scenario.prefix + "/commitments.yaml",
+			})
+			require.NoError(t, verifyCmd.Execute())
+		})
+	}
+
+	text, err := afero.ReadFile(fs, "plain/words-2.txt")
+	require.NoError(t, err)
+
+	fields := strings.Fields(string(text))
+	require.Equal(t, "1.", fields[0])
+
+	// a mistyped word
+	mistyped := strings.Replace(string(text), " "+fields[2]+" ", " "+fields[2]+"x ", 1)
+	require.NoError(t, afero.WriteFile(fs, "plain/mistyped.txt", []byte(mistyped), 0o600))
+	require.ErrorIs(t, importTestShare(fs, "plain/mistyped.txt", "plain/mistyped.yaml"), mnemonic.ErrUnknownWord)
+
+	// two swapped words, written without line numbers
+	lines := strings.SplitN(string(text), "\n", 2)
+	words := strings.Fields(lines[0])[1:]
+	for i := 1; i < len(words); i++ {
+		if words[i] != words[0] {
+			words[0], words[i] = words[i], words[0]
+			break
+		}
+	}
+
+	swapped := strings.Join(words, " ") + "\n" + lines[1]
+	require.NoError(t, afero.WriteFile(fs, "plain/swapped.txt", []byte(swapped), 0o600))
+	require.ErrorIs(t, importTestShare(fs, "plain/swapped.txt", "plain/swapped.yaml"), mnemonic.ErrInvalidChecksum)
+}
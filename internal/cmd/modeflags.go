@@ -0,0 +1,59 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type SharingMode string
+
+const (
+	// PedersenMode splits the whole secret with Pedersen verifiable secret sharing.
+	PedersenMode SharingMode = "pedersen"
+	// ShortMode encrypts the secret, disperses the ciphertext among the shareholders
+	// and splits the encryption key with Pedersen verifiable secret sharing.
+	ShortMode SharingMode = "short"
+)
+
+var (
+	modes = map[string]struct{}{
+		string(PedersenMode): {},
+		string(ShortMode):    {},
+	}
+)
+
+func (m *SharingMode) String() string {
+	return string(*m)
+}
+
+func (m *SharingMode) Set(v string) error {
+	v = strings.ToLower(v)
+	if _, ok := modes[v]; ok {
+		*m = SharingMode(v)
+		return nil
+	}
+
+	return fmt.Errorf("must be one of \"%s\"", strings.Join(keys(modes), "\", \""))
+}
+
+func (m *SharingMode) Type() string {
+	return "SharingMode"
+}
+
+type sharingModeFlags struct {
+	mode SharingMode
+}
+
+func (s *sharingModeFlags) register(cmd *cobra.Command) {
+	s.mode = PedersenMode
+
+	cmd.PersistentFlags().Var(&s.mode, "mode", fmt.Sprintf("sharing mode. allowed: %s", strings.Join(keys(modes), ", ")))
+}
@@ -7,13 +7,22 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	iofs "io/fs"
 	"strings"
 
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/schema"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
+var (
+	ErrMissingShareIndex  = errors.New("share file does not contain the shareholder index")
+	ErrShareIndexMismatch = errors.New("shareholder index of the share file does not match its file name")
+)
+
 type pedersenFlags struct {
 	parts     int
 	threshold int
@@ -53,7 +62,107 @@ func (s *secretSharesFlags) share(index int) string {
 	return strings.ReplaceAll(s.sharesFilePattern, "*", fmt.Sprintf("%d", index))
 }
 
+type shareFilesFlags struct {
+	shareOpenFlags
+	rosterFlags
+	shareFiles      []string
+	commitmentsFile string
+
+	fs afero.Fs
+}
+
+func (s *shareFilesFlags) register(cmd *cobra.Command) error {
+	cmd.PersistentFlags().StringSliceVarP(&s.shareFiles, "shares", "", nil, `secret shares files.
+Either a comma separated list of share files, or a single
+pattern expression using '*' as placeholder for the index
+of the share (e.g. shares/shareholder-*)`)
+	cmd.PersistentFlags().StringVarP(&s.commitmentsFile, "commitments", "", "", "commitments file")
+	s.shareOpenFlags.register(cmd)
+	s.rosterFlags.register(cmd)
+
+	err := cmd.MarkPersistentFlagRequired("shares")
+	if err != nil {
+		return err
+	}
+
+	return cmd.MarkPersistentFlagRequired("commitments")
+}
+
+// readShares reads the shareholders shares.
+// If a pattern expression is provided, every share from index 0 to parts-1 is probed and
+// the missing ones are skipped.
+// Sealed share files are opened with the identity of their recipient, while the
+// passphrase of protected share files is prompted.
+// The share files of weighted shareholders contribute all their bundled shares.
+func (s *shareFilesFlags) readShares(parts int) ([]pedersen.Share, []*schema.Fragment, error) {
+	var (
+		shares    []pedersen.Share
+		fragments []*schema.Fragment
+	)
+
+	if len(s.shareFiles) == 1 && strings.Contains(s.shareFiles[0], "*") {
+		pattern := secretSharesFlags{sharesFilePattern: s.shareFiles[0]}
+
+		for i := 0; i < parts; i++ {
+			share := schema.Shares{}
+
+			if err := readFileAutofmt(s.fs, pattern.share(i), &share); err != nil {
+				if errors.Is(err, iofs.ErrNotExist) {
+					continue
+				}
+
+				return nil, nil, err
+			}
+
+			if err := s.open(s.fs, &share); err != nil {
+				return nil, nil, err
+			}
+
+			if share.Index != nil && *share.Index != i {
+				return nil, nil, ErrShareIndexMismatch
+			}
+
+			holderShares, holderFragments, err := s.unbundle(i, &share)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			shares = append(shares, holderShares...)
+			fragments = append(fragments, holderFragments...)
+		}
+
+		return shares, fragments, nil
+	}
+
+	for _, shareFile := range s.shareFiles {
+		share := schema.Shares{}
+
+		if err := readFileAutofmt(s.fs, shareFile, &share); err != nil {
+			return nil, nil, err
+		}
+
+		if err := s.open(s.fs, &share); err != nil {
+			return nil, nil, err
+		}
+
+		if share.Index == nil {
+			return nil, nil, ErrMissingShareIndex
+		}
+
+		holderShares, holderFragments, err := s.unbundle(*share.Index, &share)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		shares = append(shares, holderShares...)
+		fragments = append(fragments, holderFragments...)
+	}
+
+	return shares, fragments, nil
+}
+
 type secretShareFlags struct {
+	shareOpenFlags
 	shareFile       string
 	commitmentsFile string
 }
@@ -61,6 +170,7 @@ type secretShareFlags struct {
 func (s *secretShareFlags) register(cmd *cobra.Command) error {
 	cmd.PersistentFlags().StringVarP(&s.shareFile, "share", "", "", "secret shares file")
 	cmd.PersistentFlags().StringVarP(&s.commitmentsFile, "commitments", "", "", "commitments file")
+	s.shareOpenFlags.register(cmd)
 
 	err := cmd.MarkPersistentFlagRequired("share")
 	if err != nil {
@@ -69,3 +179,9 @@ func (s *secretShareFlags) register(cmd *cobra.Command) error {
 
 	return cmd.MarkPersistentFlagRequired("commitments")
 }
+
+// readShare reads the share of the shareholder, opening it with the identity of
+// its recipient if it is sealed, or with the prompted passphrase if it is protected.
+func (s *secretShareFlags) readShare(fs afero.Fs) (schema.Shares, error) {
+	return s.readShareFile(fs, s.shareFile)
+}
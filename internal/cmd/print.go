@@ -0,0 +1,303 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	iofs "io/fs"
+	"strings"
+
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/skip2/go-qrcode"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// sharePayloadPrefix prefixes the QR code payload of a share. The payload
+	// uses only characters of the QR alphanumeric mode, which is the most compact
+	// mode for base32 data.
+	sharePayloadPrefix = "PEDERSEN-SHARE:1:"
+
+	shareChecksumLen = 10
+)
+
+var (
+	ErrInvalidSharePayload  = errors.New("invalid share QR code payload")
+	ErrSharePayloadChecksum = errors.New("share QR code payload checksum mismatch")
+)
+
+var payloadEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// shareChecksum returns the checksum of the encoded share.
+func shareChecksum(data []byte) string {
+	digest := sha256.Sum256(data)
+
+	return payloadEncoding.EncodeToString(digest[:shareChecksumLen])
+}
+
+// sharePayload returns the QR code payload of the share, which carries its
+// binary encoding followed by its checksum.
+func sharePayload(share schema.Shares) (string, error) {
+	data, err := share.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	return sharePayloadPrefix + payloadEncoding.EncodeToString(data) + ":" + shareChecksum(data), nil
+}
+
+// isSharePayload reports whether the text is the content of a share QR code.
+func isSharePayload(text string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(text)), sharePayloadPrefix)
+}
+
+// parseSharePayload returns the share carried by the QR code payload, after
+// checking its checksum. White spaces introduced by line wrapping are ignored.
+func parseSharePayload(text string) (schema.Shares, error) {
+	payload := strings.ToUpper(strings.Join(strings.Fields(text), ""))
+	if !strings.HasPrefix(payload, sharePayloadPrefix) {
+		return schema.Shares{}, ErrInvalidSharePayload
+	}
+
+	fields := strings.Split(strings.TrimPrefix(payload, sharePayloadPrefix), ":")
+	if len(fields) != 2 {
+		return schema.Shares{}, ErrInvalidSharePayload
+	}
+
+	data, err := payloadEncoding.DecodeString(fields[0])
+	if err != nil {
+		return schema.Shares{}, ErrInvalidSharePayload
+	}
+
+	if subtle.ConstantTimeCompare([]byte(shareChecksum(data)), []byte(fields[1])) != 1 {
+		return schema.Shares{}, ErrSharePayloadChecksum
+	}
+
+	share := schema.Shares{}
+	if err := share.UnmarshalBinary(data); err != nil {
+		return schema.Shares{}, err
+	}
+
+	return share, nil
+}
+
+// groups splits the string into groups of the provided size, separated by the separator.
+func groups(s string, size int, sep string) string {
+	var res []string
+
+	for len(s) > size {
+		res = append(res, s[:size])
+		s = s[size:]
+	}
+
+	return strings.Join(append(res, s), sep)
+}
+
+// qrCodeSVG renders the QR code of the payload as an inline SVG image.
+func qrCodeSVG(payload string) (template.HTML, error) {
+	code, err := qrcode.New(payload, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+
+	bitmap := code.Bitmap()
+
+	var path strings.Builder
+
+	for y, row := range bitmap {
+		for x, black := range row {
+			if black {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	// the path is made only of numbers, so it does not need escaping
+	return template.HTML(fmt.Sprintf( //nolint:gosec
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %[1]d %[1]d" shape-rendering="crispEdges">`+
+			`<rect width="%[1]d" height="%[1]d" fill="#fff"/><path d="%[2]s" fill="#000"/></svg>`,
+		len(bitmap), path.String(),
+	)), nil
+}
+
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Pedersen share {{.Index}}{{if .Name}} - {{.Name}}{{end}}</title>
+<style>
+body { font-family: sans-serif; margin: 2cm; color: #000; }
+table { border-collapse: collapse; margin-bottom: 1cm; }
+th, td { border: 1px solid #000; padding: 0.2cm 0.4cm; text-align: left; vertical-align: top; }
+code, pre { font-family: monospace; font-size: 1.1em; }
+.qr { width: 9cm; height: 9cm; }
+.payload { white-space: pre-wrap; word-break: break-all; font-size: 0.8em; }
+@media print { body { margin: 1cm; } }
+</style>
+</head>
+<body>
+<h1>Pedersen secret share</h1>
+<table>
+<tr><th>Shareholder</th><td>{{.Name}}</td></tr>
+<tr><th>Shareholder index</th><td>{{.Index}}</td></tr>
+{{- if .Weight}}
+<tr><th>Weight</th><td>{{.Weight}}</td></tr>
+{{- end}}
+<tr><th>Split ID</th><td><code>{{.SplitID}}</code></td></tr>
+<tr><th>Sharing mode</th><td>{{.Mode}}</td></tr>
+<tr><th>Chunks</th><td>{{.Chunks}}</td></tr>
+<tr><th>Share checksum</th><td><code>{{.Checksum}}</code></td></tr>
+{{- if .Commitments}}
+<tr><th>Commitments SHA-256</th><td><code>{{.Commitments}}</code></td></tr>
+{{- end}}
+</table>
+<div class="qr">{{.QRCode}}</div>
+<p>Import the content of the QR code with <code>pedersen share import</code>,
+and check that the share checksum matches.</p>
+<pre class="payload">{{.Payload}}</pre>
+</body>
+</html>
+`))
+
+type sharePage struct {
+	Name        string
+	Index       int
+	Weight      int
+	SplitID     string
+	Mode        string
+	Chunks      int
+	Checksum    string
+	Commitments string
+	QRCode      template.HTML
+	Payload     string
+}
+
+type SharePrintCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	shareOpenFlags
+	inFile          string
+	commitmentsFile string
+	name            string
+	outFile         string
+	fs              afero.Fs
+}
+
+func NewSharePrintCommand(fs afero.Fs) (*SharePrintCommand, error) {
+	printCmd := &SharePrintCommand{fs: fs}
+
+	printCmd.Command = cobra.Command{
+		Use:   "print",
+		Short: "Render a share file into a printable page",
+		Long: `Render a share file into a self-contained printable HTML page.
+The page carries the QR code of the share, the checksum of the share, the
+shareholder name and the split metadata, and the SHA-256 digest of the
+commitments file, if provided.
+The content of the QR code can be imported with 'pedersen share import'.`,
+		RunE: func(*cobra.Command, []string) error {
+			return printCmd.execute()
+		},
+	}
+
+	printCmd.fileFmtFlags.register(&printCmd.Command)
+	printCmd.shareOpenFlags.register(&printCmd.Command)
+
+	printCmd.PersistentFlags().StringVarP(&printCmd.inFile, "in", "i", "", "share file")
+	printCmd.PersistentFlags().StringVarP(&printCmd.commitmentsFile, "commitments", "", "", "commitments file")
+	printCmd.PersistentFlags().StringVarP(&printCmd.name, "name", "", "", "shareholder name")
+	printCmd.PersistentFlags().StringVarP(&printCmd.outFile, "out", "o", "", "HTML file")
+
+	if err := printCmd.MarkPersistentFlagRequired("in"); err != nil {
+		return nil, err
+	}
+
+	if err := printCmd.MarkPersistentFlagRequired("out"); err != nil {
+		return nil, err
+	}
+
+	return printCmd, nil
+}
+
+func (s *SharePrintCommand) execute() error {
+	share, err := s.readShareFile(s.fs, s.inFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := share.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	payload, err := sharePayload(share)
+	if err != nil {
+		return err
+	}
+
+	qrCode, err := qrCodeSVG(payload)
+	if err != nil {
+		return err
+	}
+
+	page := sharePage{
+		Name:     s.name,
+		Index:    *share.Index,
+		SplitID:  base64.StdEncoding.EncodeToString(share.SplitID),
+		Mode:     string(PedersenMode),
+		Chunks:   len(share.Parts),
+		Checksum: groups(shareChecksum(data), 4, "-"),
+		QRCode:   qrCode,
+		Payload:  payload,
+	}
+
+	// the share file of a weighted shareholder is printed with all its bundled shares
+	sample := share
+	if len(share.Bundle) > 0 {
+		sample = share.Bundle[0]
+		page.Weight = len(share.Bundle)
+		page.Chunks = len(sample.Parts)
+
+		if page.Name == "" {
+			page.Name = share.Holder
+		}
+	}
+
+	if sample.Fragment != nil {
+		page.Mode = string(ShortMode)
+	}
+
+	if len(share.Proofs) > 0 {
+		page.Mode += ", merkle commitments"
+	}
+
+	if s.commitmentsFile != "" {
+		commitments, err := afero.ReadFile(s.fs, s.commitmentsFile)
+		if err != nil {
+			return err
+		}
+
+		digest := sha256.Sum256(commitments)
+		page.Commitments = groups(hex.EncodeToString(digest[:]), 8, " ")
+	}
+
+	var buf bytes.Buffer
+	if err := sharePageTemplate.Execute(&buf, page); err != nil {
+		return err
+	}
+
+	return afero.WriteFile(s.fs, s.outFile, buf.Bytes(), iofs.FileMode(s.filePerm))
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+var printedPayload = regexp.MustCompile(`<pre class="payload">([^<]*)</pre>`)
+
+// wrapTestPayload wraps the payload, as a QR code scanner or an email client may do.
+func wrapTestPayload(payload string, width int) string {
+	var b strings.Builder
+
+	for len(payload) > width {
+		b.WriteString(payload[:width] + "\n")
+		payload = payload[width:]
+	}
+
+	b.WriteString(payload + "\n")
+
+	return b.String()
+}
+
+func TestSharePrintCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	secret := []byte("secret kept in a safe")
+	splitTestSecret(t, fs, secret, "plain")
+	splitTestSecret(t, fs, secret, "merkle", "--merkle")
+
+	commitments, err := afero.ReadFile(fs, "plain/commitments.yaml")
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(commitments)
+
+	for _, prefix := range []string{"plain", "merkle"} {
+		for _, index := range []string{"1", "3"} {
+			shareCmd, err := cmd.NewShareCommand(fs)
+			require.NoError(t, err)
+
+			shareCmd.SetArgs([]string{
+				"print", "-i", prefix + "/shareholder-" + index + ".yaml",
+				"--commitments", prefix + "/commitments.yaml", "--name", "Alice <CFO>",
+				"-o", prefix + "/shareholder-" + index + ".html", "--perm", "600",
+			})
+			require.NoError(t, shareCmd.Execute())
+
+			page, err := afero.ReadFile(fs, prefix+"/shareholder-"+index+".html")
+			require.NoError(t, err)
+			require.Contains(t, string(page), "<svg")
+			require.Contains(t, string(page), "Alice &lt;CFO&gt;")
+
+			if prefix == "plain" {
+				require.Contains(t, string(page), hex.EncodeToString(digest[:4]))
+			}
+
+			match := printedPayload.FindStringSubmatch(string(page))
+			require.Len(t, match, 2)
+
+			require.NoError(t, afero.WriteFile(fs, prefix+"/payload-"+index+".txt", []byte(wrapTestPayload(match[1], 40)), 0o600))
+			require.NoError(t, importTestShare(fs, prefix+"/payload-"+index+".txt", prefix+"/imported/shareholder-"+index+".yaml"))
+		}
+
+		combineCmd, err := cmd.NewCombineCommand(fs)
+		require.NoError(t, err)
+
+		combineCmd.SetArgs([]string{
+			"-g", "group.json", "-o", prefix + "/reconstructed", "-p", "5", "-t", "3",
+			"--shares", prefix + "/imported/shareholder-1.yaml," + prefix + "/imported/shareholder-3.yaml," + prefix + "/shareholder-0.yaml",
+			"--commitments", prefix + "/commitments.yaml", "--perm", "600",
+		})
+		require.NoError(t, combineCmd.Execute())
+
+		reconstructed, err := afero.ReadFile(fs, prefix+"/reconstructed")
+		require.NoError(t, err)
+		require.Equal(t, secret, reconstructed)
+	}
+
+	payload, err := afero.ReadFile(fs, "plain/payload-1.txt")
+	require.NoError(t, err)
+
+	// a payload whose data does not match its checksum
+	tampered := []byte(strings.ToUpper(string(payload)))
+	position := len("PEDERSEN-SHARE:1:") + 8
+	if tampered[position] == 'A' {
+		tampered[position] = 'B'
+	} else {
+		tampered[position] = 'A'
+	}
+
+	require.NoError(t, afero.WriteFile(fs, "plain/tampered.txt", tampered, 0o600))
+	require.ErrorIs(t, importTestShare(fs, "plain/tampered.txt", "plain/tampered.yaml"), cmd.ErrSharePayloadChecksum)
+}
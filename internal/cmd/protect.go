@@ -0,0 +1,282 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"os"
+	"strings"
+
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/matteoarella/pedersen/internal/seal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	ErrPassphraseMismatch = errors.New("passphrases do not match")
+	ErrProtectedShare     = errors.New("protected share does not match the share file")
+)
+
+// passphrasePrompt reads passphrases from the input of the command.
+// When the input is a terminal the passphrase is read without echo, otherwise
+// it is read from a line of the input, so that passphrases can be piped.
+type passphrasePrompt struct {
+	cmd    *cobra.Command
+	reader *bufio.Reader
+}
+
+func (p *passphrasePrompt) read(prompt string) ([]byte, error) {
+	in := p.cmd.InOrStdin()
+
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprint(p.cmd.ErrOrStderr(), prompt)
+		defer fmt.Fprintln(p.cmd.ErrOrStderr())
+
+		passphrase, err := term.ReadPassword(int(f.Fd()))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(passphrase) == 0 {
+			return nil, seal.ErrEmptyPassphrase
+		}
+
+		return passphrase, nil
+	}
+
+	if p.reader == nil {
+		p.reader = bufio.NewReader(in)
+	}
+
+	line, err := p.reader.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	passphrase := strings.TrimRight(line, "\r\n")
+	if passphrase == "" {
+		return nil, seal.ErrEmptyPassphrase
+	}
+
+	return []byte(passphrase), nil
+}
+
+// readNew reads a new passphrase of the shareholder, which must be confirmed.
+func (p *passphrasePrompt) readNew(index int) ([]byte, error) {
+	passphrase, err := p.read(fmt.Sprintf("New passphrase for shareholder %d: ", index))
+	if err != nil {
+		return nil, err
+	}
+
+	confirmation, err := p.read(fmt.Sprintf("Confirm passphrase for shareholder %d: ", index))
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(passphrase, confirmation) {
+		return nil, ErrPassphraseMismatch
+	}
+
+	return passphrase, nil
+}
+
+// protectShare returns the share file which carries the share protected with
+// the passphrase. Only the shareholder index and the parameters of the key
+// derivation function are left in plaintext.
+func protectShare(share schema.Shares, passphrase []byte) (schema.Shares, error) {
+	if share.Index == nil {
+		return schema.Shares{}, ErrMissingShareIndex
+	}
+
+	params, err := seal.NewKDFParams()
+	if err != nil {
+		return schema.Shares{}, err
+	}
+
+	plaintext, err := json.Marshal(share)
+	if err != nil {
+		return schema.Shares{}, err
+	}
+
+	data, err := seal.Protect(passphrase, params, plaintext, sealAdditionalData(*share.Index))
+	if err != nil {
+		return schema.Shares{}, err
+	}
+
+	return schema.Shares{
+		Index: share.Index,
+		Protected: &schema.Protected{
+			KDF: schema.KDF{
+				Name: params.Name,
+				Salt: params.Salt,
+				LogN: params.LogN,
+				R:    params.R,
+				P:    params.P,
+			},
+			Data: data,
+		},
+	}, nil
+}
+
+// unprotect replaces the protected share file with the share it carries, by
+// prompting the passphrase of the shareholder.
+// Share files which are not protected are left untouched.
+func (p *passphrasePrompt) unprotect(share *schema.Shares) error {
+	if share.Protected == nil {
+		return nil
+	}
+
+	if share.Index == nil {
+		return ErrMissingShareIndex
+	}
+
+	passphrase, err := p.read(fmt.Sprintf("Passphrase for shareholder %d: ", *share.Index))
+	if err != nil {
+		return err
+	}
+
+	kdf := share.Protected.KDF
+
+	plaintext, err := seal.Unprotect(passphrase,
+		seal.KDFParams{
+			Name: kdf.Name,
+			Salt: kdf.Salt,
+			LogN: kdf.LogN,
+			R:    kdf.R,
+			P:    kdf.P,
+		},
+		share.Protected.Data,
+		sealAdditionalData(*share.Index),
+	)
+	if err != nil {
+		return err
+	}
+
+	opened := schema.Shares{}
+	if err := json.Unmarshal(plaintext, &opened); err != nil {
+		return err
+	}
+
+	if opened.Index == nil || *opened.Index != *share.Index || opened.Sealed != nil || opened.Protected != nil {
+		return ErrProtectedShare
+	}
+
+	*share = opened
+
+	return nil
+}
+
+// shareOpenFlags opens sealed and protected share files.
+type shareOpenFlags struct {
+	identityFlags
+	passphrases passphrasePrompt
+}
+
+func (s *shareOpenFlags) register(cmd *cobra.Command) {
+	s.identityFlags.register(cmd)
+	s.passphrases.cmd = cmd
+}
+
+// open replaces the share file with the share it carries.
+// A share which is both protected and sealed for its recipient is unsealed first.
+func (s *shareOpenFlags) open(fs afero.Fs, share *schema.Shares) error {
+	if err := s.unseal(fs, share); err != nil {
+		return err
+	}
+
+	return s.passphrases.unprotect(share)
+}
+
+// readShareFile reads the share file, and replaces it with the share it carries.
+func (s *shareOpenFlags) readShareFile(fs afero.Fs, name string) (schema.Shares, error) {
+	share := schema.Shares{}
+	if err := readFileAutofmt(fs, name, &share); err != nil {
+		return schema.Shares{}, err
+	}
+
+	if err := s.open(fs, &share); err != nil {
+		return schema.Shares{}, err
+	}
+
+	return share, nil
+}
+
+type ShareProtectCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	shareOpenFlags
+	inFile  string
+	outFile string
+	fs      afero.Fs
+}
+
+func NewShareProtectCommand(fs afero.Fs) (*ShareProtectCommand, error) {
+	protectCmd := &ShareProtectCommand{fs: fs}
+
+	protectCmd.Command = cobra.Command{
+		Use:   "protect",
+		Short: "Protect a share file with a passphrase",
+		Long: `Protect a share file with a passphrase.
+The share is encrypted with a key derived from the passphrase with scrypt.
+A share file which is already protected is protected with the new passphrase.`,
+		RunE: func(*cobra.Command, []string) error {
+			return protectCmd.execute()
+		},
+	}
+
+	protectCmd.fileFmtFlags.register(&protectCmd.Command)
+	protectCmd.shareOpenFlags.register(&protectCmd.Command)
+
+	protectCmd.PersistentFlags().StringVarP(&protectCmd.inFile, "in", "i", "", "share file")
+	protectCmd.PersistentFlags().StringVarP(&protectCmd.outFile, "out", "o", "", "protected share file")
+
+	if err := protectCmd.MarkPersistentFlagRequired("in"); err != nil {
+		return nil, err
+	}
+
+	if err := protectCmd.MarkPersistentFlagRequired("out"); err != nil {
+		return nil, err
+	}
+
+	return protectCmd, nil
+}
+
+func (s *ShareProtectCommand) execute() error {
+	share, err := s.readShareFile(s.fs, s.inFile)
+	if err != nil {
+		return err
+	}
+
+	if share.Index == nil {
+		return ErrMissingShareIndex
+	}
+
+	passphrase, err := s.passphrases.readNew(*share.Index)
+	if err != nil {
+		return err
+	}
+
+	protected, err := protectShare(share, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAutofmt(s.fs, s.fileFmt, s.outFile, protected, iofs.FileMode(s.filePerm))
+}
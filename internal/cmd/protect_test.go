@@ -0,0 +1,213 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/matteoarella/pedersen/internal/io/yaml"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/matteoarella/pedersen/internal/seal"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// testPassphrases returns the input with the passphrases of the shareholders,
+// each repeated as many times as requested.
+func testPassphrases(repeat int, indexes ...int) *strings.Reader {
+	var b strings.Builder
+
+	for _, index := range indexes {
+		for i := 0; i < repeat; i++ {
+			fmt.Fprintf(&b, "passphrase of shareholder %d\n", index)
+		}
+	}
+
+	return strings.NewReader(b.String())
+}
+
+func TestProtectedSharesCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+	writeTestRecipients(t, fs, 5)
+
+	secret := []byte("protected secret")
+	require.NoError(t, afero.WriteFile(fs, "secret", secret, 0o600))
+
+	for _, prefix := range []string{"protected", "sealed"} {
+		splitCmd, err := cmd.NewSplitCommand(fs)
+		require.NoError(t, err)
+
+		args := []string{
+			"-g", "group.json", "-i", "secret", "-p", "5", "-t", "3",
+			"--shares", prefix + "/shareholder-*.yaml", "--commitments", prefix + "/commitments.yaml",
+			"--protect", "--perm", "600",
+		}
+		if prefix == "sealed" {
+			args = append(args, "--recipients", "recipients.yaml")
+		}
+
+		splitCmd.SetIn(testPassphrases(2, 0, 1, 2, 3, 4))
+		splitCmd.SetArgs(args)
+		require.NoError(t, splitCmd.Execute())
+	}
+
+	share := schema.Shares{}
+	require.NoError(t, yaml.New(fs).ReadFile("protected/shareholder-2.yaml", &share))
+	require.NotNil(t, share.Protected)
+	require.Equal(t, seal.ScryptKDF, share.Protected.KDF.Name)
+	require.Nil(t, share.Abscissa)
+	require.Empty(t, share.Parts)
+
+	shares := "/shareholder-4.yaml,%[1]s/shareholder-0.yaml,%[1]s/shareholder-2.yaml"
+
+	for _, scenario := range []struct {
+		description string
+		prefix      string
+		passphrases *strings.Reader
+		args        []string
+		err         error
+	}{
+		{
+			description: "combine protected shares",
+			prefix:      "protected",
+			passphrases: testPassphrases(1, 4, 0, 2),
+		},
+		{
+			description: "combine protected shares with a wrong passphrase",
+			prefix:      "protected",
+			passphrases: testPassphrases(1, 4, 1, 2),
+			err:         seal.ErrOpen,
+		},
+		{
+			description: "combine protected shares with missing passphrases",
+			prefix:      "protected",
+			passphrases: testPassphrases(1, 4),
+			err:         io.ErrUnexpectedEOF,
+		},
+		{
+			description: "combine protected and sealed shares",
+			prefix:      "sealed",
+			passphrases: testPassphrases(1, 4, 0, 2),
+			args: []string{
+				"--identity", "identities/shareholder-4.yaml,identities/shareholder-0.yaml,identities/shareholder-2.yaml",
+			},
+		},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			combineCmd, err := cmd.NewCombineCommand(fs)
+			require.NoError(t, err)
+
+			combineCmd.SetIn(scenario.passphrases)
+			combineCmd.SetArgs(append([]string{
+				"-g", "group.json", "-o", scenario.prefix + "/reconstructed", "-p", "5", "-t", "3",
+				"--shares", scenario.prefix + fmt.Sprintf(shares, scenario.prefix),
+				"--commitments", scenario.prefix + "/commitments.yaml", "--perm", "600",
+			}, scenario.args...))
+
+			err = combineCmd.Execute()
+			if scenario.err != nil {
+				require.ErrorIs(t, err, scenario.err)
+				return
+			}
+			require.NoError(t, err)
+
+			reconstructed, err := afero.ReadFile(fs, scenario.prefix+"/reconstructed")
+			require.NoError(t, err)
+			require.Equal(t, secret, reconstructed)
+		})
+	}
+
+	verifyCmd, err := cmd.NewVerifyCommand(fs)
+	require.NoError(t, err)
+
+	verifyCmd.SetIn(testPassphrases(1, 2))
+	verifyCmd.SetArgs([]string{
+		"part", "-g", "group.json", "-p", "5", "-t", "3",
+		"--share", "protected/shareholder-2.yaml", "--commitments", "protected/commitments.yaml",
+	})
+	require.NoError(t, verifyCmd.Execute())
+}
+
+func TestShareProtectCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	splitTestSecret(t, fs, []byte("secret"), "plain")
+
+	shareCmd, err := cmd.NewShareCommand(fs)
+	require.NoError(t, err)
+
+	shareCmd.SetIn(strings.NewReader("first passphrase\nsecond passphrase\n"))
+	shareCmd.SetArgs([]string{"protect", "-i", "plain/shareholder-3.yaml", "-o", "protected/shareholder-3.yaml"})
+	require.ErrorIs(t, shareCmd.Execute(), cmd.ErrPassphraseMismatch)
+
+	shareCmd, err = cmd.NewShareCommand(fs)
+	require.NoError(t, err)
+
+	shareCmd.SetIn(testPassphrases(2, 3))
+	shareCmd.SetArgs([]string{
+		"protect", "-i", "plain/shareholder-3.yaml", "-o", "protected/shareholder-3.yaml", "--perm", "600",
+	})
+	require.NoError(t, shareCmd.Execute())
+
+	// changing the passphrase of a protected share
+	shareCmd, err = cmd.NewShareCommand(fs)
+	require.NoError(t, err)
+
+	shareCmd.SetIn(strings.NewReader("passphrase of shareholder 3\nnew passphrase\nnew passphrase\n"))
+	shareCmd.SetArgs([]string{
+		"protect", "-i", "protected/shareholder-3.yaml", "-o", "changed/shareholder-3.yaml", "--perm", "600",
+	})
+	require.NoError(t, shareCmd.Execute())
+
+	for _, scenario := range []struct {
+		description string
+		share       string
+		passphrase  string
+		err         error
+	}{
+		{
+			description: "verify protected part",
+			share:       "protected/shareholder-3.yaml",
+			passphrase:  "passphrase of shareholder 3\n",
+		},
+		{
+			description: "verify protected part with changed passphrase",
+			share:       "changed/shareholder-3.yaml",
+			passphrase:  "new passphrase\n",
+		},
+		{
+			description: "verify protected part with previous passphrase",
+			share:       "changed/shareholder-3.yaml",
+			passphrase:  "passphrase of shareholder 3\n",
+			err:         seal.ErrOpen,
+		},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			verifyCmd, err := cmd.NewVerifyCommand(fs)
+			require.NoError(t, err)
+
+			verifyCmd.SetIn(strings.NewReader(scenario.passphrase))
+			verifyCmd.SetArgs([]string{
+				"part", "-g", "group.json", "-p", "5", "-t", "3",
+				"--share", scenario.share, "--commitments", "plain/commitments.yaml",
+			})
+
+			err = verifyCmd.Execute()
+			if scenario.err != nil {
+				require.ErrorIs(t, err, scenario.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -70,11 +70,53 @@ func NewRootCommand(fs afero.Fs) (*RootCommand, error) {
 		return nil, err
 	}
 
+	keygenCmd, err := NewKeygenCommand(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptCmd, err := NewEncryptCommand(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	decryptShareCmd, err := NewDecryptShareCommand(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	decryptCombineCmd, err := NewDecryptCombineCommand(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	attestCmd, err := NewAttestCommand(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	identityCmd, err := NewIdentityCommand(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	shareCmd, err := NewShareCommand(fs)
+	if err != nil {
+		return nil, err
+	}
+
 	rootCmd.AddCommand(&versionCmd.Command,
 		&generateCmd.Command,
 		&splitCmd.Command,
 		&verifyCmd.Command,
 		&combineCmd.Command,
+		&keygenCmd.Command,
+		&encryptCmd.Command,
+		&decryptShareCmd.Command,
+		&decryptCombineCmd.Command,
+		&attestCmd.Command,
+		&identityCmd.Command,
+		&shareCmd.Command,
 	)
 
 	return rootCmd, nil
@@ -0,0 +1,166 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ErrMissingHolder    = errors.New("roster shareholder must have a name")
+	ErrDuplicateHolder  = errors.New("duplicate roster shareholder")
+	ErrHolderMismatch   = errors.New("share file does not match the roster shareholder")
+	ErrMissingRoster    = errors.New("share files of weighted shareholders require the roster")
+	ErrUnexpectedBundle = errors.New("share file bundles the shares of a weighted shareholder")
+)
+
+// rosterFlags reads the roster of the weighted shareholders, whose weights are
+// arranged by [pedersen.Roster].
+type rosterFlags struct {
+	rosterFile string
+	roster     *schema.Roster
+	weights    *pedersen.Roster
+}
+
+func (r *rosterFlags) register(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&r.rosterFile, "roster", "", "", `roster file of the weighted shareholders, where every
+shareholder has as many parts as its weight.
+Parts are the sum of the weights, and the threshold
+counts the weight of the shareholders`)
+}
+
+// readRoster reads and validates the roster, if provided, and sets the parts
+// to the sum of the weights of the shareholders.
+func (r *rosterFlags) readRoster(fs afero.Fs, parts *int) error {
+	if r.rosterFile == "" {
+		return nil
+	}
+
+	roster := schema.Roster{}
+	if err := readFileAutofmt(fs, r.rosterFile, &roster); err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(roster.Holders))
+	weights := make([]int, len(roster.Holders))
+
+	for i, holder := range roster.Holders {
+		if holder.Name == "" {
+			return ErrMissingHolder
+		}
+
+		if _, ok := names[holder.Name]; ok {
+			return ErrDuplicateHolder
+		}
+
+		names[holder.Name] = struct{}{}
+		weights[i] = holder.Weight
+	}
+
+	w, err := pedersen.NewRoster(weights...)
+	if err != nil {
+		return err
+	}
+
+	r.roster = &roster
+	r.weights = w
+	*parts = w.Parts()
+
+	return nil
+}
+
+// bundle arranges the share files of every shareholder index into the share
+// files of the roster shareholders, each one bundling as many shares as its weight.
+// Without a roster every share file carries the share of a single index.
+func (r *rosterFlags) bundle(shares *pedersen.Shares, shareFiles []schema.Shares) ([]schema.Shares, error) {
+	if r.roster == nil {
+		return shareFiles, nil
+	}
+
+	holders, err := r.weights.Bundle(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := make([]schema.Shares, len(holders))
+
+	for i, holder := range holders {
+		index := holder.Holder
+
+		bundle := schema.Shares{
+			Index:   &index,
+			Holder:  r.roster.Holders[holder.Holder].Name,
+			SplitID: shares.SplitID,
+			Proofs:  shareFiles[holder.Shares[0].Index].Proofs,
+		}
+
+		for _, share := range holder.Shares {
+			bundled := shareFiles[share.Index]
+			bundled.SplitID = nil
+			bundled.Proofs = nil
+			bundle.Bundle = append(bundle.Bundle, bundled)
+		}
+
+		bundles[i] = bundle
+	}
+
+	return bundles, nil
+}
+
+// unbundle returns the shares carried by the share file with the provided index,
+// along with their fragments.
+// With a roster every share file must be the share file of the roster shareholder
+// with the provided index, whose bundled shares must have the shareholder indices
+// assigned to it, while share files of weighted shareholders are rejected without
+// a roster, since nothing would bind their bundled shares to the shareholder.
+func (r *rosterFlags) unbundle(index int, share *schema.Shares) ([]pedersen.Share, []*schema.Fragment, error) {
+	if r.roster == nil {
+		if len(share.Bundle) > 0 {
+			return nil, nil, ErrMissingRoster
+		}
+
+		return []pedersen.Share{toShare(index, share)}, []*schema.Fragment{share.Fragment}, nil
+	}
+
+	if index < 0 || index >= len(r.roster.Holders) {
+		return nil, nil, pedersen.ErrInvalidShareholder
+	}
+
+	if len(share.Bundle) == 0 || share.Holder != r.roster.Holders[index].Name {
+		return nil, nil, ErrHolderMismatch
+	}
+
+	holder := pedersen.WeightedShare{
+		Holder: index,
+		Shares: make([]pedersen.Share, len(share.Bundle)),
+	}
+	fragments := make([]*schema.Fragment, len(share.Bundle))
+
+	for i, bundled := range share.Bundle {
+		if bundled.Index == nil {
+			return nil, nil, ErrMissingShareIndex
+		}
+
+		bundled.SplitID = share.SplitID
+		bundled.Proofs = share.Proofs
+
+		holder.Shares[i] = toShare(*bundled.Index, &bundled)
+		fragments[i] = bundled.Fragment
+	}
+
+	shares, err := r.weights.Unbundle(holder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return shares, fragments, nil
+}
@@ -0,0 +1,258 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/matteoarella/pedersen/internal/io/yaml"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestRoster(t *testing.T, fs afero.Fs, name string, holders ...schema.Holder) {
+	t.Helper()
+
+	require.NoError(t, yaml.New(fs).WriteFile(name, schema.Roster{Holders: holders}, 0o600))
+}
+
+func TestWeightedSharesCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	writeTestRoster(t, fs, "roster.yaml",
+		schema.Holder{Name: "cfo", Weight: 2},
+		schema.Holder{Name: "alice", Weight: 1},
+		schema.Holder{Name: "bob", Weight: 1},
+		schema.Holder{Name: "carol", Weight: 1},
+	)
+
+	secret := []byte("secret of weighted shareholders")
+	splitTestSecret(t, fs, secret, "weighted", "--roster", "roster.yaml")
+
+	share := schema.Shares{}
+	require.NoError(t, yaml.New(fs).ReadFile("weighted/shareholder-0.yaml", &share))
+	require.Equal(t, 0, *share.Index)
+	require.Equal(t, "cfo", share.Holder)
+	require.NotEmpty(t, share.SplitID)
+	require.Len(t, share.Bundle, 2)
+
+	for i, bundled := range share.Bundle {
+		require.Equal(t, i, *bundled.Index)
+		require.NotNil(t, bundled.Abscissa)
+		require.NotEmpty(t, bundled.Parts)
+	}
+
+	share = schema.Shares{}
+	require.NoError(t, yaml.New(fs).ReadFile("weighted/shareholder-3.yaml", &share))
+	require.Equal(t, "carol", share.Holder)
+	require.Len(t, share.Bundle, 1)
+	require.Equal(t, 4, *share.Bundle[0].Index)
+
+	exists, err := afero.Exists(fs, "weighted/shareholder-4.yaml")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	combine := func(shares string, args ...string) error {
+		combineCmd, err := cmd.NewCombineCommand(fs)
+		require.NoError(t, err)
+
+		combineCmd.SetArgs(append([]string{
+			"-g", "group.json", "-o", "weighted/reconstructed", "-p", "5", "-t", "3",
+			"--shares", shares, "--commitments", "weighted/commitments.yaml", "--perm", "600",
+		}, args...))
+
+		return combineCmd.Execute()
+	}
+
+	for _, scenario := range []struct {
+		scenario string
+		shares   string
+		args     []string
+		err      bool
+	}{
+		{
+			scenario: "weighted shareholder with another shareholder",
+			shares:   "weighted/shareholder-0.yaml,weighted/shareholder-2.yaml",
+			args:     []string{"--roster", "roster.yaml"},
+		},
+		{
+			scenario: "unit shareholders",
+			shares:   "weighted/shareholder-1.yaml,weighted/shareholder-2.yaml,weighted/shareholder-3.yaml",
+			args:     []string{"--roster", "roster.yaml"},
+		},
+		{
+			scenario: "pattern expression",
+			shares:   "weighted/shareholder-*.yaml",
+			args:     []string{"--roster", "roster.yaml"},
+		},
+		{
+			scenario: "weight below threshold",
+			shares:   "weighted/shareholder-1.yaml,weighted/shareholder-3.yaml",
+			args:     []string{"--roster", "roster.yaml"},
+			err:      true,
+		},
+	} {
+		t.Run(scenario.scenario, func(t *testing.T) {
+			require.NoError(t, fs.RemoveAll("weighted/reconstructed"))
+
+			if scenario.err {
+				require.Error(t, combine(scenario.shares, scenario.args...))
+				return
+			}
+
+			require.NoError(t, combine(scenario.shares, scenario.args...))
+
+			reconstructed, err := afero.ReadFile(fs, "weighted/reconstructed")
+			require.NoError(t, err)
+			require.Equal(t, secret, reconstructed)
+		})
+	}
+
+	verifyCmd, err := cmd.NewVerifyCommand(fs)
+	require.NoError(t, err)
+
+	verifyCmd.SetArgs([]string{
+		"shares", "-g", "group.json", "-t", "3", "--roster", "roster.yaml",
+		"--shares", "weighted/shareholder-*.yaml", "--commitments", "weighted/commitments.yaml",
+	})
+	require.NoError(t, verifyCmd.Execute())
+
+	verifyCmd, err = cmd.NewVerifyCommand(fs)
+	require.NoError(t, err)
+
+	verifyCmd.SetArgs([]string{
+		"part", "-g", "group.json", "-t", "3", "--roster", "roster.yaml",
+		"--share", "weighted/shareholder-0.yaml", "--commitments", "weighted/commitments.yaml",
+	})
+	require.NoError(t, verifyCmd.Execute())
+
+	// a roster which does not match the split
+	writeTestRoster(t, fs, "reordered.yaml",
+		schema.Holder{Name: "alice", Weight: 1},
+		schema.Holder{Name: "cfo", Weight: 2},
+		schema.Holder{Name: "bob", Weight: 1},
+		schema.Holder{Name: "carol", Weight: 1},
+	)
+	require.ErrorIs(t,
+		combine("weighted/shareholder-0.yaml,weighted/shareholder-2.yaml", "--roster", "reordered.yaml"),
+		cmd.ErrHolderMismatch,
+	)
+
+	// share files of weighted shareholders without the roster
+	require.ErrorIs(t,
+		combine("weighted/shareholder-0.yaml,weighted/shareholder-3.yaml"),
+		cmd.ErrMissingRoster,
+	)
+
+	verifyCmd, err = cmd.NewVerifyCommand(fs)
+	require.NoError(t, err)
+
+	verifyCmd.SetArgs([]string{
+		"part", "-g", "group.json", "-t", "3",
+		"--share", "weighted/shareholder-0.yaml", "--commitments", "weighted/commitments.yaml",
+	})
+	require.ErrorIs(t, verifyCmd.Execute(), cmd.ErrMissingRoster)
+
+	// share files of unit shareholders with the roster
+	splitTestSecret(t, fs, secret, "plain")
+	require.ErrorIs(t,
+		combine("plain/shareholder-0.yaml,plain/shareholder-1.yaml,plain/shareholder-2.yaml", "--roster", "roster.yaml"),
+		cmd.ErrHolderMismatch,
+	)
+
+	// a weighted shareholder claiming the same shareholder index twice
+	share = schema.Shares{}
+	require.NoError(t, yaml.New(fs).ReadFile("weighted/shareholder-0.yaml", &share))
+	share.Bundle[1] = share.Bundle[0]
+	require.NoError(t, yaml.New(fs).WriteFile("weighted/duplicate.yaml", share, 0o600))
+	require.ErrorIs(t,
+		combine("weighted/duplicate.yaml,weighted/shareholder-2.yaml", "--roster", "roster.yaml"),
+		pedersen.ErrBundleMismatch,
+	)
+
+	// bundled shares are exported to mnemonic words and printed as a whole
+	exportTestShare(t, fs, "weighted/shareholder-0.yaml", "weighted/words.txt")
+	require.NoError(t, importTestShare(fs, "weighted/words.txt", "weighted/imported/shareholder-0.yaml"))
+
+	shareCmd, err := cmd.NewShareCommand(fs)
+	require.NoError(t, err)
+
+	shareCmd.SetArgs([]string{
+		"print", "-i", "weighted/shareholder-2.yaml", "-o", "weighted/shareholder-2.html", "--perm", "600",
+	})
+	require.NoError(t, shareCmd.Execute())
+
+	page, err := afero.ReadFile(fs, "weighted/shareholder-2.html")
+	require.NoError(t, err)
+	require.Contains(t, string(page), "<td>bob</td>")
+
+	match := printedPayload.FindStringSubmatch(string(page))
+	require.Len(t, match, 2)
+	require.NoError(t, afero.WriteFile(fs, "weighted/payload-2.txt", []byte(match[1]), 0o600))
+	require.NoError(t, importTestShare(fs, "weighted/payload-2.txt", "weighted/imported/shareholder-2.yaml"))
+
+	imported := schema.Shares{}
+	require.NoError(t, yaml.New(fs).ReadFile("weighted/imported/shareholder-0.yaml", &imported))
+	require.Equal(t, "cfo", imported.Holder)
+	require.Len(t, imported.Bundle, 2)
+
+	require.NoError(t, combine("weighted/imported/shareholder-0.yaml,weighted/imported/shareholder-2.yaml",
+		"--roster", "roster.yaml"))
+
+	reconstructed, err := afero.ReadFile(fs, "weighted/reconstructed")
+	require.NoError(t, err)
+	require.Equal(t, secret, reconstructed)
+}
+
+func TestInvalidRosterCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+	require.NoError(t, afero.WriteFile(fs, "secret", []byte("secret"), 0o600))
+
+	for _, scenario := range []struct {
+		scenario string
+		holders  []schema.Holder
+		err      error
+	}{
+		{
+			scenario: "empty roster",
+			err:      pedersen.ErrEmptyRoster,
+		},
+		{
+			scenario: "zero weight",
+			holders:  []schema.Holder{{Name: "cfo", Weight: 2}, {Name: "alice", Weight: 0}},
+			err:      pedersen.ErrInvalidWeight,
+		},
+		{
+			scenario: "missing name",
+			holders:  []schema.Holder{{Name: "cfo", Weight: 2}, {Weight: 1}},
+			err:      cmd.ErrMissingHolder,
+		},
+		{
+			scenario: "duplicate shareholder",
+			holders:  []schema.Holder{{Name: "cfo", Weight: 2}, {Name: "cfo", Weight: 1}},
+			err:      cmd.ErrDuplicateHolder,
+		},
+	} {
+		t.Run(scenario.scenario, func(t *testing.T) {
+			writeTestRoster(t, fs, "roster.yaml", scenario.holders...)
+
+			splitCmd, err := cmd.NewSplitCommand(fs)
+			require.NoError(t, err)
+
+			splitCmd.SetArgs([]string{
+				"-g", "group.json", "-i", "secret", "-t", "2", "--roster", "roster.yaml",
+				"--shares", "shares/shareholder-*.yaml", "--commitments", "shares/commitments.yaml",
+			})
+			require.ErrorIs(t, splitCmd.Execute(), scenario.err)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	iofs "io/fs"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/matteoarella/pedersen/internal/seal"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ErrMissingRecipient   = errors.New("recipients file does not contain the public key of every shareholder")
+	ErrDuplicateRecipient = errors.New("recipients file contains the same shareholder more than once")
+	ErrMissingIdentity    = errors.New("share file is sealed for a recipient without identity file")
+	ErrSealedShare        = errors.New("sealed share does not match the share file")
+)
+
+type recipientsFlags struct {
+	recipientsFile string
+}
+
+func (r *recipientsFlags) register(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&r.recipientsFile, "recipients", "", "", `recipients file. When set, every share file is sealed
+for the public key of its shareholder`)
+}
+
+// readRecipients reads the public keys of the recipients, arranged by shareholder index.
+// It returns nil if no recipients file is provided.
+func (r *recipientsFlags) readRecipients(fs afero.Fs, parts int) ([][]byte, error) {
+	if r.recipientsFile == "" {
+		return nil, nil
+	}
+
+	recipients := schema.Recipients{}
+	if err := readFileAutofmt(fs, r.recipientsFile, &recipients); err != nil {
+		return nil, err
+	}
+
+	keys := make([][]byte, parts)
+
+	for _, recipient := range recipients.Recipients {
+		if recipient.Index == nil {
+			return nil, ErrMissingShareIndex
+		}
+
+		index := *recipient.Index
+		if index < 0 || index >= parts {
+			return nil, pedersen.ErrInvalidShareholder
+		}
+
+		if keys[index] != nil {
+			return nil, ErrDuplicateRecipient
+		}
+
+		if len(recipient.PublicKey) != seal.KeySize {
+			return nil, seal.ErrInvalidKey
+		}
+
+		keys[index] = recipient.PublicKey
+	}
+
+	for _, key := range keys {
+		if key == nil {
+			return nil, ErrMissingRecipient
+		}
+	}
+
+	return keys, nil
+}
+
+// sealShare returns the share file which carries the share sealed for the recipient.
+// Only the shareholder index is left in plaintext.
+func sealShare(share schema.Shares, recipient []byte) (schema.Shares, error) {
+	if share.Index == nil {
+		return schema.Shares{}, ErrMissingShareIndex
+	}
+
+	plaintext, err := json.Marshal(share)
+	if err != nil {
+		return schema.Shares{}, err
+	}
+
+	ephemeral, data, err := seal.Seal(recipient, plaintext, sealAdditionalData(*share.Index))
+	if err != nil {
+		return schema.Shares{}, err
+	}
+
+	return schema.Shares{
+		Index: share.Index,
+		Sealed: &schema.Sealed{
+			Recipient: recipient,
+			Ephemeral: ephemeral,
+			Data:      data,
+		},
+	}, nil
+}
+
+// sealAdditionalData binds the sealed share to the shareholder index, so that
+// it cannot be relabelled as the share of another shareholder.
+func sealAdditionalData(index int) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(index))
+
+	return buf[:]
+}
+
+type identityFlags struct {
+	identityFiles []string
+	identities    []schema.Identity
+}
+
+func (i *identityFlags) register(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSliceVarP(&i.identityFiles, "identity", "", nil, `identity files, used for opening the share files
+sealed for their recipients`)
+}
+
+// unseal replaces the sealed share file with the share it carries, by using the
+// identity of its recipient. Share files which are not sealed are left untouched.
+func (i *identityFlags) unseal(fs afero.Fs, share *schema.Shares) error {
+	if share.Sealed == nil {
+		return nil
+	}
+
+	if share.Index == nil {
+		return ErrMissingShareIndex
+	}
+
+	if err := i.readIdentities(fs); err != nil {
+		return err
+	}
+
+	for _, identity := range i.identities {
+		if !bytes.Equal(identity.PublicKey, share.Sealed.Recipient) {
+			continue
+		}
+
+		plaintext, err := seal.Open(identity.PrivateKey,
+			share.Sealed.Ephemeral,
+			share.Sealed.Data,
+			sealAdditionalData(*share.Index),
+		)
+		if err != nil {
+			return err
+		}
+
+		opened := schema.Shares{}
+		if err := json.Unmarshal(plaintext, &opened); err != nil {
+			return err
+		}
+
+		if opened.Index == nil || *opened.Index != *share.Index || opened.Sealed != nil {
+			return ErrSealedShare
+		}
+
+		*share = opened
+
+		return nil
+	}
+
+	return ErrMissingIdentity
+}
+
+// readIdentities reads the identity files once, and checks that their public
+// keys are related to their private keys.
+func (i *identityFlags) readIdentities(fs afero.Fs) error {
+	if i.identities != nil || len(i.identityFiles) == 0 {
+		return nil
+	}
+
+	identities := make([]schema.Identity, len(i.identityFiles))
+
+	for idx, identityFile := range i.identityFiles {
+		if err := readFileAutofmt(fs, identityFile, &identities[idx]); err != nil {
+			return err
+		}
+
+		if !seal.Matches(identities[idx].PrivateKey, identities[idx].PublicKey) {
+			return seal.ErrInvalidKey
+		}
+	}
+
+	i.identities = identities
+
+	return nil
+}
+
+type IdentityCommand struct {
+	cobra.Command
+
+	fileFmtFlags
+	outFile string
+	fs      afero.Fs
+}
+
+func NewIdentityCommand(fs afero.Fs) (*IdentityCommand, error) {
+	identityCmd := &IdentityCommand{fs: fs}
+
+	identityCmd.Command = cobra.Command{
+		Use:   "identity",
+		Short: "Generate an identity for receiving sealed shares",
+		Long: `Generate an X25519 identity for receiving sealed shares.
+The identity file holds the private key, and must be kept by the shareholder.
+The public key is printed, so that it can be added to the recipients file.`,
+		RunE: func(*cobra.Command, []string) error {
+			return identityCmd.execute()
+		},
+	}
+
+	identityCmd.fileFmtFlags.register(&identityCmd.Command)
+
+	identityCmd.PersistentFlags().StringVarP(&identityCmd.outFile, "out", "o", "", "identity file")
+
+	if err := identityCmd.MarkPersistentFlagRequired("out"); err != nil {
+		return nil, err
+	}
+
+	return identityCmd, nil
+}
+
+func (i *IdentityCommand) execute() error {
+	private, public, err := seal.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	err = writeFileAutofmt(i.fs,
+		i.fileFmt,
+		i.outFile,
+		schema.Identity{
+			PublicKey:  public,
+			PrivateKey: private,
+		},
+		iofs.FileMode(i.filePerm),
+	)
+	if err != nil {
+		return err
+	}
+
+	i.Println(base64.StdEncoding.EncodeToString(public))
+
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/matteoarella/pedersen/internal/io/yaml"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/matteoarella/pedersen/internal/seal"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestRecipients(t *testing.T, fs afero.Fs, parts int) {
+	t.Helper()
+
+	recipients := schema.Recipients{}
+
+	for i := 0; i < parts; i++ {
+		identityCmd, err := cmd.NewIdentityCommand(fs)
+		require.NoError(t, err)
+
+		buf := new(bytes.Buffer)
+		identityCmd.SetOut(buf)
+		identityCmd.SetArgs([]string{"-o", fmt.Sprintf("identities/shareholder-%d.yaml", i), "--perm", "600"})
+		require.NoError(t, identityCmd.Execute())
+
+		public, err := base64.StdEncoding.DecodeString(strings.TrimSpace(buf.String()))
+		require.NoError(t, err)
+
+		index := i
+		recipients.Recipients = append(recipients.Recipients, schema.Recipient{
+			Index:     &index,
+			PublicKey: public,
+		})
+	}
+
+	require.NoError(t, yaml.New(fs).WriteFile("recipients.yaml", recipients, 0o600))
+}
+
+func TestSealedSharesCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+	writeTestRecipients(t, fs, 5)
+
+	secret := []byte("sealed secret")
+	splitTestSecret(t, fs, secret, "sealed", "--recipients", "recipients.yaml")
+
+	share := schema.Shares{}
+	require.NoError(t, yaml.New(fs).ReadFile("sealed/shareholder-2.yaml", &share))
+	require.NotNil(t, share.Sealed)
+	require.Nil(t, share.Abscissa)
+	require.Empty(t, share.Parts)
+
+	identities := "identities/shareholder-4.yaml,identities/shareholder-0.yaml,identities/shareholder-2.yaml"
+	shares := "sealed/shareholder-4.yaml,sealed/shareholder-0.yaml,sealed/shareholder-2.yaml"
+
+	for _, scenario := range []struct {
+		description string
+		args        []string
+		err         error
+	}{
+		{
+			description: "combine sealed shares",
+			args:        []string{"--shares", shares, "--identity", identities},
+		},
+		{
+			description: "combine sealed shares pattern",
+			args: []string{
+				"--shares", "sealed/shareholder-*.yaml",
+				"--identity", "identities/shareholder-1.yaml,identities/shareholder-3.yaml," + identities,
+			},
+		},
+		{
+			description: "combine sealed shares without identities",
+			args:        []string{"--shares", shares},
+			err:         cmd.ErrMissingIdentity,
+		},
+		{
+			description: "combine sealed shares with missing identity",
+			args:        []string{"--shares", shares, "--identity", "identities/shareholder-4.yaml,identities/shareholder-0.yaml"},
+			err:         cmd.ErrMissingIdentity,
+		},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			combineCmd, err := cmd.NewCombineCommand(fs)
+			require.NoError(t, err)
+
+			combineCmd.SetArgs(append([]string{
+				"-g", "group.json", "-o", "sealed/reconstructed", "-p", "5", "-t", "3",
+				"--commitments", "sealed/commitments.yaml", "--perm", "600",
+			}, scenario.args...))
+
+			err = combineCmd.Execute()
+			if scenario.err != nil {
+				require.ErrorIs(t, err, scenario.err)
+				return
+			}
+			require.NoError(t, err)
+
+			reconstructed, err := afero.ReadFile(fs, "sealed/reconstructed")
+			require.NoError(t, err)
+			require.Equal(t, secret, reconstructed)
+		})
+	}
+
+	verifyCmd, err := cmd.NewVerifyCommand(fs)
+	require.NoError(t, err)
+
+	verifyCmd.SetArgs([]string{
+		"part", "-g", "group.json", "-p", "5", "-t", "3",
+		"--share", "sealed/shareholder-2.yaml", "--commitments", "sealed/commitments.yaml",
+		"--identity", "identities/shareholder-2.yaml",
+	})
+	require.NoError(t, verifyCmd.Execute())
+
+	// a sealed share relabelled as the share of another shareholder
+	index := 1
+	share.Index = &index
+	require.NoError(t, yaml.New(fs).WriteFile("sealed/shareholder-1.yaml", share, 0o600))
+
+	verifyCmd, err = cmd.NewVerifyCommand(fs)
+	require.NoError(t, err)
+
+	verifyCmd.SetArgs([]string{
+		"part", "-g", "group.json", "-p", "5", "-t", "3",
+		"--share", "sealed/shareholder-1.yaml", "--commitments", "sealed/commitments.yaml",
+		"--identity", "identities/shareholder-2.yaml",
+	})
+	require.ErrorIs(t, verifyCmd.Execute(), seal.ErrOpen)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+type ShareCommand struct {
+	cobra.Command
+
+	fs afero.Fs
+}
+
+func NewShareCommand(fs afero.Fs) (*ShareCommand, error) {
+	shareCmd := &ShareCommand{fs: fs}
+
+	shareCmd.Command = cobra.Command{
+		Use:   "share",
+		Short: "Manage share files",
+	}
+
+	protectCmd, err := NewShareProtectCommand(shareCmd.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	exportCmd, err := NewShareExportCommand(shareCmd.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	importCmd, err := NewShareImportCommand(shareCmd.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	printCmd, err := NewSharePrintCommand(shareCmd.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	shareCmd.AddCommand(&protectCmd.Command, &exportCmd.Command, &importCmd.Command, &printCmd.Command)
+
+	return shareCmd, nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/schema"
+)
+
+var (
+	ErrMissingFragment = errors.New("share file does not contain a fragment")
+	ErrInvalidFragment = errors.New("fragment does not match its digest")
+)
+
+// shareFragments returns the fragment of every shareholder from the provided short shares.
+func shareFragments(s *pedersen.ShortShares) []*schema.Fragment {
+	digests := make([]schema.Bytes, len(s.Digests))
+	for i, digest := range s.Digests {
+		digests[i] = digest
+	}
+
+	fragments := make([]*schema.Fragment, len(s.Fragments))
+	for i, fragment := range s.Fragments {
+		fragments[i] = &schema.Fragment{
+			Data:    fragment,
+			Digests: digests,
+			Nonce:   s.Nonce,
+			Length:  s.Length,
+		}
+	}
+
+	return fragments
+}
+
+// fragmentDispersal returns the dispersal described by the integrity information
+// of the provided fragment.
+func fragmentDispersal(fragment *schema.Fragment) *pedersen.Dispersal {
+	d := &pedersen.Dispersal{
+		Digests: make([][]byte, len(fragment.Digests)),
+		Nonce:   fragment.Nonce,
+		Length:  fragment.Length,
+	}
+
+	for i, digest := range fragment.Digests {
+		d.Digests[i] = digest
+	}
+
+	return d
+}
+
+// boundFragment returns the fragment whose digests, nonce and length are bound to
+// the split of the transcript, so that the integrity information of a share file
+// cannot be replaced together with its fragment.
+// Legacy transcripts carry no split identifier, in which case the integrity
+// information shared by the majority of the fragments is taken instead.
+func boundFragment(fragments []*schema.Fragment, t *pedersen.Transcript) *schema.Fragment {
+	if t.SplitID == nil {
+		return majorityFragment(fragments)
+	}
+
+	for _, fragment := range fragments {
+		if fragment != nil && bytes.Equal(fragmentDispersal(fragment).SplitID(), t.SplitID) {
+			return fragment
+		}
+	}
+
+	return nil
+}
+
+// majorityFragment returns the fragment whose digests, nonce and length are shared
+// by the majority of the provided fragments.
+func majorityFragment(fragments []*schema.Fragment) *schema.Fragment {
+	var (
+		best      *schema.Fragment
+		bestVotes int
+	)
+
+	for _, candidate := range fragments {
+		if candidate == nil {
+			continue
+		}
+
+		splitID := fragmentDispersal(candidate).SplitID()
+
+		votes := 0
+		for _, other := range fragments {
+			if other != nil && bytes.Equal(fragmentDispersal(other).SplitID(), splitID) {
+				votes++
+			}
+		}
+
+		if votes > bestVotes {
+			best, bestVotes = candidate, votes
+		}
+	}
+
+	return best
+}
+
+// shareDispersal builds the dispersed ciphertext from the fragments read from the
+// shareholders files. fragments is indexed by shareholder index and missing
+// fragments must be nil.
+func shareDispersal(fragments []*schema.Fragment, t *pedersen.Transcript) (*pedersen.Dispersal, error) {
+	info := boundFragment(fragments, t)
+	if info == nil {
+		return nil, ErrMissingFragment
+	}
+
+	d := fragmentDispersal(info)
+	d.Fragments = make([][]byte, len(fragments))
+
+	for i, fragment := range fragments {
+		if fragment != nil {
+			d.Fragments[i] = fragment.Data
+		}
+	}
+
+	return d, nil
+}
+
+// verifyFragments checks the fragments of the provided shares against their
+// SHA-256 digests, which must be bound to the split of the transcript, so that
+// corrupted fragments are detected before combining the shares.
+// Shares without a fragment are skipped.
+func verifyFragments(shares []pedersen.Share, fragments []*schema.Fragment, t *pedersen.Transcript) error {
+	info := boundFragment(fragments, t)
+
+	for i, fragment := range fragments {
+		if fragment == nil {
+			continue
+		}
+
+		if info == nil {
+			return ErrInvalidFragment
+		}
+
+		index := shares[i].Index
+		if index < 0 || index >= len(info.Digests) {
+			return ErrInvalidFragment
+		}
+
+		if subtle.ConstantTimeCompare(pedersen.FragmentDigest(fragment.Data), info.Digests[index]) != 1 {
+			return ErrInvalidFragment
+		}
+	}
+
+	return nil
+}
@@ -26,8 +26,14 @@ type SplitCommand struct {
 	fileFmtFlags
 	pedersenFlags
 	secretSharesFlags
-	inFile string
-	fs     afero.Fs
+	sharingModeFlags
+	recipientsFlags
+	rosterFlags
+	inFile      string
+	merkle      bool
+	protect     bool
+	passphrases passphrasePrompt
+	fs          afero.Fs
 }
 
 func NewSplitCommand(fs afero.Fs) (*SplitCommand, error) {
@@ -57,8 +63,18 @@ func NewSplitCommand(fs afero.Fs) (*SplitCommand, error) {
 	}
 
 	splitCmd.fileFmtFlags.register(&splitCmd.Command)
+	splitCmd.sharingModeFlags.register(&splitCmd.Command)
+	splitCmd.recipientsFlags.register(&splitCmd.Command)
+	splitCmd.rosterFlags.register(&splitCmd.Command)
 
 	splitCmd.PersistentFlags().StringVarP(&splitCmd.inFile, "in", "i", "", "input file")
+	splitCmd.PersistentFlags().BoolVarP(&splitCmd.merkle, "merkle", "", false, `write only the Merkle root of the commitments into the
+commitments file, while every share file carries the
+commitments of its chunks`)
+	splitCmd.PersistentFlags().BoolVarP(&splitCmd.protect, "protect", "", false, `protect every share file with a passphrase, which is
+prompted for each shareholder`)
+
+	splitCmd.passphrases.cmd = &splitCmd.Command
 
 	err = splitCmd.MarkPersistentFlagRequired("in")
 	if err != nil {
@@ -74,34 +90,108 @@ func (s *SplitCommand) execute() error {
 		return err
 	}
 
+	if err := s.readRoster(s.fs, &s.parts); err != nil {
+		return err
+	}
+
+	// weighted shareholders have a single share file bundling all their shares
+	holders := s.parts
+	if s.roster != nil {
+		holders = len(s.roster.Holders)
+	}
+
+	recipients, err := s.readRecipients(s.fs, holders)
+	if err != nil {
+		return err
+	}
+
 	// read secret file
 	inFile, err := afero.ReadFile(s.fs, s.inFile)
 	if err != nil {
 		return err
 	}
 
-	p, err := pedersen.NewPedersen(s.parts,
-		s.threshold,
+	options := []pedersen.Option{
 		pedersen.CyclicGroup(&pedersen.Group{
 			P: group.P,
 			Q: group.Q,
 			G: group.G,
 			H: group.H,
 		}),
-	)
-	if err != nil {
-		return err
 	}
 
-	shares, err := p.Split(inFile, nil)
+	if s.merkle {
+		options = append(options, pedersen.MerkleCommitments())
+	}
+
+	p, err := pedersen.NewPedersen(s.parts, s.threshold, options...)
 	if err != nil {
 		return err
 	}
+	defer p.Close()
+
+	var (
+		shares    *pedersen.Shares
+		fragments []*schema.Fragment
+	)
+
+	switch s.mode {
+	case ShortMode:
+		short, err := p.SplitShort(inFile, nil)
+		if err != nil {
+			return err
+		}
+
+		shares = short.KeyShares
+		fragments = shareFragments(short)
+	default:
+		shares, err = p.Split(inFile, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	proofs := shareProofs(shares)
+	shareFiles := make([]schema.Shares, s.parts)
 
 	for i := 0; i < s.parts; i++ {
-		parts := schema.Shares{
+		index := i
+		shareFiles[i] = schema.Shares{
+			Index:    &index,
 			Abscissa: shares.Abscissae[i],
 			Parts:    shares.Parts[i],
+			SplitID:  shares.SplitID,
+			Proofs:   proofs,
+		}
+
+		if fragments != nil {
+			shareFiles[i].Fragment = fragments[i]
+		}
+	}
+
+	shareFiles, err = s.bundle(shares, shareFiles)
+	if err != nil {
+		return err
+	}
+
+	for i, parts := range shareFiles {
+		if s.protect {
+			passphrase, err := s.passphrases.readNew(i)
+			if err != nil {
+				return err
+			}
+
+			parts, err = protectShare(parts, passphrase)
+			if err != nil {
+				return err
+			}
+		}
+
+		if recipients != nil {
+			parts, err = sealShare(parts, recipients[i])
+			if err != nil {
+				return err
+			}
 		}
 
 		if err := writeFileAutofmt(s.fs, s.fileFmt, s.share(i), parts, iofs.FileMode(s.filePerm)); err != nil {
@@ -109,9 +199,7 @@ func (s *SplitCommand) execute() error {
 		}
 	}
 
-	commitments := schema.Commitments{
-		Commitments: shares.Commitments,
-	}
+	commitments := commitmentsFile(shares)
 
 	return writeFileAutofmt(s.fs,
 		s.fileFmt,
@@ -0,0 +1,256 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/matteoarella/pedersen/internal/io/json"
+	"github.com/matteoarella/pedersen/internal/io/yaml"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestGroup(t *testing.T, fs afero.Fs) {
+	t.Helper()
+
+	generateCmd, err := cmd.NewGenerateCommand(fs)
+	require.NoError(t, err)
+
+	generateCmd.SetArgs([]string{"-o", "group.json", "-b", "256"})
+	require.NoError(t, generateCmd.Execute())
+}
+
+func TestSplitCombineCmd(t *testing.T) {
+	secret := make([]byte, 1024)
+	_, err := rand.Read(secret)
+	require.NoError(t, err)
+
+	for _, scenario := range []struct {
+		scenario string
+		mode     string
+		shares   []string
+		remove   []string
+	}{
+		{
+			scenario: "pedersen mode",
+			mode:     "pedersen",
+		},
+		{
+			scenario: "short mode",
+			mode:     "short",
+		},
+		{
+			scenario: "pedersen mode with missing shares",
+			mode:     "pedersen",
+			remove:   []string{"shares/shareholder-0.yaml", "shares/shareholder-3.yaml"},
+		},
+		{
+			scenario: "short mode with missing shares",
+			mode:     "short",
+			remove:   []string{"shares/shareholder-1.yaml", "shares/shareholder-3.yaml"},
+		},
+		{
+			scenario: "short mode with share files list",
+			mode:     "short",
+			shares:   []string{"shares/shareholder-4.yaml", "shares/shareholder-0.yaml", "shares/shareholder-2.yaml"},
+		},
+	} {
+		t.Run(scenario.scenario, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			writeTestGroup(t, fs)
+
+			require.NoError(t, afero.WriteFile(fs, "secret", secret, 0o600))
+
+			splitCmd, err := cmd.NewSplitCommand(fs)
+			require.NoError(t, err)
+
+			buf := new(bytes.Buffer)
+			splitCmd.SetOut(buf)
+			splitCmd.SetErr(buf)
+			splitCmd.SetArgs([]string{
+				"-g", "group.json", "-i", "secret", "-p", "5", "-t", "3",
+				"--shares", "shares/shareholder-*.yaml", "--commitments", "commitments.yaml",
+				"--mode", scenario.mode, "--perm", "600",
+			})
+			require.NoError(t, splitCmd.Execute())
+
+			for _, name := range scenario.remove {
+				require.NoError(t, fs.Remove(name))
+			}
+
+			shares := "shares/shareholder-*.yaml"
+			if scenario.shares != nil {
+				shares = strings.Join(scenario.shares, ",")
+			}
+
+			combineCmd, err := cmd.NewCombineCommand(fs)
+			require.NoError(t, err)
+
+			combineCmd.SetOut(buf)
+			combineCmd.SetErr(buf)
+			combineCmd.SetArgs([]string{
+				"-g", "group.json", "-o", "reconstructed", "-p", "5", "-t", "3",
+				"--shares", shares, "--commitments", "commitments.yaml",
+				"--mode", scenario.mode, "--perm", "600",
+			})
+			require.NoError(t, combineCmd.Execute())
+
+			reconstructed, err := afero.ReadFile(fs, "reconstructed")
+			require.NoError(t, err)
+			require.Equal(t, secret, reconstructed)
+		})
+	}
+}
+
+func TestCombineCmdMismatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	require.NoError(t, afero.WriteFile(fs, "secret", []byte("secret"), 0o600))
+
+	for _, prefix := range []string{"first", "second"} {
+		splitCmd, err := cmd.NewSplitCommand(fs)
+		require.NoError(t, err)
+
+		splitCmd.SetArgs([]string{
+			"-g", "group.json", "-i", "secret", "-p", "5", "-t", "3",
+			"--shares", prefix + "/shareholder-*.yaml", "--commitments", prefix + "/commitments.yaml",
+			"--perm", "600",
+		})
+		require.NoError(t, splitCmd.Execute())
+	}
+
+	combineCmd, err := cmd.NewCombineCommand(fs)
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	combineCmd.SetOut(buf)
+	combineCmd.SetErr(buf)
+	combineCmd.SetArgs([]string{
+		"-g", "group.json", "-o", "reconstructed", "-p", "5", "-t", "3",
+		"--shares", "first/shareholder-*.yaml", "--commitments", "second/commitments.yaml",
+		"--verify=false",
+	})
+	require.ErrorIs(t, combineCmd.Execute(), pedersen.ErrSplitMismatch)
+
+	exists, err := afero.Exists(fs, "reconstructed")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestCombineCmdLegacyFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	group := pedersen.Group{}
+	require.NoError(t, json.New(fs).ReadFile("group.json", &group))
+
+	p, err := pedersen.NewPedersen(5, 3,
+		pedersen.CyclicGroup(&group),
+		pedersen.Encoding(pedersen.LegacyEncoding),
+	)
+	require.NoError(t, err)
+	defer p.Close()
+
+	secret := make([]byte, 100)
+	_, err = rand.Read(secret)
+	require.NoError(t, err)
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+
+	// files written by previous versions carry neither the split identifier,
+	// nor the bindings, nor the chunk encoding
+	out := yaml.New(fs)
+
+	for i := range shares.Parts {
+		index := i
+		require.NoError(t, out.WriteFile("legacy/shareholder-"+strconv.Itoa(i)+".yaml", schema.Shares{
+			Index:    &index,
+			Abscissa: shares.Abscissae[i],
+			Parts:    shares.Parts[i],
+		}, 0o600))
+	}
+
+	require.NoError(t, out.WriteFile("legacy/commitments.yaml", schema.Commitments{
+		Commitments: shares.Commitments,
+	}, 0o600))
+
+	combine := func(args ...string) error {
+		combineCmd, err := cmd.NewCombineCommand(fs)
+		require.NoError(t, err)
+
+		buf := new(bytes.Buffer)
+		combineCmd.SetOut(buf)
+		combineCmd.SetErr(buf)
+		combineCmd.SetArgs(append([]string{
+			"-g", "group.json", "-o", "reconstructed", "-p", "5", "-t", "3",
+			"--shares", "legacy/shareholder-*.yaml", "--commitments", "legacy/commitments.yaml",
+		}, args...))
+
+		return combineCmd.Execute()
+	}
+
+	// files without bindings are accepted only on request
+	require.ErrorIs(t, combine(), pedersen.ErrUnboundTranscript)
+	require.NoError(t, combine("--legacy"))
+
+	reconstructed, err := afero.ReadFile(fs, "reconstructed")
+	require.NoError(t, err)
+	require.Equal(t, secret, reconstructed)
+}
+
+func TestCombineCmdStrippedBindings(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	splitTestSecret(t, fs, []byte("secret whose bindings are stripped"), "stripped")
+
+	// the split identifier and the bindings are removed from every file
+	out := yaml.New(fs)
+
+	commitments := schema.Commitments{}
+	require.NoError(t, out.ReadFile("stripped/commitments.yaml", &commitments))
+	commitments.SplitID = nil
+	commitments.Bindings = nil
+	require.NoError(t, out.WriteFile("stripped/commitments.yaml", commitments, 0o600))
+
+	for i := 0; i < 5; i++ {
+		name := "stripped/shareholder-" + strconv.Itoa(i) + ".yaml"
+
+		share := schema.Shares{}
+		require.NoError(t, out.ReadFile(name, &share))
+		share.SplitID = nil
+		require.NoError(t, out.WriteFile(name, share, 0o600))
+	}
+
+	combineCmd, err := cmd.NewCombineCommand(fs)
+	require.NoError(t, err)
+
+	combineCmd.SetArgs([]string{
+		"-g", "group.json", "-o", "stripped/reconstructed", "-p", "5", "-t", "3",
+		"--shares", "stripped/shareholder-*.yaml", "--commitments", "stripped/commitments.yaml",
+	})
+	require.ErrorIs(t, combineCmd.Execute(), pedersen.ErrUnboundTranscript)
+
+	verifyCmd, err := cmd.NewVerifyCommand(fs)
+	require.NoError(t, err)
+
+	verifyCmd.SetArgs([]string{
+		"part", "-g", "group.json", "-p", "5", "-t", "3",
+		"--share", "stripped/shareholder-0.yaml", "--commitments", "stripped/commitments.yaml",
+	})
+	require.ErrorIs(t, verifyCmd.Execute(), pedersen.ErrUnboundTranscript)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/frost"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ErrMissingKeyIndex = errors.New("key share file does not contain the shareholder index")
+)
+
+type thresholdKeyFlags struct {
+	groupFile  string
+	publicFile string
+
+	fs afero.Fs
+}
+
+func (k *thresholdKeyFlags) register(cmd *cobra.Command) error {
+	cmd.PersistentFlags().StringVarP(&k.groupFile, "group", "g", "", "group file")
+	cmd.PersistentFlags().StringVarP(&k.publicFile, "public", "", "", "threshold public key file")
+
+	err := cmd.MarkPersistentFlagRequired("group")
+	if err != nil {
+		return err
+	}
+
+	return cmd.MarkPersistentFlagRequired("public")
+}
+
+// readPublic reads the threshold public key package, and validates it.
+func (k *thresholdKeyFlags) readPublic() (*frost.PublicKeyPackage, error) {
+	group := pedersen.Group{}
+	if err := readFileAutofmt(k.fs, k.groupFile, &group); err != nil {
+		return nil, err
+	}
+
+	public := schema.PublicKey{}
+	if err := readFileAutofmt(k.fs, k.publicFile, &public); err != nil {
+		return nil, err
+	}
+
+	pk := &frost.PublicKeyPackage{
+		Group:           &group,
+		Threshold:       public.Threshold,
+		PublicKey:       public.PublicKey,
+		Identifiers:     public.Identifiers,
+		VerifyingShares: public.VerifyingShares,
+	}
+
+	if err := pk.Validate(); err != nil {
+		return nil, err
+	}
+
+	return pk, nil
+}
+
+// readKeyShare reads the key share of a shareholder, and checks it against
+// the threshold public key package.
+func (k *thresholdKeyFlags) readKeyShare(name string, public *frost.PublicKeyPackage) (*frost.KeyShare, error) {
+	key := schema.KeyShare{}
+	if err := readFileAutofmt(k.fs, name, &key); err != nil {
+		return nil, err
+	}
+
+	if key.Index == nil {
+		return nil, ErrMissingKeyIndex
+	}
+
+	return frost.NewKeyShare(pedersen.ScalarShare{
+		Index:    *key.Index,
+		Abscissa: key.Identifier,
+		Part: pedersen.SecretPart{
+			SShare: key.SigningShare,
+		},
+	}, public)
+}
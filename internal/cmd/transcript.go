@@ -0,0 +1,150 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd
+
+import (
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// legacyFlags holds the flag accepting the commitments files written by previous
+// versions, which are rejected by default since their commitments are not bound
+// to the split.
+type legacyFlags struct {
+	legacy bool
+}
+
+func (l *legacyFlags) register(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVarP(&l.legacy, "legacy", "", false, `accept commitments files written by previous versions,
+whose commitments are not bound to the split`)
+}
+
+// pedersenOptions returns the provided options, together with the option accepting
+// the transcripts of previous versions if the legacy flag is set.
+func (l *legacyFlags) pedersenOptions(options ...pedersen.Option) []pedersen.Option {
+	if l.legacy {
+		options = append(options, pedersen.LegacyTranscripts())
+	}
+
+	return options
+}
+
+// commitmentsFile returns the commitments file content of the provided shares.
+// When the shares carry the Merkle root of the commitments, only the split
+// identifier and the root are written, since every share file carries the
+// commitments of its own chunks.
+func commitmentsFile(s *pedersen.Shares) schema.Commitments {
+	if s.Root != nil {
+		return schema.Commitments{
+			SplitID:  s.SplitID,
+			Root:     s.Root,
+			Encoding: s.Encoding,
+		}
+	}
+
+	return schema.Commitments{
+		Commitments: s.Commitments,
+		SplitID:     s.SplitID,
+		Bindings:    toSchemaBytes(s.Bindings),
+		Encoding:    s.Encoding,
+	}
+}
+
+// shareProofs returns the commitment proofs of the shares, as written in every share file.
+func shareProofs(s *pedersen.Shares) []schema.Proof {
+	if s.Root == nil {
+		return nil
+	}
+
+	proofs := make([]schema.Proof, len(s.Commitments))
+	for chunkIdx := range proofs {
+		proofs[chunkIdx] = schema.Proof{
+			Commitments: s.Commitments[chunkIdx],
+			Path:        toSchemaBytes(s.Paths[chunkIdx]),
+		}
+	}
+
+	return proofs
+}
+
+// transcript returns the transcript stored in the provided commitments file.
+// Commitments files written by previous versions yield a legacy transcript,
+// whose chunks have the legacy encoding.
+func transcript(c *schema.Commitments) *pedersen.Transcript {
+	t := &pedersen.Transcript{
+		Commitments: c.Commitments,
+		SplitID:     optionalBytes(c.SplitID),
+		Bindings:    fromSchemaBytes(c.Bindings),
+		Root:        optionalBytes(c.Root),
+		Encoding:    c.Encoding,
+	}
+
+	if t.SplitID == nil && t.Bindings == nil && t.Root == nil {
+		t.Encoding = pedersen.LegacyEncoding
+	}
+
+	return t
+}
+
+// toShare returns the share stored in the provided share file.
+func toShare(index int, s *schema.Shares) pedersen.Share {
+	var proofs []pedersen.CommitmentProof
+	if len(s.Proofs) > 0 {
+		proofs = make([]pedersen.CommitmentProof, len(s.Proofs))
+		for chunkIdx, proof := range s.Proofs {
+			proofs[chunkIdx] = pedersen.CommitmentProof{
+				Commitments: proof.Commitments,
+				Path:        fromSchemaBytes(proof.Path),
+			}
+		}
+	}
+
+	return pedersen.Share{
+		Index:    index,
+		Abscissa: s.Abscissa,
+		Parts:    s.Parts,
+		SplitID:  optionalBytes(s.SplitID),
+		Proofs:   proofs,
+	}
+}
+
+// optionalBytes returns the bytes read from a file, which are nil when
+// the file does not carry them.
+func optionalBytes(b schema.Bytes) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return b
+}
+
+func toSchemaBytes(v [][]byte) []schema.Bytes {
+	if v == nil {
+		return nil
+	}
+
+	res := make([]schema.Bytes, len(v))
+	for i, b := range v {
+		res[i] = b
+	}
+
+	return res
+}
+
+func fromSchemaBytes(v []schema.Bytes) [][]byte {
+	if len(v) == 0 {
+		return nil
+	}
+
+	res := make([][]byte, len(v))
+	for i, b := range v {
+		res[i] = b
+	}
+
+	return res
+}
@@ -7,22 +7,18 @@
 package cmd
 
 import (
-	"errors"
-	iofs "io/fs"
-
 	"github.com/matteoarella/pedersen"
-	"github.com/matteoarella/pedersen/big"
 	"github.com/matteoarella/pedersen/internal/schema"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 )
 
 type VerifySharesCommand struct {
 	cobra.Command
 
 	pedersenFlags
-	secretSharesFlags
+	shareFilesFlags
+	legacyFlags
 	fs afero.Fs
 }
 
@@ -38,44 +34,35 @@ func (v *VerifySharesCommand) execute() error {
 		return err
 	}
 
-	shares := &pedersen.Shares{
-		Abscissae:   make([]*big.Int, v.parts),
-		Commitments: commitments.Commitments,
-		Parts:       make([][]pedersen.SecretPart, v.parts),
+	if err := v.readRoster(v.fs, &v.parts); err != nil {
+		return err
 	}
 
-	for i := 0; i < v.parts; i++ {
-		parts := schema.Shares{}
-
-		shareFile := v.share(i)
-
-		if err := readFileAutofmt(v.fs, shareFile, &parts); err != nil {
-			if errors.Is(err, iofs.ErrNotExist) {
-				continue
-			}
-
-			return err
-		}
+	shares, fragments, err := v.readShares(v.parts)
+	if err != nil {
+		return err
+	}
 
-		shares.Abscissae[i] = parts.Abscissa
-		shares.Parts[i] = parts.Parts
+	if err := verifyFragments(shares, fragments, transcript(&commitments)); err != nil {
+		return err
 	}
 
 	p, err := pedersen.NewPedersen(v.parts,
 		v.threshold,
-		pedersen.CyclicGroup(&group),
+		v.pedersenOptions(pedersen.CyclicGroup(&group))...,
 	)
 	if err != nil {
 		return err
 	}
+	defer p.Close()
 
-	return p.VerifyShares(shares)
+	return p.VerifyShareholders(shares, transcript(&commitments))
 }
 
 func NewVerifySharesCommand(fs afero.Fs) (*VerifySharesCommand, error) {
 	verifySharesCmd := &VerifySharesCommand{
 		fs: fs,
-		secretSharesFlags: secretSharesFlags{
+		shareFilesFlags: shareFilesFlags{
 			fs: fs,
 		},
 	}
@@ -93,11 +80,13 @@ func NewVerifySharesCommand(fs afero.Fs) (*VerifySharesCommand, error) {
 		return nil, err
 	}
 
-	err = verifySharesCmd.secretSharesFlags.register(&verifySharesCmd.Command)
+	err = verifySharesCmd.shareFilesFlags.register(&verifySharesCmd.Command)
 	if err != nil {
 		return nil, err
 	}
 
+	verifySharesCmd.legacyFlags.register(&verifySharesCmd.Command)
+
 	return verifySharesCmd, nil
 }
 
@@ -106,6 +95,8 @@ type VerifyPartCommand struct {
 
 	pedersenFlags
 	secretShareFlags
+	rosterFlags
+	legacyFlags
 	Fs afero.Fs
 }
 
@@ -121,29 +112,50 @@ func (v *VerifyPartCommand) execute() error {
 		return err
 	}
 
-	parts := schema.Shares{}
-	if err := readFileAutofmt(v.Fs, v.shareFile, &parts); err != nil {
+	if err := v.readRoster(v.Fs, &v.parts); err != nil {
+		return err
+	}
+
+	parts, err := v.readShare(v.Fs)
+	if err != nil {
 		return err
 	}
 
 	p, err := pedersen.NewPedersen(v.parts,
 		v.threshold,
-		pedersen.CyclicGroup(&group),
+		v.pedersenOptions(pedersen.CyclicGroup(&group))...,
 	)
 	if err != nil {
 		return err
 	}
+	defer p.Close()
+
+	index := 0
+	if parts.Index != nil {
+		index = *parts.Index
+	}
+
+	// the share file of a weighted shareholder is verified share by share
+	shares, fragments, err := v.unbundle(index, &parts)
+	if err != nil {
+		return err
+	}
 
-	g := errgroup.Group{}
+	if err := verifyFragments(shares, fragments, transcript(&commitments)); err != nil {
+		return err
+	}
+
+	for _, share := range shares {
+		if share.Index < 0 || share.Index >= v.parts {
+			return pedersen.ErrInvalidShareholder
+		}
 
-	for i := 0; i < len(parts.Parts); i++ {
-		i := i
-		g.Go(func() error {
-			return p.Verify(parts.Abscissa, parts.Parts[i], commitments.Commitments[i])
-		})
+		if err := p.VerifyShare(share, transcript(&commitments)); err != nil {
+			return err
+		}
 	}
 
-	return g.Wait()
+	return nil
 }
 
 func NewVerifyPartCommand(fs afero.Fs) (*VerifyPartCommand, error) {
@@ -167,6 +179,9 @@ func NewVerifyPartCommand(fs afero.Fs) (*VerifyPartCommand, error) {
 		return nil, err
 	}
 
+	verifyPartCmd.rosterFlags.register(&verifyPartCmd.Command)
+	verifyPartCmd.legacyFlags.register(&verifyPartCmd.Command)
+
 	return verifyPartCmd, nil
 }
 
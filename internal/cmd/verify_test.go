@@ -0,0 +1,235 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package cmd_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/internal/cmd"
+	"github.com/matteoarella/pedersen/internal/io/yaml"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func splitTestSecret(t *testing.T, fs afero.Fs, secret []byte, prefix string, args ...string) {
+	t.Helper()
+
+	require.NoError(t, afero.WriteFile(fs, prefix+"/secret", secret, 0o600))
+
+	splitCmd, err := cmd.NewSplitCommand(fs)
+	require.NoError(t, err)
+
+	splitCmd.SetArgs(append([]string{
+		"-g", "group.json", "-i", prefix + "/secret", "-p", "5", "-t", "3",
+		"--shares", prefix + "/shareholder-*.yaml", "--commitments", prefix + "/commitments.yaml",
+		"--perm", "600",
+	}, args...))
+	require.NoError(t, splitCmd.Execute())
+}
+
+func TestVerifyCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	long := make([]byte, 256)
+	_, err := rand.Read(long)
+	require.NoError(t, err)
+
+	splitTestSecret(t, fs, []byte("secret"), "short")
+	splitTestSecret(t, fs, long, "long")
+	splitTestSecret(t, fs, long, "merkle", "--merkle")
+
+	for _, scenario := range []struct {
+		description string
+		args        []string
+		err         error
+	}{
+		{
+			description: "verify shares",
+			args:        []string{"shares", "--shares", "long/shareholder-*.yaml", "--commitments", "long/commitments.yaml"},
+		},
+		{
+			description: "verify part",
+			args:        []string{"part", "--share", "long/shareholder-2.yaml", "--commitments", "long/commitments.yaml"},
+		},
+		{
+			description: "verify part with commitments of another secret",
+			args:        []string{"part", "--share", "short/shareholder-2.yaml", "--commitments", "long/commitments.yaml"},
+			err:         pedersen.ErrWrongSharesLen,
+		},
+		{
+			description: "verify part with a share of another secret",
+			args:        []string{"part", "--share", "long/shareholder-2.yaml", "--commitments", "short/commitments.yaml"},
+			err:         pedersen.ErrWrongSharesLen,
+		},
+		{
+			description: "verify merkle shares",
+			args:        []string{"shares", "--shares", "merkle/shareholder-*.yaml", "--commitments", "merkle/commitments.yaml"},
+		},
+		{
+			description: "verify merkle part",
+			args:        []string{"part", "--share", "merkle/shareholder-2.yaml", "--commitments", "merkle/commitments.yaml"},
+		},
+		{
+			description: "verify merkle part with the root of another split",
+			args:        []string{"part", "--share", "merkle/shareholder-2.yaml", "--commitments", "long/commitments.yaml"},
+			err:         pedersen.ErrSplitMismatch,
+		},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			verifyCmd, err := cmd.NewVerifyCommand(fs)
+			require.NoError(t, err)
+
+			buf := new(bytes.Buffer)
+			verifyCmd.SetOut(buf)
+			verifyCmd.SetErr(buf)
+			verifyCmd.SetArgs(append(scenario.args, "-g", "group.json", "-p", "5", "-t", "3"))
+
+			err = verifyCmd.Execute()
+			if scenario.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, scenario.err)
+			}
+		})
+	}
+}
+
+func TestCombineCmdIndexMismatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	splitTestSecret(t, fs, []byte("secret"), "shares")
+
+	// a share file renamed to the file name of another shareholder
+	share, err := afero.ReadFile(fs, "shares/shareholder-1.yaml")
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "shares/shareholder-0.yaml", share, 0o600))
+
+	combineCmd, err := cmd.NewCombineCommand(fs)
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	combineCmd.SetOut(buf)
+	combineCmd.SetErr(buf)
+	combineCmd.SetArgs([]string{
+		"-g", "group.json", "-o", "reconstructed", "-p", "5", "-t", "3",
+		"--shares", "shares/shareholder-*.yaml", "--commitments", "shares/commitments.yaml",
+	})
+	require.ErrorIs(t, combineCmd.Execute(), cmd.ErrShareIndexMismatch)
+}
+
+func TestCombineCmdMerkle(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	secret := make([]byte, 256)
+	_, err := rand.Read(secret)
+	require.NoError(t, err)
+
+	splitTestSecret(t, fs, secret, "shares", "--merkle")
+
+	commitments, err := afero.ReadFile(fs, "shares/commitments.yaml")
+	require.NoError(t, err)
+	require.Contains(t, string(commitments), "root:")
+	require.NotContains(t, string(commitments), "commitments:")
+
+	combineCmd, err := cmd.NewCombineCommand(fs)
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	combineCmd.SetOut(buf)
+	combineCmd.SetErr(buf)
+	combineCmd.SetArgs([]string{
+		"-g", "group.json", "-o", "reconstructed", "-p", "5", "-t", "3",
+		"--shares", "shares/shareholder-1.yaml,shares/shareholder-3.yaml,shares/shareholder-4.yaml",
+		"--commitments", "shares/commitments.yaml",
+	})
+	require.NoError(t, combineCmd.Execute())
+
+	reconstructed, err := afero.ReadFile(fs, "reconstructed")
+	require.NoError(t, err)
+	require.Equal(t, secret, reconstructed)
+}
+
+func TestVerifyCmdShortFragments(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTestGroup(t, fs)
+
+	splitTestSecret(t, fs, []byte("secret of the short mode"), "short", "--mode", "short")
+
+	// a share file whose fragment is corrupted
+	share := schema.Shares{}
+	require.NoError(t, yaml.New(fs).ReadFile("short/shareholder-2.yaml", &share))
+	share.Fragment.Data[0] ^= 0xff
+	require.NoError(t, yaml.New(fs).WriteFile("short/shareholder-2.yaml", share, 0o600))
+
+	// a share file whose fragment is replaced together with its digest
+	share = schema.Shares{}
+	require.NoError(t, yaml.New(fs).ReadFile("short/shareholder-3.yaml", &share))
+	share.Fragment.Data[0] ^= 0xff
+	share.Fragment.Digests[3] = pedersen.FragmentDigest(share.Fragment.Data)
+	require.NoError(t, yaml.New(fs).WriteFile("short/tampered.yaml", share, 0o600))
+
+	for _, scenario := range []struct {
+		description string
+		args        []string
+		err         error
+	}{
+		{
+			description: "verify intact part",
+			args:        []string{"part", "--share", "short/shareholder-1.yaml"},
+		},
+		{
+			description: "verify part with a corrupted fragment",
+			args:        []string{"part", "--share", "short/shareholder-2.yaml"},
+			err:         cmd.ErrInvalidFragment,
+		},
+		{
+			description: "verify part with a fragment and a digest replaced",
+			args:        []string{"part", "--share", "short/tampered.yaml"},
+			err:         cmd.ErrInvalidFragment,
+		},
+		{
+			description: "verify shares with a fragment and a digest replaced",
+			args:        []string{"shares", "--shares", "short/shareholder-0.yaml,short/shareholder-1.yaml,short/tampered.yaml"},
+			err:         cmd.ErrInvalidFragment,
+		},
+		{
+			description: "verify intact shares",
+			args:        []string{"shares", "--shares", "short/shareholder-0.yaml,short/shareholder-1.yaml,short/shareholder-3.yaml"},
+		},
+		{
+			description: "verify shares with a corrupted fragment",
+			args:        []string{"shares", "--shares", "short/shareholder-*.yaml"},
+			err:         cmd.ErrInvalidFragment,
+		},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			verifyCmd, err := cmd.NewVerifyCommand(fs)
+			require.NoError(t, err)
+
+			buf := new(bytes.Buffer)
+			verifyCmd.SetOut(buf)
+			verifyCmd.SetErr(buf)
+			verifyCmd.SetArgs(append(scenario.args,
+				"--commitments", "short/commitments.yaml", "-g", "group.json", "-p", "5", "-t", "3",
+			))
+
+			err = verifyCmd.Execute()
+			if scenario.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, scenario.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+// Package ida implements Rabin's Information Dispersal Algorithm over GF(2^8).
+// Data is dispersed into n fragments, any k of which are sufficient to
+// recover it, so that each fragment is about 1/k of the original size.
+package ida
+
+import (
+	"errors"
+)
+
+const (
+	// MaxFragments is the maximum number of fragments that can be generated,
+	// since each fragment is bound to a distinct nonzero element of GF(2^8).
+	MaxFragments = 255
+
+	// reduction polynomial x^8 + x^4 + x^3 + x^2 + 1
+	gfPoly = 0x11d
+)
+
+var (
+	ErrInvalidParameters     = errors.New("fragments count must be at least the threshold and at most 255")
+	ErrInsufficientFragments = errors.New("insufficient fragments")
+	ErrWrongFragmentLen      = errors.New("fragments must have the same length")
+	ErrInvalidLength         = errors.New("invalid data length")
+)
+
+var (
+	gfExp [512]byte
+	gfLog [256]byte
+)
+
+func init() { //nolint:gochecknoinits
+	x := 1
+
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= gfPoly
+		}
+	}
+
+	for i := 255; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])]
+}
+
+// point returns the evaluation point of the fragment with the given index.
+func point(index int) byte {
+	return byte(index + 1)
+}
+
+// FragmentLen returns the length of each fragment obtained by dispersing
+// length bytes with threshold k.
+func FragmentLen(length, k int) int {
+	return (length + k - 1) / k
+}
+
+// Encode disperses data into n fragments, any k of which are sufficient
+// to reconstruct data with [Decode].
+func Encode(data []byte, n, k int) ([][]byte, error) {
+	if k < 1 || n < k || n > MaxFragments {
+		return nil, ErrInvalidParameters
+	}
+
+	rows := FragmentLen(len(data), k)
+	fragments := make([][]byte, n)
+
+	for i := 0; i < n; i++ {
+		x := point(i)
+		fragment := make([]byte, rows)
+
+		for r := 0; r < rows; r++ {
+			// Horner evaluation of the row polynomial at x
+			var acc byte
+
+			for j := k - 1; j >= 0; j-- {
+				var coefficient byte
+				if idx := r*k + j; idx < len(data) {
+					coefficient = data[idx]
+				}
+
+				acc = gfMul(acc, x) ^ coefficient
+			}
+
+			fragment[r] = acc
+		}
+
+		fragments[i] = fragment
+	}
+
+	return fragments, nil
+}
+
+// invertVandermonde returns the inverse of the k×k Vandermonde matrix
+// built on the provided points.
+func invertVandermonde(points []byte) [][]byte {
+	k := len(points)
+	m := make([][]byte, k)
+	inv := make([][]byte, k)
+
+	for i := 0; i < k; i++ {
+		m[i] = make([]byte, k)
+		inv[i] = make([]byte, k)
+		inv[i][i] = 1
+
+		var v byte = 1
+		for j := 0; j < k; j++ {
+			m[i][j] = v
+			v = gfMul(v, points[i])
+		}
+	}
+
+	// Gauss-Jordan elimination; the matrix is always invertible since
+	// points are distinct
+	for col := 0; col < k; col++ {
+		pivot := col
+		for m[pivot][col] == 0 {
+			pivot++
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		scale := gfInv(m[col][col])
+		for j := 0; j < k; j++ {
+			m[col][j] = gfMul(m[col][j], scale)
+			inv[col][j] = gfMul(inv[col][j], scale)
+		}
+
+		for row := 0; row < k; row++ {
+			if row == col || m[row][col] == 0 {
+				continue
+			}
+
+			factor := m[row][col]
+			for j := 0; j < k; j++ {
+				m[row][j] ^= gfMul(factor, m[col][j])
+				inv[row][j] ^= gfMul(factor, inv[col][j])
+			}
+		}
+	}
+
+	return inv
+}
+
+// Decode reconstructs length bytes of data from the provided fragments.
+// fragments is indexed by fragment index, and missing fragments must be nil.
+// At least k fragments must be present.
+func Decode(fragments [][]byte, k, length int) ([]byte, error) {
+	if k < 1 || len(fragments) > MaxFragments {
+		return nil, ErrInvalidParameters
+	}
+
+	if length < 0 {
+		return nil, ErrInvalidLength
+	}
+
+	rows := FragmentLen(length, k)
+	points := make([]byte, 0, k)
+	selected := make([][]byte, 0, k)
+
+	for idx, fragment := range fragments {
+		if fragment == nil {
+			continue
+		}
+
+		if len(fragment) != rows {
+			return nil, ErrWrongFragmentLen
+		}
+
+		points = append(points, point(idx))
+		selected = append(selected, fragment)
+
+		if len(selected) == k {
+			break
+		}
+	}
+
+	if len(selected) < k {
+		return nil, ErrInsufficientFragments
+	}
+
+	inv := invertVandermonde(points)
+	data := make([]byte, rows*k)
+
+	for r := 0; r < rows; r++ {
+		for j := 0; j < k; j++ {
+			var acc byte
+
+			for i := 0; i < k; i++ {
+				acc ^= gfMul(inv[j][i], selected[i][r])
+			}
+
+			data[r*k+j] = acc
+		}
+	}
+
+	return data[:length], nil
+}
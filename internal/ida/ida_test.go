@@ -0,0 +1,71 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package ida_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/matteoarella/pedersen/internal/ida"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDAValid(t *testing.T) {
+	data := make([]byte, 1021)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	for _, scenario := range []struct {
+		description string
+		n, k        int
+		missing     []int
+	}{
+		{description: "all fragments", n: 5, k: 3},
+		{description: "first fragments missing", n: 5, k: 3, missing: []int{0, 1}},
+		{description: "sparse fragments", n: 10, k: 4, missing: []int{1, 3, 5, 7, 9, 0}},
+		{description: "maximum fragments", n: ida.MaxFragments, k: 200, missing: []int{10, 20, 30}},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			fragments, err := ida.Encode(data, scenario.n, scenario.k)
+			require.NoError(t, err)
+			require.Len(t, fragments, scenario.n)
+
+			for _, fragment := range fragments {
+				require.Len(t, fragment, ida.FragmentLen(len(data), scenario.k))
+			}
+
+			for _, idx := range scenario.missing {
+				fragments[idx] = nil
+			}
+
+			decoded, err := ida.Decode(fragments, scenario.k, len(data))
+			require.NoError(t, err)
+			require.Equal(t, data, decoded)
+		})
+	}
+}
+
+func TestIDAInvalid(t *testing.T) {
+	_, err := ida.Encode([]byte("data"), 3, 4)
+	require.ErrorIs(t, err, ida.ErrInvalidParameters)
+
+	_, err = ida.Encode([]byte("data"), ida.MaxFragments+1, 4)
+	require.ErrorIs(t, err, ida.ErrInvalidParameters)
+
+	fragments, err := ida.Encode([]byte("secret data"), 5, 3)
+	require.NoError(t, err)
+
+	fragments[0], fragments[1], fragments[2] = nil, nil, nil
+
+	_, err = ida.Decode(fragments, 3, len("secret data"))
+	require.ErrorIs(t, err, ida.ErrInsufficientFragments)
+
+	fragments[0] = []byte{1}
+
+	_, err = ida.Decode(fragments, 3, len("secret data"))
+	require.ErrorIs(t, err, ida.ErrWrongFragmentLen)
+}
@@ -0,0 +1,327 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+// Package armor implements an ASCII armored file format, which survives being
+// pasted into emails, tickets and chats.
+// The armor carries the JSON encoding of the value in base64, framed by BEGIN and
+// END lines naming the kind of the value, along with informational headers and
+// a SHA-256 based checksum:
+//
+//	-----BEGIN PEDERSEN SHARE-----
+//	Index: 3
+//	Split-ID: 1cq3zku2GwR+UaoMi9MISA==
+//
+//	eyJpbmRleCI6MywiYWJzY2lzc2EiOiI0IiwicGFydHMiOlt7InNfc2hhcmUiOiIx
+//	...
+//	=6QFl0hVr
+//	-----END PEDERSEN SHARE-----
+//
+// Headers are "Key: Value" lines, ended by a blank line. Whitespaces within the
+// armored data are not significant, so the armor can be re-wrapped or re-indented.
+package armor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	iofs "io/fs"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	perrors "github.com/matteoarella/pedersen/internal/errors"
+	"github.com/matteoarella/pedersen/internal/io"
+	"github.com/spf13/afero"
+)
+
+const (
+	lineLen     = 64
+	checksumLen = 6
+
+	indexHeader   = "Index"
+	splitIDHeader = "Split-ID"
+)
+
+var (
+	ErrNotArmored     = errors.New("file is not armored")
+	ErrInvalidArmor   = errors.New("invalid armor")
+	ErrChecksum       = errors.New("armor checksum mismatch")
+	ErrHeaderMismatch = errors.New("armor headers do not match the armored data")
+	ErrBlockType      = errors.New("armor block type does not match the expected data")
+)
+
+var (
+	armorRegexp = regexp.MustCompile(`-----\s*BEGIN\s+PEDERSEN\s+([A-Z][A-Z\s]*?)\s*-----` +
+		`([\s\S]*?)-----\s*END\s+PEDERSEN\s+([A-Z][A-Z\s]*?)\s*-----`)
+
+	// blockTypes overrides the block type derived from the name of the type of the value.
+	blockTypes = map[string]string{
+		"Shares": "SHARE",
+	}
+
+	encoding = base64.RawStdEncoding
+)
+
+type armorIO struct {
+	io.BaseIO
+}
+
+func New(fs afero.Fs) io.IO {
+	return armorIO{
+		BaseIO: io.BaseIO{
+			Fs: fs,
+		},
+	}
+}
+
+func Ext() string {
+	return ".asc"
+}
+
+func (a armorIO) Ext() string {
+	return Ext()
+}
+
+func (a armorIO) ReadFile(name string, v interface{}) error {
+	fileData, err := a.BaseIO.ReadFile(name, Ext())
+	if err != nil {
+		return perrors.WrapErrorf(err, "[armorIO.ReadFile]")
+	}
+
+	return Unmarshal(fileData, v)
+}
+
+func (a armorIO) WriteFile(name string, v interface{}, perm iofs.FileMode) error {
+	armorData, err := Marshal(v)
+	if err != nil {
+		return perrors.WrapErrorf(err, "[armorIO.WriteFile]")
+	}
+
+	if len(filepath.Ext(name)) < 1 {
+		name += a.Ext()
+	}
+
+	return a.BaseIO.WriteFile(name, Ext(), armorData, perm)
+}
+
+// Marshal returns the armored encoding of v.
+func Marshal(v interface{}) ([]byte, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := bodyHeaders(body)
+	if err != nil {
+		return nil, err
+	}
+
+	blockType := typeOf(v)
+
+	var b bytes.Buffer
+
+	b.WriteString("-----BEGIN PEDERSEN " + blockType + "-----\n")
+
+	for _, key := range []string{indexHeader, splitIDHeader} {
+		if value, ok := headers[key]; ok {
+			b.WriteString(key + ": " + value + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+
+	encoded := encoding.EncodeToString(body)
+	for len(encoded) > lineLen {
+		b.WriteString(encoded[:lineLen] + "\n")
+		encoded = encoded[lineLen:]
+	}
+
+	if encoded != "" {
+		b.WriteString(encoded + "\n")
+	}
+
+	b.WriteString("=" + checksum(body) + "\n")
+	b.WriteString("-----END PEDERSEN " + blockType + "-----\n")
+
+	return b.Bytes(), nil
+}
+
+// Unmarshal decodes the armored data into v, after checking its checksum, its
+// headers and its block type. Text around the armor is ignored.
+func Unmarshal(data []byte, v interface{}) error {
+	match := armorRegexp.FindSubmatch(data)
+	if match == nil {
+		return ErrNotArmored
+	}
+
+	blockType := normalizeSpaces(string(match[1]))
+	if blockType != normalizeSpaces(string(match[3])) {
+		return ErrInvalidArmor
+	}
+
+	if blockType != typeOf(v) {
+		return ErrBlockType
+	}
+
+	headers, encoded, err := parseArmor(string(match[2]))
+	if err != nil {
+		return err
+	}
+
+	// the checksum follows the unpadded base64 data
+	parts := strings.Split(encoded, "=")
+	if len(parts) != 2 {
+		return ErrInvalidArmor
+	}
+
+	body, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrInvalidArmor
+	}
+
+	if subtle.ConstantTimeCompare([]byte(checksum(body)), []byte(parts[1])) != 1 {
+		return ErrChecksum
+	}
+
+	bodyHeaders, err := bodyHeaders(body)
+	if err != nil {
+		return ErrInvalidArmor
+	}
+
+	for _, key := range []string{indexHeader, splitIDHeader} {
+		value, ok := headers[key]
+		if ok && value != bodyHeaders[key] {
+			return ErrHeaderMismatch
+		}
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// parseArmor splits the content of the armor into its headers and its armored
+// data. The headers are parsed line by line up to the blank line separating them
+// from the data, so that their values may contain spaces, while the whitespaces
+// of the data are removed.
+func parseArmor(content string) (map[string]string, string, error) {
+	lines := strings.Split(content, "\n")
+	headers := make(map[string]string)
+
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+
+	// base64 data never contains colons, so the armor has no headers otherwise
+	if i < len(lines) && strings.Contains(lines[i], ":") {
+		for ; i < len(lines); i++ {
+			line := strings.TrimSpace(lines[i])
+			if line == "" {
+				break
+			}
+
+			idx := strings.Index(line, ":")
+			if idx <= 0 {
+				return nil, "", ErrInvalidArmor
+			}
+
+			key, value := strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+			if _, ok := headers[key]; ok {
+				return nil, "", ErrInvalidArmor
+			}
+
+			headers[key] = value
+		}
+
+		if i == len(lines) {
+			return nil, "", ErrInvalidArmor
+		}
+	}
+
+	return headers, strings.Join(strings.Fields(strings.Join(lines[i:], "\n")), ""), nil
+}
+
+// IsArmored reports whether the data contains an armor.
+func IsArmored(data []byte) bool {
+	return armorRegexp.Match(data)
+}
+
+func checksum(body []byte) string {
+	digest := sha256.Sum256(body)
+
+	return encoding.EncodeToString(digest[:checksumLen])
+}
+
+// bodyHeaders returns the headers describing the armored JSON data, which are
+// the shareholder index and the split identifier, when the data carries them.
+func bodyHeaders(body []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	if len(body) == 0 || body[0] != '{' {
+		return headers, nil
+	}
+
+	fields := struct {
+		Index   *int   `json:"index"`
+		SplitID string `json:"split_id"`
+	}{}
+
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	if fields.Index != nil {
+		headers[indexHeader] = strconv.Itoa(*fields.Index)
+	}
+
+	if fields.SplitID != "" {
+		headers[splitIDHeader] = fields.SplitID
+	}
+
+	return headers, nil
+}
+
+// typeOf returns the block type of the value, which is derived from the name
+// of its type, e.g. PUBLIC KEY for PublicKey.
+func typeOf(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Name() == "" {
+		return "DATA"
+	}
+
+	if blockType, ok := blockTypes[t.Name()]; ok {
+		return blockType
+	}
+
+	var words []string
+
+	start := 0
+	name := t.Name()
+
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+
+	words = append(words, name[start:])
+
+	return strings.ToUpper(strings.Join(words, " "))
+}
+
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,161 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package armor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen/internal/io/armor"
+	"github.com/stretchr/testify/require"
+)
+
+type Shares struct {
+	Index   *int   `json:"index"`
+	SplitID string `json:"split_id"`
+	Data    string `json:"data"`
+}
+
+type PublicKey struct {
+	Key string `json:"key"`
+}
+
+func testShares() Shares {
+	index := 2
+
+	return Shares{
+		Index:   &index,
+		SplitID: "1cq3zku2GwR+UaoMi9MISA==",
+		Data:    strings.Repeat("armored data of the shareholder ", 8),
+	}
+}
+
+// rewrapArmor re-wraps and re-indents the armor, and surrounds it with text.
+func rewrapArmor(text string) string {
+	var b strings.Builder
+
+	b.WriteString("Hello,\r\nhere is your share:\r\n\r\n")
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.HasPrefix(line, "-----") || strings.Contains(line, ":") || line == "" {
+			b.WriteString("  " + line + "\r\n")
+			continue
+		}
+
+		for len(line) > 40 {
+			b.WriteString("    " + line[:40] + "\r\n")
+			line = line[40:]
+		}
+
+		b.WriteString("    " + line + "\r\n")
+	}
+
+	b.WriteString("\r\nRegards\r\n")
+
+	return b.String()
+}
+
+func TestArmorRoundTrip(t *testing.T) {
+	shares := testShares()
+
+	data, err := armor.Marshal(shares)
+	require.NoError(t, err)
+	require.True(t, armor.IsArmored(data))
+	require.True(t, strings.HasPrefix(string(data),
+		"-----BEGIN PEDERSEN SHARE-----\nIndex: 2\nSplit-ID: 1cq3zku2GwR+UaoMi9MISA==\n\n"))
+	require.True(t, strings.HasSuffix(string(data), "-----END PEDERSEN SHARE-----\n"))
+
+	var decoded Shares
+	require.NoError(t, armor.Unmarshal(data, &decoded))
+	require.Equal(t, shares, decoded)
+
+	// values without headers
+	data, err = armor.Marshal(PublicKey{Key: "key"})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), "-----BEGIN PEDERSEN PUBLIC KEY-----\n\n"))
+
+	var key PublicKey
+	require.NoError(t, armor.Unmarshal(data, &key))
+	require.Equal(t, "key", key.Key)
+}
+
+func TestArmorRewrap(t *testing.T) {
+	shares := testShares()
+
+	data, err := armor.Marshal(shares)
+	require.NoError(t, err)
+
+	var decoded Shares
+	require.NoError(t, armor.Unmarshal([]byte(rewrapArmor(string(data))), &decoded))
+	require.Equal(t, shares, decoded)
+}
+
+func TestArmorHeaders(t *testing.T) {
+	shares := testShares()
+
+	data, err := armor.Marshal(shares)
+	require.NoError(t, err)
+
+	unmarshal := func(text string) error {
+		var decoded Shares
+		return armor.Unmarshal([]byte(text), &decoded)
+	}
+
+	t.Run("header with spaces", func(t *testing.T) {
+		text := strings.Replace(string(data), "Index: 2\n", "Index: 2\nComment: from the ops team\n", 1)
+		require.NoError(t, unmarshal(text))
+
+		var decoded Shares
+		require.NoError(t, armor.Unmarshal([]byte(rewrapArmor(text)), &decoded))
+		require.Equal(t, shares, decoded)
+	})
+
+	t.Run("relabeled share", func(t *testing.T) {
+		text := strings.Replace(string(data), "Index: 2", "Index: 3", 1)
+		require.ErrorIs(t, unmarshal(text), armor.ErrHeaderMismatch)
+	})
+
+	t.Run("duplicated header", func(t *testing.T) {
+		text := strings.Replace(string(data), "Index: 2\n", "Index: 2\nIndex: 2\n", 1)
+		require.ErrorIs(t, unmarshal(text), armor.ErrInvalidArmor)
+	})
+
+	t.Run("missing separator", func(t *testing.T) {
+		text := strings.Replace(string(data), "==\n\n", "==\n", 1)
+		require.ErrorIs(t, unmarshal(text), armor.ErrInvalidArmor)
+	})
+}
+
+func TestArmorChecksum(t *testing.T) {
+	data, err := armor.Marshal(testShares())
+	require.NoError(t, err)
+
+	// a corrupted character of the armored data
+	lines := strings.Split(string(data), "\n")
+	body := []byte(lines[4])
+	if body[10] == 'A' {
+		body[10] = 'B'
+	} else {
+		body[10] = 'A'
+	}
+
+	lines[4] = string(body)
+
+	var decoded Shares
+	require.ErrorIs(t, armor.Unmarshal([]byte(strings.Join(lines, "\n")), &decoded), armor.ErrChecksum)
+}
+
+func TestArmorBlockType(t *testing.T) {
+	data, err := armor.Marshal(testShares())
+	require.NoError(t, err)
+
+	var key PublicKey
+	require.ErrorIs(t, armor.Unmarshal(data, &key), armor.ErrBlockType)
+
+	var decoded Shares
+	require.ErrorIs(t, armor.Unmarshal([]byte("not armored"), &decoded), armor.ErrNotArmored)
+}
@@ -0,0 +1,273 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+// Package mnemonic implements the encoding of data into a sequence of words,
+// which is meant to be written on paper, in the spirit of BIP-39 and SLIP-39.
+// Every word of the list of 1024 words encodes 10 bits, and it is identified by
+// its first 4 letters, so that words can be abbreviated.
+// The last 3 words are the checksum of the others, which detects transcription errors.
+package mnemonic
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	perrors "github.com/matteoarella/pedersen/internal/errors"
+)
+
+const (
+	// WordBits is the number of bits encoded by every word.
+	WordBits = 10
+
+	// ChecksumWords is the number of words of the checksum.
+	ChecksumWords = 3
+
+	wordListLen = 1 << WordBits
+	prefixLen   = 4
+
+	checksumDomain = "pedersen-mnemonic-v1"
+)
+
+var (
+	ErrUnknownWord     = errors.New("unknown word")
+	ErrInvalidChecksum = errors.New("invalid mnemonic checksum")
+	ErrInvalidLength   = errors.New("invalid mnemonic length")
+	ErrInvalidPadding  = errors.New("invalid mnemonic padding")
+)
+
+//go:embed wordlist.txt
+var wordList string
+
+var (
+	words    []string
+	prefixes map[string]int
+)
+
+func init() { //nolint:gochecknoinits
+	words = strings.Fields(wordList)
+	if len(words) != wordListLen {
+		panic("mnemonic: invalid word list length")
+	}
+
+	prefixes = make(map[string]int, len(words))
+
+	for i, word := range words {
+		prefix := word[:prefixLen]
+		if _, ok := prefixes[prefix]; ok {
+			panic("mnemonic: duplicate word prefix " + prefix)
+		}
+
+		prefixes[prefix] = i
+	}
+}
+
+// Encode returns the words encoding the data, followed by the checksum words.
+// The data is prefixed by its length, so that it can be recovered from the
+// words regardless of the padding of the last data word.
+func Encode(data []byte) []string {
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(data)))
+
+	payload := make([]byte, 0, n+len(data))
+	payload = append(payload, length[:n]...)
+	payload = append(payload, data...)
+
+	indexes := toIndexes(payload)
+	indexes = append(indexes, checksum(indexes)...)
+
+	res := make([]string, len(indexes))
+	for i, index := range indexes {
+		res[i] = words[index]
+	}
+
+	return res
+}
+
+// Decode returns the data encoded by the words, after checking the checksum.
+// Words are case insensitive and can be abbreviated to their first 4 letters.
+func Decode(mnemonic []string) ([]byte, error) {
+	if len(mnemonic) <= ChecksumWords {
+		return nil, ErrInvalidLength
+	}
+
+	indexes := make([]int, len(mnemonic))
+
+	for i, word := range mnemonic {
+		index, err := lookup(word)
+		if err != nil {
+			return nil, perrors.WrapErrorf(err, "word %d %q%s", i+1, word, suggestion(word))
+		}
+
+		indexes[i] = index
+	}
+
+	dataIndexes := indexes[:len(indexes)-ChecksumWords]
+	expected := checksum(dataIndexes)
+
+	for i, index := range indexes[len(dataIndexes):] {
+		if index != expected[i] {
+			return nil, ErrInvalidChecksum
+		}
+	}
+
+	payload, err := fromIndexes(dataIndexes)
+	if err != nil {
+		return nil, err
+	}
+
+	length, n := binary.Uvarint(payload)
+	if n <= 0 || length > uint64(len(payload)-n) {
+		return nil, ErrInvalidLength
+	}
+
+	// only the padding of the last word can follow the data
+	if (len(payload)-n-int(length))*8 >= WordBits {
+		return nil, ErrInvalidLength
+	}
+
+	for _, b := range payload[n+int(length):] {
+		if b != 0 {
+			return nil, ErrInvalidPadding
+		}
+	}
+
+	return payload[n : n+int(length)], nil
+}
+
+// lookup returns the index of the word, which can be abbreviated to its first letters.
+func lookup(word string) (int, error) {
+	word = strings.ToLower(word)
+	if len(word) < prefixLen {
+		return 0, ErrUnknownWord
+	}
+
+	index, ok := prefixes[word[:prefixLen]]
+	if !ok || !strings.HasPrefix(words[index], word) {
+		return 0, ErrUnknownWord
+	}
+
+	return index, nil
+}
+
+// suggestion returns a hint with the word of the list which differs from the
+// unknown word by a single letter, if there is only one.
+func suggestion(word string) string {
+	word = strings.ToLower(word)
+	found := ""
+
+	for _, candidate := range words {
+		if editDistanceOne(word, candidate) {
+			if found != "" {
+				return ""
+			}
+
+			found = candidate
+		}
+	}
+
+	if found == "" {
+		return ""
+	}
+
+	return ", did you mean \"" + found + "\"?"
+}
+
+// editDistanceOne reports whether a is turned into b by substituting, inserting
+// or deleting a single letter.
+func editDistanceOne(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	switch len(b) - len(a) {
+	case 0:
+		diff := 0
+		for i := range a {
+			if a[i] != b[i] {
+				diff++
+			}
+		}
+
+		return diff == 1
+	case 1:
+		for i := range b {
+			if b[:i]+b[i+1:] == a {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checksum returns the checksum words of the data words.
+func checksum(indexes []int) []int {
+	h := sha256.New()
+	h.Write([]byte(checksumDomain))
+
+	var buf [2]byte
+	for _, index := range indexes {
+		binary.BigEndian.PutUint16(buf[:], uint16(index))
+		h.Write(buf[:])
+	}
+
+	digest := h.Sum(nil)
+
+	return toIndexes(digest[:(ChecksumWords*WordBits+7)/8])[:ChecksumWords]
+}
+
+// toIndexes splits the data into groups of 10 bits, padding the last group with zeros.
+func toIndexes(data []byte) []int {
+	indexes := make([]int, 0, (len(data)*8+WordBits-1)/WordBits)
+
+	acc, bits := 0, 0
+
+	for _, b := range data {
+		acc = acc<<8 | int(b)
+		bits += 8
+
+		for bits >= WordBits {
+			bits -= WordBits
+			indexes = append(indexes, (acc>>bits)&(wordListLen-1))
+		}
+
+		acc &= 1<<bits - 1
+	}
+
+	if bits > 0 {
+		indexes = append(indexes, (acc<<(WordBits-bits))&(wordListLen-1))
+	}
+
+	return indexes
+}
+
+// fromIndexes joins the groups of 10 bits into bytes, checking that the padding is zero.
+func fromIndexes(indexes []int) ([]byte, error) {
+	data := make([]byte, 0, len(indexes)*WordBits/8)
+
+	acc, bits := 0, 0
+
+	for _, index := range indexes {
+		acc = acc<<WordBits | index
+		bits += WordBits
+
+		for bits >= 8 {
+			bits -= 8
+			data = append(data, byte(acc>>bits))
+		}
+
+		acc &= 1<<bits - 1
+	}
+
+	if acc != 0 {
+		return nil, ErrInvalidPadding
+	}
+
+	return data, nil
+}
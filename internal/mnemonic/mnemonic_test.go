@@ -0,0 +1,199 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package mnemonic_test
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/internal/mnemonic"
+	"github.com/matteoarella/pedersen/internal/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	for length := 0; length < 64; length++ {
+		data := make([]byte, length)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		words := mnemonic.Encode(data)
+
+		decoded, err := mnemonic.Decode(words)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+
+		// words are case insensitive, and can be abbreviated
+		abbreviated := make([]string, len(words))
+		for i, word := range words {
+			abbreviated[i] = strings.ToUpper(word[:4])
+		}
+
+		decoded, err = mnemonic.Decode(abbreviated)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	words := mnemonic.Encode([]byte("a mnemonic encoded share"))
+
+	// a mistyped word is reported along with its position
+	mistyped := append([]string(nil), words...)
+	mistyped[2] = mistyped[2][:len(mistyped[2])-1] + "q"
+	_, err := mnemonic.Decode(mistyped)
+	require.ErrorIs(t, err, mnemonic.ErrUnknownWord)
+	require.Contains(t, err.Error(), "word 3")
+
+	swapped := append([]string(nil), words...)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	_, err = mnemonic.Decode(swapped)
+	require.ErrorIs(t, err, mnemonic.ErrInvalidChecksum)
+
+	_, err = mnemonic.Decode(words[1:])
+	require.ErrorIs(t, err, mnemonic.ErrInvalidChecksum)
+
+	_, err = mnemonic.Decode(words[:mnemonic.ChecksumWords])
+	require.ErrorIs(t, err, mnemonic.ErrInvalidLength)
+}
+
+func testInt(t *testing.T, b ...byte) *big.Int {
+	t.Helper()
+
+	x, err := big.NewInt()
+	require.NoError(t, err)
+
+	if len(b) > 0 {
+		x.SetBytes(b)
+	}
+
+	return x
+}
+
+func TestEncodeDecodeShare(t *testing.T) {
+	index := 3
+	share := schema.Shares{
+		Index:    &index,
+		Abscissa: testInt(t, 0x01, 0x02, 0x03),
+		Parts: []pedersen.SecretPart{
+			{SShare: testInt(t, 0xff, 0x00, 0x01), TShare: testInt(t)},
+			{SShare: testInt(t, 0x42), TShare: testInt(t, 0x10, 0x20)},
+		},
+		SplitID: schema.Bytes("split identifier"),
+		Proofs: []schema.Proof{
+			{
+				Commitments: []*big.Int{testInt(t, 0x07), testInt(t, 0x08, 0x09)},
+				Path:        []schema.Bytes{schema.Bytes("left"), schema.Bytes("right")},
+			},
+		},
+		Fragment: &schema.Fragment{
+			Data:    schema.Bytes("fragment"),
+			Digests: []schema.Bytes{schema.Bytes("digest")},
+			Nonce:   schema.Bytes("nonce"),
+			Length:  42,
+		},
+	}
+
+	words, err := mnemonic.EncodeShare(share)
+	require.NoError(t, err)
+
+	decoded, err := mnemonic.DecodeShare(words)
+	require.NoError(t, err)
+
+	require.Equal(t, *share.Index, *decoded.Index)
+	require.Equal(t, 0, share.Abscissa.Cmp(decoded.Abscissa))
+	require.Equal(t, share.SplitID, decoded.SplitID)
+	require.Len(t, decoded.Parts, len(share.Parts))
+
+	for i, part := range share.Parts {
+		require.Equal(t, 0, part.SShare.Cmp(decoded.Parts[i].SShare))
+		require.Equal(t, 0, part.TShare.Cmp(decoded.Parts[i].TShare))
+	}
+
+	require.Len(t, decoded.Proofs, 1)
+	require.Equal(t, share.Proofs[0].Path, decoded.Proofs[0].Path)
+	require.Len(t, decoded.Proofs[0].Commitments, 2)
+	require.Equal(t, 0, share.Proofs[0].Commitments[1].Cmp(decoded.Proofs[0].Commitments[1]))
+	require.Equal(t, share.Fragment, decoded.Fragment)
+
+	share.Protected = &schema.Protected{}
+	_, err = mnemonic.EncodeShare(share)
+	require.ErrorIs(t, err, schema.ErrUnopenedShare)
+
+	// data which is not a share
+	_, err = mnemonic.DecodeShare(mnemonic.Encode([]byte("not a share")))
+	require.ErrorIs(t, err, schema.ErrInvalidShareEncoding)
+}
+
+func TestEncodeDecodeBundledShare(t *testing.T) {
+	holder := 1
+	first, second := 1, 2
+
+	share := schema.Shares{
+		Index:   &holder,
+		Holder:  "cfo",
+		SplitID: schema.Bytes("split identifier"),
+		Proofs: []schema.Proof{
+			{
+				Commitments: []*big.Int{testInt(t, 0x07), testInt(t, 0x08, 0x09)},
+				Path:        []schema.Bytes{schema.Bytes("left")},
+			},
+		},
+		Bundle: []schema.Shares{
+			{
+				Index:    &first,
+				Abscissa: testInt(t, 0x01, 0x02),
+				Parts:    []pedersen.SecretPart{{SShare: testInt(t, 0x11), TShare: testInt(t, 0x12)}},
+			},
+			{
+				Index:    &second,
+				Abscissa: testInt(t, 0x03, 0x04),
+				Parts:    []pedersen.SecretPart{{SShare: testInt(t, 0x21), TShare: testInt(t)}},
+				Fragment: &schema.Fragment{
+					Data:    schema.Bytes("fragment"),
+					Digests: []schema.Bytes{schema.Bytes("digest")},
+					Nonce:   schema.Bytes("nonce"),
+					Length:  42,
+				},
+			},
+		},
+	}
+
+	words, err := mnemonic.EncodeShare(share)
+	require.NoError(t, err)
+
+	decoded, err := mnemonic.DecodeShare(words)
+	require.NoError(t, err)
+
+	require.Equal(t, holder, *decoded.Index)
+	require.Equal(t, "cfo", decoded.Holder)
+	require.Equal(t, share.SplitID, decoded.SplitID)
+	require.Nil(t, decoded.Abscissa)
+	require.Empty(t, decoded.Parts)
+	require.Len(t, decoded.Proofs, 1)
+	require.Equal(t, share.Proofs[0].Path, decoded.Proofs[0].Path)
+	require.Len(t, decoded.Bundle, 2)
+
+	for i, bundled := range share.Bundle {
+		require.Equal(t, *bundled.Index, *decoded.Bundle[i].Index)
+		require.Equal(t, 0, bundled.Abscissa.Cmp(decoded.Bundle[i].Abscissa))
+		require.Nil(t, decoded.Bundle[i].SplitID)
+		require.Nil(t, decoded.Bundle[i].Proofs)
+		require.Len(t, decoded.Bundle[i].Parts, 1)
+		require.Equal(t, 0, bundled.Parts[0].SShare.Cmp(decoded.Bundle[i].Parts[0].SShare))
+		require.Equal(t, 0, bundled.Parts[0].TShare.Cmp(decoded.Bundle[i].Parts[0].TShare))
+		require.Equal(t, bundled.Fragment, decoded.Bundle[i].Fragment)
+	}
+
+	// the split identifier and the proofs belong to the bundle only
+	share.Bundle[0].SplitID = share.SplitID
+	_, err = mnemonic.EncodeShare(share)
+	require.ErrorIs(t, err, schema.ErrInvalidShareEncoding)
+}
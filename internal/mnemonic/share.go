@@ -0,0 +1,39 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package mnemonic
+
+import (
+	"github.com/matteoarella/pedersen/internal/schema"
+)
+
+// EncodeShare returns the words encoding the share of a single shareholder,
+// including its index, its abscissa, the split identifier, the commitment proofs
+// and the fragment of the short secret sharing mode, or the words encoding the
+// bundled shares of a weighted shareholder.
+func EncodeShare(share schema.Shares) ([]string, error) {
+	data, err := share.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return Encode(data), nil
+}
+
+// DecodeShare returns the share encoded by the words.
+func DecodeShare(mnemonic []string) (schema.Shares, error) {
+	data, err := Decode(mnemonic)
+	if err != nil {
+		return schema.Shares{}, err
+	}
+
+	share := schema.Shares{}
+	if err := share.UnmarshalBinary(data); err != nil {
+		return schema.Shares{}, err
+	}
+
+	return share, nil
+}
@@ -0,0 +1,389 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+)
+
+const (
+	shareEncodingVersion  = 1
+	bundleEncodingVersion = 2
+)
+
+var (
+	ErrInvalidShareEncoding = errors.New("invalid share encoding")
+	ErrUnopenedShare        = errors.New("sealed or protected shares must be opened before being encoded")
+)
+
+// MarshalBinary returns the compact binary encoding of the share of a single
+// shareholder, including its index, its abscissa, the split identifier, the
+// commitment proofs and the fragment of the short secret sharing mode.
+// The share file of a weighted shareholder is encoded with its name, the split
+// identifier and the commitment proofs, followed by the count of its bundled
+// shares and by the index, the abscissa, the parts and the fragment of each one.
+func (s Shares) MarshalBinary() ([]byte, error) {
+	if s.Sealed != nil || s.Protected != nil {
+		return nil, ErrUnopenedShare
+	}
+
+	if s.Index == nil || *s.Index < 0 {
+		return nil, ErrInvalidShareEncoding
+	}
+
+	w := &shareWriter{}
+
+	if len(s.Bundle) > 0 {
+		if s.Abscissa != nil || len(s.Parts) > 0 || s.Fragment != nil {
+			return nil, ErrInvalidShareEncoding
+		}
+
+		w.buf.WriteByte(bundleEncodingVersion)
+		w.uint(uint64(*s.Index))
+		w.bytes([]byte(s.Holder))
+		w.bytes(s.SplitID)
+
+		if err := w.proofs(s.Proofs); err != nil {
+			return nil, err
+		}
+
+		w.uint(uint64(len(s.Bundle)))
+		for _, bundled := range s.Bundle {
+			// the split identifier and the proofs are the ones of the bundle
+			if len(bundled.Bundle) > 0 || bundled.SplitID != nil || bundled.Proofs != nil || bundled.Holder != "" ||
+				bundled.Sealed != nil || bundled.Protected != nil {
+				return nil, ErrInvalidShareEncoding
+			}
+
+			if err := w.share(bundled, true); err != nil {
+				return nil, err
+			}
+		}
+
+		return w.buf.Bytes(), nil
+	}
+
+	w.buf.WriteByte(shareEncodingVersion)
+
+	if err := w.share(s, false); err != nil {
+		return nil, err
+	}
+
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the share from its compact binary encoding.
+func (s *Shares) UnmarshalBinary(data []byte) error {
+	r := &shareReader{r: bytes.NewReader(data)}
+
+	var share Shares
+
+	switch r.byte() {
+	case shareEncodingVersion:
+		share = r.share(false)
+	case bundleEncodingVersion:
+		index := r.int()
+		share = Shares{
+			Index:   &index,
+			Holder:  string(r.bytes()),
+			SplitID: r.bytes(),
+			Proofs:  r.proofs(),
+		}
+
+		bundled := r.len()
+		for i := 0; i < bundled && r.err == nil; i++ {
+			share.Bundle = append(share.Bundle, r.share(true))
+		}
+
+		if r.err == nil && bundled == 0 {
+			return ErrInvalidShareEncoding
+		}
+	default:
+		return ErrInvalidShareEncoding
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+
+	if r.r.Len() != 0 {
+		return ErrInvalidShareEncoding
+	}
+
+	*s = share
+
+	return nil
+}
+
+type shareWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *shareWriter) uint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	w.buf.Write(buf[:n])
+}
+
+func (w *shareWriter) bytes(b []byte) {
+	w.uint(uint64(len(b)))
+	w.buf.Write(b)
+}
+
+// share writes the index, the abscissa, the parts and the fragment of the
+// share. The split identifier and the commitment proofs are written as well,
+// unless the share is bundled, since they are written once for the bundle.
+func (w *shareWriter) share(s Shares, bundled bool) error {
+	if s.Index == nil || *s.Index < 0 || s.Abscissa == nil {
+		return ErrInvalidShareEncoding
+	}
+
+	w.uint(uint64(*s.Index))
+
+	if err := w.int(s.Abscissa); err != nil {
+		return err
+	}
+
+	if !bundled {
+		w.bytes(s.SplitID)
+	}
+
+	w.uint(uint64(len(s.Parts)))
+	for _, part := range s.Parts {
+		if err := w.int(part.SShare); err != nil {
+			return err
+		}
+
+		if err := w.int(part.TShare); err != nil {
+			return err
+		}
+	}
+
+	if !bundled {
+		if err := w.proofs(s.Proofs); err != nil {
+			return err
+		}
+	}
+
+	if s.Fragment == nil {
+		w.buf.WriteByte(0)
+	} else {
+		w.buf.WriteByte(1)
+		w.bytes(s.Fragment.Data)
+
+		w.uint(uint64(len(s.Fragment.Digests)))
+		for _, digest := range s.Fragment.Digests {
+			w.bytes(digest)
+		}
+
+		w.bytes(s.Fragment.Nonce)
+		w.uint(uint64(s.Fragment.Length))
+	}
+
+	return nil
+}
+
+func (w *shareWriter) proofs(proofs []Proof) error {
+	w.uint(uint64(len(proofs)))
+	for _, proof := range proofs {
+		w.uint(uint64(len(proof.Commitments)))
+		for _, commitment := range proof.Commitments {
+			if err := w.int(commitment); err != nil {
+				return err
+			}
+		}
+
+		w.uint(uint64(len(proof.Path)))
+		for _, node := range proof.Path {
+			w.bytes(node)
+		}
+	}
+
+	return nil
+}
+
+func (w *shareWriter) int(x *big.Int) error {
+	if x == nil {
+		return ErrInvalidShareEncoding
+	}
+
+	b, err := x.Bytes()
+	if err != nil {
+		return err
+	}
+
+	w.bytes(b)
+
+	return nil
+}
+
+// shareReader reads the fields of a share, and keeps the first error, so that
+// the following reads return zero values.
+type shareReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (r *shareReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *shareReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+
+	b, err := r.r.ReadByte()
+	if err != nil {
+		r.fail(ErrInvalidShareEncoding)
+	}
+
+	return b
+}
+
+func (r *shareReader) uint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		r.fail(ErrInvalidShareEncoding)
+	}
+
+	return v
+}
+
+// int reads a non negative integer.
+func (r *shareReader) int() int {
+	v := r.uint()
+	if v > uint64(int(^uint(0)>>1)) {
+		r.fail(ErrInvalidShareEncoding)
+		return 0
+	}
+
+	return int(v)
+}
+
+// len reads a length, which cannot exceed the unread data.
+func (r *shareReader) len() int {
+	v := r.uint()
+	if v > uint64(r.r.Len()) {
+		r.fail(ErrInvalidShareEncoding)
+		return 0
+	}
+
+	return int(v)
+}
+
+func (r *shareReader) bytes() []byte {
+	n := r.len()
+	if r.err != nil {
+		return nil
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.fail(ErrInvalidShareEncoding)
+		return nil
+	}
+
+	return b
+}
+
+// share reads a share written by [shareWriter.share].
+func (r *shareReader) share(bundled bool) Shares {
+	index := r.int()
+	share := Shares{
+		Index:    &index,
+		Abscissa: r.bigInt(),
+	}
+
+	if !bundled {
+		share.SplitID = r.bytes()
+	}
+
+	parts := r.len()
+	for i := 0; i < parts && r.err == nil; i++ {
+		share.Parts = append(share.Parts, pedersen.SecretPart{
+			SShare: r.bigInt(),
+			TShare: r.bigInt(),
+		})
+	}
+
+	if !bundled {
+		share.Proofs = r.proofs()
+	}
+
+	if r.byte() == 1 {
+		fragment := &Fragment{
+			Data: r.bytes(),
+		}
+
+		digests := r.len()
+		for i := 0; i < digests && r.err == nil; i++ {
+			fragment.Digests = append(fragment.Digests, r.bytes())
+		}
+
+		fragment.Nonce = r.bytes()
+		fragment.Length = r.int()
+
+		share.Fragment = fragment
+	}
+
+	return share
+}
+
+func (r *shareReader) proofs() []Proof {
+	var proofs []Proof
+
+	count := r.len()
+	for i := 0; i < count && r.err == nil; i++ {
+		proof := Proof{}
+
+		commitments := r.len()
+		for j := 0; j < commitments && r.err == nil; j++ {
+			proof.Commitments = append(proof.Commitments, r.bigInt())
+		}
+
+		path := r.len()
+		for j := 0; j < path && r.err == nil; j++ {
+			proof.Path = append(proof.Path, r.bytes())
+		}
+
+		proofs = append(proofs, proof)
+	}
+
+	return proofs
+}
+
+func (r *shareReader) bigInt() *big.Int {
+	b := r.bytes()
+	if r.err != nil {
+		return nil
+	}
+
+	x, err := big.NewInt()
+	if err != nil {
+		r.fail(err)
+		return nil
+	}
+
+	if len(b) > 0 {
+		x.SetBytes(b)
+	}
+
+	return x
+}
@@ -0,0 +1,36 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package schema
+
+import (
+	"encoding/base64"
+)
+
+// Bytes is a byte slice that is encoded as a base64 string in every file format.
+type Bytes []byte
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (b Bytes) MarshalText() ([]byte, error) {
+	buf := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+	base64.StdEncoding.Encode(buf, b)
+
+	return buf, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (b *Bytes) UnmarshalText(data []byte) error {
+	buf := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+
+	n, err := base64.StdEncoding.Decode(buf, data)
+	if err != nil {
+		return err
+	}
+
+	*b = buf[:n]
+
+	return nil
+}
@@ -19,10 +19,110 @@ type Group struct {
 }
 
 type Shares struct {
-	Abscissa *big.Int              `json:"abscissa" yaml:"abscissa" xml:"abscissa"`
-	Parts    []pedersen.SecretPart `json:"parts" yaml:"parts" xml:"parts"`
+	Index     *int                  `json:"index,omitempty" yaml:"index,omitempty" xml:"index,omitempty"`
+	Holder    string                `json:"holder,omitempty" yaml:"holder,omitempty" xml:"holder,omitempty"`
+	Abscissa  *big.Int              `json:"abscissa,omitempty" yaml:"abscissa,omitempty" xml:"abscissa,omitempty"`
+	Parts     []pedersen.SecretPart `json:"parts,omitempty" yaml:"parts,omitempty" xml:"parts,omitempty"`
+	Fragment  *Fragment             `json:"fragment,omitempty" yaml:"fragment,omitempty" xml:"fragment,omitempty"`
+	SplitID   Bytes                 `json:"split_id,omitempty" yaml:"split_id,omitempty" xml:"split_id,omitempty"`
+	Proofs    []Proof               `json:"proofs,omitempty" yaml:"proofs,omitempty" xml:"proofs,omitempty"`
+	Bundle    []Shares              `json:"bundle,omitempty" yaml:"bundle,omitempty" xml:"bundle,omitempty"`
+	Sealed    *Sealed               `json:"sealed,omitempty" yaml:"sealed,omitempty" xml:"sealed,omitempty"`
+	Protected *Protected            `json:"protected,omitempty" yaml:"protected,omitempty" xml:"protected,omitempty"`
+}
+
+type Sealed struct {
+	Recipient Bytes `json:"recipient" yaml:"recipient" xml:"recipient"`
+	Ephemeral Bytes `json:"ephemeral" yaml:"ephemeral" xml:"ephemeral"`
+	Data      Bytes `json:"data" yaml:"data" xml:"data"`
+}
+
+type Protected struct {
+	KDF  KDF   `json:"kdf" yaml:"kdf" xml:"kdf"`
+	Data Bytes `json:"data" yaml:"data" xml:"data"`
+}
+
+type KDF struct {
+	Name string `json:"name" yaml:"name" xml:"name"`
+	Salt Bytes  `json:"salt" yaml:"salt" xml:"salt"`
+	LogN int    `json:"log_n" yaml:"log_n" xml:"log_n"`
+	R    int    `json:"r" yaml:"r" xml:"r"`
+	P    int    `json:"p" yaml:"p" xml:"p"`
+}
+
+type Proof struct {
+	Commitments []*big.Int `json:"commitments" yaml:"commitments" xml:"commitments"`
+	Path        []Bytes    `json:"path" yaml:"path" xml:"path"`
+}
+
+type Fragment struct {
+	Data    Bytes   `json:"data" yaml:"data" xml:"data"`
+	Digests []Bytes `json:"digests" yaml:"digests" xml:"digests"`
+	Nonce   Bytes   `json:"nonce" yaml:"nonce" xml:"nonce"`
+	Length  int     `json:"length" yaml:"length" xml:"length"`
 }
 
 type Commitments struct {
-	Commitments [][]*big.Int `json:"commitments" yaml:"commitments" xml:"commitments"`
+	Commitments [][]*big.Int           `json:"commitments,omitempty" yaml:"commitments,omitempty" xml:"commitments,omitempty"`
+	SplitID     Bytes                  `json:"split_id,omitempty" yaml:"split_id,omitempty" xml:"split_id,omitempty"`
+	Bindings    []Bytes                `json:"bindings,omitempty" yaml:"bindings,omitempty" xml:"bindings,omitempty"`
+	Root        Bytes                  `json:"root,omitempty" yaml:"root,omitempty" xml:"root,omitempty"`
+	Encoding    pedersen.ChunkEncoding `json:"encoding,omitempty" yaml:"encoding,omitempty" xml:"encoding,omitempty"`
+}
+
+type KeyShare struct {
+	Index        *int     `json:"index" yaml:"index" xml:"index"`
+	Identifier   *big.Int `json:"identifier" yaml:"identifier" xml:"identifier"`
+	SigningShare *big.Int `json:"signing_share" yaml:"signing_share" xml:"signing_share"`
+}
+
+type PublicKey struct {
+	Threshold       int        `json:"threshold" yaml:"threshold" xml:"threshold"`
+	PublicKey       *big.Int   `json:"public_key" yaml:"public_key" xml:"public_key"`
+	Identifiers     []*big.Int `json:"identifiers" yaml:"identifiers" xml:"identifiers"`
+	VerifyingShares []*big.Int `json:"verifying_shares" yaml:"verifying_shares" xml:"verifying_shares"`
+}
+
+type Ciphertext struct {
+	Ephemeral *big.Int `json:"ephemeral" yaml:"ephemeral" xml:"ephemeral"`
+	Data      Bytes    `json:"data" yaml:"data" xml:"data"`
+}
+
+type DecryptionShare struct {
+	Index     *int     `json:"index" yaml:"index" xml:"index"`
+	Share     *big.Int `json:"share" yaml:"share" xml:"share"`
+	Challenge *big.Int `json:"challenge" yaml:"challenge" xml:"challenge"`
+	Response  *big.Int `json:"response" yaml:"response" xml:"response"`
+}
+
+type Attestation struct {
+	Index     *int                  `json:"index" yaml:"index" xml:"index"`
+	Abscissa  *big.Int              `json:"abscissa" yaml:"abscissa" xml:"abscissa"`
+	SplitID   Bytes                 `json:"split_id,omitempty" yaml:"split_id,omitempty" xml:"split_id,omitempty"`
+	Proofs    []Proof               `json:"proofs,omitempty" yaml:"proofs,omitempty" xml:"proofs,omitempty"`
+	Nonces    []*big.Int            `json:"nonces" yaml:"nonces" xml:"nonces"`
+	Responses []pedersen.SecretPart `json:"responses" yaml:"responses" xml:"responses"`
+}
+
+type Recipient struct {
+	Index     *int  `json:"index" yaml:"index" xml:"index"`
+	PublicKey Bytes `json:"public_key" yaml:"public_key" xml:"public_key"`
+}
+
+type Recipients struct {
+	Recipients []Recipient `json:"recipients" yaml:"recipients" xml:"recipients"`
+}
+
+type Identity struct {
+	PublicKey  Bytes `json:"public_key" yaml:"public_key" xml:"public_key"`
+	PrivateKey Bytes `json:"private_key" yaml:"private_key" xml:"private_key"`
+}
+
+type Holder struct {
+	Name   string `json:"name" yaml:"name" xml:"name"`
+	Weight int    `json:"weight" yaml:"weight" xml:"weight"`
+}
+
+type Roster struct {
+	Holders []Holder `json:"holders" yaml:"holders" xml:"holders"`
 }
@@ -0,0 +1,127 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package schnorr
+
+import (
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+var (
+	ErrInvalidProof = errors.New("invalid discrete logarithm equality proof")
+)
+
+// DLEQProof is a non-interactive Chaum-Pedersen proof that log_g1(y1) = log_g2(y2).
+type DLEQProof struct {
+	Challenge *big.Int
+	Response  *big.Int
+}
+
+// dleqChallenge computes the challenge of the proof from the statement and the
+// commitments a1 = g1^k and a2 = g2^k.
+func (s *Suite) dleqChallenge(ctx *big.IntContext, elements ...*big.Int) (*big.Int, error) {
+	inputs := make([][]byte, len(elements))
+
+	for i, x := range elements {
+		data, err := s.EncodeElement(x)
+		if err != nil {
+			return nil, err
+		}
+
+		inputs[i] = data
+	}
+
+	return s.HashToScalar(ctx, "dleq", inputs...)
+}
+
+// ProveDLEQ proves that y1 = g1^x and y2 = g2^x for the secret scalar x.
+func (s *Suite) ProveDLEQ(ctx *big.IntContext, g1, y1, g2, y2, x *big.Int) (*DLEQProof, error) {
+	k, err := s.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+
+	a1, err := s.SecretExp(ctx, g1, k)
+	if err != nil {
+		return nil, err
+	}
+
+	a2, err := s.SecretExp(ctx, g2, k)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.dleqChallenge(ctx, g1, y1, g2, y2, a1, a2)
+	if err != nil {
+		return nil, err
+	}
+
+	// z = k - c*x
+	z, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+	z.SetConstantTime()
+
+	if err := s.Field.Mul(ctx, z, c, x); err != nil {
+		return nil, err
+	}
+
+	if err := s.Field.Sub(ctx, z, k, z); err != nil {
+		return nil, err
+	}
+
+	return &DLEQProof{
+		Challenge: c,
+		Response:  z,
+	}, nil
+}
+
+// VerifyDLEQ verifies that the proof shows that log_g1(y1) = log_g2(y2).
+// The elements must be already validated.
+func (s *Suite) VerifyDLEQ(ctx *big.IntContext, g1, y1, g2, y2 *big.Int, proof *DLEQProof) error {
+	if proof == nil || s.ValidateScalar(proof.Challenge) != nil || s.ValidateScalar(proof.Response) != nil {
+		return ErrInvalidProof
+	}
+
+	// a = g^z * y^c
+	commitment := func(g, y *big.Int) (*big.Int, error) {
+		a, err := s.Exp(ctx, g, proof.Response)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := s.Exp(ctx, y, proof.Challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.Mul(ctx, a, t)
+	}
+
+	a1, err := commitment(g1, y1)
+	if err != nil {
+		return err
+	}
+
+	a2, err := commitment(g2, y2)
+	if err != nil {
+		return err
+	}
+
+	c, err := s.dleqChallenge(ctx, g1, y1, g2, y2, a1, a2)
+	if err != nil {
+		return err
+	}
+
+	if c.Cmp(proof.Challenge) != 0 {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
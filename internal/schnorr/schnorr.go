@@ -0,0 +1,332 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+// Package schnorr implements the group and scalar operations shared by the
+// protocols built on top of the Schnorr groups of the pedersen package.
+package schnorr
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+)
+
+const (
+	// hashSecurityBytes is the number of additional bytes hashed into a scalar,
+	// so that the reduction modulo q is statistically close to uniform.
+	hashSecurityBytes = 16
+)
+
+var (
+	ErrNilGroup          = errors.New("group cannot be nil")
+	ErrInvalidElement    = errors.New("element must be a non-identity element of the subgroup of order q")
+	ErrInvalidScalar     = errors.New("scalar must be in the range [0, q)")
+	ErrInvalidIdentifier = errors.New("identifier must be in the range [1, q)")
+)
+
+// Suite performs the group and scalar operations of a protocol.
+type Suite struct {
+	Group *pedersen.Group
+
+	// Field performs the arithmetic modulo the order Q of the group.
+	Field *big.Field
+
+	ElementLen int
+	ScalarLen  int
+
+	// context is the domain separation string of the protocol.
+	context string
+}
+
+// NewSuite creates a new Suite for the group, whose hashes are domain
+// separated by the context string of the protocol.
+func NewSuite(group *pedersen.Group, context string) (*Suite, error) {
+	if group == nil {
+		return nil, ErrNilGroup
+	}
+
+	field, err := big.NewField(group.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Suite{
+		Group:      group,
+		Field:      field,
+		ElementLen: group.P.BytesLen(),
+		ScalarLen:  group.Q.BytesLen(),
+		context:    context,
+	}, nil
+}
+
+// encode returns x as a big-endian byte slice of the provided length.
+func encode(x *big.Int, length int) ([]byte, error) {
+	data, err := x.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > length {
+		return nil, ErrInvalidScalar
+	}
+
+	buf := make([]byte, length)
+	copy(buf[length-len(data):], data)
+
+	return buf, nil
+}
+
+// EncodeElement returns the group element x as a big-endian byte slice of ElementLen bytes.
+func (s *Suite) EncodeElement(x *big.Int) ([]byte, error) {
+	return encode(x, s.ElementLen)
+}
+
+// EncodeScalar returns the scalar x as a big-endian byte slice of ScalarLen bytes.
+func (s *Suite) EncodeScalar(x *big.Int) ([]byte, error) {
+	return encode(x, s.ScalarLen)
+}
+
+// Digest hashes the data with the context string and a domain separation tag.
+func (s *Suite) Digest(tag string, data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(s.context))
+	h.Write([]byte(tag))
+	h.Write(data)
+
+	return h.Sum(nil)
+}
+
+// HashToScalar hashes the domain separation tag and the inputs into a scalar.
+// The digest is expanded in counter mode up to hashSecurityBytes bytes more than
+// the size of q, and reduced modulo q.
+func (s *Suite) HashToScalar(ctx *big.IntContext, tag string, inputs ...[]byte) (*big.Int, error) {
+	outLen := s.ScalarLen + hashSecurityBytes
+	out := make([]byte, 0, outLen+sha256.Size)
+	buf := make([]byte, 8)
+
+	for counter := uint32(0); len(out) < outLen; counter++ {
+		h := sha256.New()
+		h.Write([]byte(s.context))
+		h.Write([]byte(tag))
+
+		binary.BigEndian.PutUint32(buf, counter)
+		h.Write(buf[:4])
+
+		for _, input := range inputs {
+			binary.BigEndian.PutUint64(buf, uint64(len(input)))
+			h.Write(buf)
+			h.Write(input)
+		}
+
+		out = h.Sum(out)
+	}
+
+	x := new(big.Int).SetBytes(out[:outLen])
+
+	return s.Field.NewElement(ctx, x)
+}
+
+// ValidateElement checks that x is an element of the subgroup of order q other
+// than the identity, which would cancel any secret exponent.
+func (s *Suite) ValidateElement(ctx *big.IntContext, x *big.Int) error {
+	if x == nil || x.Cmp(big.One()) <= 0 || x.Cmp(s.Group.P) >= 0 {
+		return ErrInvalidElement
+	}
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	order, err := ctx.GetInt()
+	if err != nil {
+		return err
+	}
+
+	if err := order.ModExp(ctx, x, s.Group.Q, s.Group.P); err != nil {
+		return err
+	}
+
+	if order.Cmp(big.One()) != 0 {
+		return ErrInvalidElement
+	}
+
+	return nil
+}
+
+// ValidateScalar checks that x is in the range [0, q).
+func (s *Suite) ValidateScalar(x *big.Int) error {
+	if x == nil || !s.Field.Contains(x) {
+		return ErrInvalidScalar
+	}
+
+	return nil
+}
+
+// ValidateIdentifier checks that x is in the range [1, q).
+func (s *Suite) ValidateIdentifier(x *big.Int) error {
+	if x == nil || x.Cmp(big.One()) < 0 || x.Cmp(s.Group.Q) >= 0 {
+		return ErrInvalidIdentifier
+	}
+
+	return nil
+}
+
+// BaseExp computes g^e mod p, where e is a secret exponent.
+func (s *Suite) BaseExp(ctx *big.IntContext, e *big.Int) (*big.Int, error) {
+	return s.SecretExp(ctx, s.Group.G, e)
+}
+
+// SecretExp computes x^e mod p, where e is a secret exponent.
+func (s *Suite) SecretExp(ctx *big.IntContext, x, e *big.Int) (*big.Int, error) {
+	z, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	exp, err := ctx.GetInt()
+	if err != nil {
+		return nil, err
+	}
+	exp.SetConstantTime()
+
+	if err := exp.Set(e); err != nil {
+		return nil, err
+	}
+
+	if err := z.ModExpMont(nil, ctx, x, exp, s.Group.P); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// Exp computes x^e mod p, where e is public.
+func (s *Suite) Exp(ctx *big.IntContext, x, e *big.Int) (*big.Int, error) {
+	z, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := z.ModExp(ctx, x, e, s.Group.P); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// Mul computes x*y mod p.
+func (s *Suite) Mul(ctx *big.IntContext, x, y *big.Int) (*big.Int, error) {
+	z, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := z.ModMul(ctx, x, y, s.Group.P); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// RandomScalar returns a random scalar in the range [1, q).
+func (s *Suite) RandomScalar() (*big.Int, error) {
+	x, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+	x.SetConstantTime()
+
+	for {
+		if err := x.RandRange(s.Group.Q); err != nil {
+			return nil, err
+		}
+
+		if x.BitLen() > 0 {
+			return x, nil
+		}
+	}
+}
+
+// LagrangeCoefficient computes the Lagrange basis value of the identifier at x,
+// with respect to the provided identifiers, which must contain it and be distinct.
+func (s *Suite) LagrangeCoefficient(ctx *big.IntContext, x, identifier *big.Int, identifiers []*big.Int) (*big.Int, error) {
+	num, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := num.SetUInt64(1); err != nil {
+		return nil, err
+	}
+
+	den, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := den.SetUInt64(1); err != nil {
+		return nil, err
+	}
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	diff, err := ctx.GetInt()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range identifiers {
+		if other.Cmp(identifier) == 0 {
+			continue
+		}
+
+		if err := s.Field.Sub(ctx, diff, x, other); err != nil {
+			return nil, err
+		}
+
+		if err := s.Field.Mul(ctx, num, num, diff); err != nil {
+			return nil, err
+		}
+
+		if err := s.Field.Sub(ctx, diff, identifier, other); err != nil {
+			return nil, err
+		}
+
+		if err := s.Field.Mul(ctx, den, den, diff); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.Field.Inverse(ctx, den, den); err != nil {
+		return nil, err
+	}
+
+	if err := s.Field.Mul(ctx, num, num, den); err != nil {
+		return nil, err
+	}
+
+	return num, nil
+}
+
+// Zero returns a new scalar set to zero.
+func Zero() (*big.Int, error) {
+	z, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := z.SetUInt64(0); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package seal
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// ScryptKDF is the name of the scrypt key derivation function.
+	ScryptKDF = "scrypt"
+
+	// DefaultScryptLogN is the default base 2 logarithm of the scrypt cost
+	// parameter, which requires 32 MiB of memory with the default block size.
+	DefaultScryptLogN = 15
+
+	// DefaultScryptR is the default scrypt block size parameter.
+	DefaultScryptR = 8
+
+	// DefaultScryptP is the default scrypt parallelization parameter.
+	DefaultScryptP = 1
+
+	saltSize = 16
+
+	// maxScryptLogN, maxScryptR and maxScryptP bound the parameters read along
+	// with untrusted protected data, while maxScryptMemory bounds the memory
+	// required for opening it, which is 128*R*2^LogN bytes.
+	maxScryptLogN   = 20
+	maxScryptR      = 32
+	maxScryptP      = 16
+	maxScryptMemory = 1 << 30
+)
+
+var (
+	ErrEmptyPassphrase = errors.New("passphrase cannot be empty")
+	ErrInvalidKDF      = errors.New("invalid key derivation parameters")
+)
+
+// KDFParams represents the parameters of the key derivation function, which are
+// stored along with the protected data.
+type KDFParams struct {
+	// Name is the name of the key derivation function, which is always scrypt.
+	Name string
+
+	// Salt is the random salt.
+	Salt []byte
+
+	// LogN is the base 2 logarithm of the cost parameter.
+	LogN int
+
+	// R is the block size parameter.
+	R int
+
+	// P is the parallelization parameter.
+	P int
+}
+
+// NewKDFParams returns the default scrypt parameters with a fresh random salt.
+func NewKDFParams() (KDFParams, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return KDFParams{}, err
+	}
+
+	return KDFParams{
+		Name: ScryptKDF,
+		Salt: salt,
+		LogN: DefaultScryptLogN,
+		R:    DefaultScryptR,
+		P:    DefaultScryptP,
+	}, nil
+}
+
+// Validate checks that the parameters are supported, and that they do not
+// require an unreasonable amount of memory or time.
+func (k KDFParams) Validate() error {
+	if k.Name != ScryptKDF || len(k.Salt) < saltSize {
+		return ErrInvalidKDF
+	}
+
+	if k.LogN < 1 || k.LogN > maxScryptLogN || k.R < 1 || k.R > maxScryptR || k.P < 1 || k.P > maxScryptP {
+		return ErrInvalidKDF
+	}
+
+	if int64(128*k.R)<<k.LogN > maxScryptMemory {
+		return ErrInvalidKDF
+	}
+
+	return nil
+}
+
+func (k KDFParams) deriveKey(passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+
+	return scrypt.Key(passphrase, k.Salt, 1<<k.LogN, k.R, k.P, chacha20poly1305.KeySize)
+}
+
+// Protect encrypts and authenticates the plaintext and the additional data with
+// a key derived from the passphrase.
+// The parameters must carry a fresh salt, since the derived key is used with a fixed nonce.
+func Protect(passphrase []byte, params KDFParams, plaintext, additionalData []byte) ([]byte, error) {
+	key, err := params.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	return aead.Seal(nil, nonce, plaintext, additionalData), nil
+}
+
+// Unprotect decrypts and authenticates the ciphertext and the additional data
+// protected with the passphrase.
+func Unprotect(passphrase []byte, params KDFParams, ciphertext, additionalData []byte) ([]byte, error) {
+	key, err := params.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrOpen
+	}
+
+	return plaintext, nil
+}
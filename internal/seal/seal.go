@@ -0,0 +1,159 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+// Package seal implements the authenticated public key encryption of share files
+// for their recipients.
+// The plaintext is encrypted with ChaCha20-Poly1305 under a key derived with
+// HKDF-SHA256 from the X25519 shared secret between a fresh ephemeral key and
+// the public key of the recipient, so that only the holder of the private key
+// can open it.
+// Alternatively, the plaintext is protected with a key derived from a passphrase
+// with scrypt, whose parameters are stored along with the ciphertext.
+package seal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// KeySize is the size of both the private and the public keys.
+	KeySize = curve25519.ScalarSize
+
+	info = "pedersen-seal-v1"
+)
+
+var (
+	ErrInvalidKey = errors.New("X25519 keys must be 32 bytes long")
+	ErrOpen       = errors.New("sealed data cannot be opened")
+)
+
+// GenerateKey returns a new random X25519 key pair.
+func GenerateKey() (private, public []byte, err error) {
+	private = make([]byte, KeySize)
+	if _, err := rand.Read(private); err != nil {
+		return nil, nil, err
+	}
+
+	public, err = PublicKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return private, public, nil
+}
+
+// PublicKey returns the public key related to the private key.
+func PublicKey(private []byte) ([]byte, error) {
+	if len(private) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return curve25519.X25519(private, curve25519.Basepoint)
+}
+
+// Seal encrypts and authenticates the plaintext and the additional data for
+// the recipient public key.
+// It returns the ephemeral public key and the ciphertext, both needed by Open.
+func Seal(recipient, plaintext, additionalData []byte) (ephemeral, ciphertext []byte, err error) {
+	if len(recipient) != KeySize {
+		return nil, nil, ErrInvalidKey
+	}
+
+	private, ephemeral, err := GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shared, err := curve25519.X25519(private, recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := deriveKey(shared, ephemeral, recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the key is never reused, since the ephemeral key is fresh
+	nonce := make([]byte, aead.NonceSize())
+
+	return ephemeral, aead.Seal(nil, nonce, plaintext, additionalData), nil
+}
+
+// Open decrypts and authenticates the ciphertext and the additional data
+// sealed for the public key related to the private key.
+func Open(private, ephemeral, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ephemeral) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	recipient, err := PublicKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := curve25519.X25519(private, ephemeral)
+	if err != nil {
+		return nil, ErrOpen
+	}
+
+	key, err := deriveKey(shared, ephemeral, recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrOpen
+	}
+
+	return plaintext, nil
+}
+
+// Matches reports whether the public key is related to the private key.
+func Matches(private, public []byte) bool {
+	derived, err := PublicKey(private)
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(derived, public) == 1
+}
+
+// deriveKey derives the encryption key from the shared secret, binding both
+// the ephemeral and the recipient public keys.
+func deriveKey(shared, ephemeral, recipient []byte) ([]byte, error) {
+	salt := make([]byte, 0, 2*KeySize)
+	salt = append(salt, ephemeral...)
+	salt = append(salt, recipient...)
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(info)), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
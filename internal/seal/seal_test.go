@@ -0,0 +1,94 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package seal_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen/internal/seal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	private, public, err := seal.GenerateKey()
+	require.NoError(t, err)
+	require.Len(t, public, seal.KeySize)
+	require.True(t, seal.Matches(private, public))
+
+	plaintext := []byte("sealed share")
+	additionalData := []byte("shareholder")
+
+	ephemeral, ciphertext, err := seal.Seal(public, plaintext, additionalData)
+	require.NoError(t, err)
+
+	opened, err := seal.Open(private, ephemeral, ciphertext, additionalData)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, opened)
+
+	// the ciphertext is bound to the additional data
+	_, err = seal.Open(private, ephemeral, ciphertext, []byte("another shareholder"))
+	require.ErrorIs(t, err, seal.ErrOpen)
+
+	// the ciphertext can be opened only by its recipient
+	otherPrivate, otherPublic, err := seal.GenerateKey()
+	require.NoError(t, err)
+	require.False(t, seal.Matches(otherPrivate, public))
+
+	_, err = seal.Open(otherPrivate, ephemeral, ciphertext, additionalData)
+	require.ErrorIs(t, err, seal.ErrOpen)
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[0] ^= 1
+	_, err = seal.Open(private, ephemeral, tampered, additionalData)
+	require.ErrorIs(t, err, seal.ErrOpen)
+
+	_, _, err = seal.Seal(otherPublic[:16], plaintext, additionalData)
+	require.ErrorIs(t, err, seal.ErrInvalidKey)
+}
+
+func TestProtectUnprotect(t *testing.T) {
+	params, err := seal.NewKDFParams()
+	require.NoError(t, err)
+	require.NoError(t, params.Validate())
+
+	// keep the test fast
+	params.LogN = 10
+
+	passphrase := []byte("correct horse battery staple")
+	plaintext := []byte("protected share")
+	additionalData := []byte("shareholder")
+
+	ciphertext, err := seal.Protect(passphrase, params, plaintext, additionalData)
+	require.NoError(t, err)
+
+	opened, err := seal.Unprotect(passphrase, params, ciphertext, additionalData)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, opened)
+
+	_, err = seal.Unprotect([]byte("wrong passphrase"), params, ciphertext, additionalData)
+	require.ErrorIs(t, err, seal.ErrOpen)
+
+	_, err = seal.Unprotect(passphrase, params, ciphertext, []byte("another shareholder"))
+	require.ErrorIs(t, err, seal.ErrOpen)
+
+	_, err = seal.Protect(nil, params, plaintext, additionalData)
+	require.ErrorIs(t, err, seal.ErrEmptyPassphrase)
+
+	for _, invalid := range []seal.KDFParams{
+		{Name: "pbkdf2", Salt: params.Salt, LogN: 10, R: 8, P: 1},
+		{Name: seal.ScryptKDF, Salt: params.Salt[:4], LogN: 10, R: 8, P: 1},
+		{Name: seal.ScryptKDF, Salt: params.Salt, LogN: 40, R: 8, P: 1},
+		{Name: seal.ScryptKDF, Salt: params.Salt, LogN: 10, R: 0, P: 1},
+		// parameters of a crafted share file requiring an unbounded amount of memory
+		{Name: seal.ScryptKDF, Salt: params.Salt, LogN: 20, R: 1 << 20, P: 1},
+		{Name: seal.ScryptKDF, Salt: params.Salt, LogN: 20, R: 16, P: 1},
+		{Name: seal.ScryptKDF, Salt: params.Salt, LogN: 10, R: 8, P: 1 << 20},
+	} {
+		_, err = seal.Unprotect(passphrase, invalid, ciphertext, additionalData)
+		require.ErrorIs(t, err, seal.ErrInvalidKDF)
+	}
+}
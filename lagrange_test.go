@@ -0,0 +1,186 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen/big"
+	"github.com/stretchr/testify/require"
+)
+
+// naiveInterpolateAtZero interpolates f(0) computing every Lagrange basis value
+// from scratch, with one modular inversion for each pair of abscissae.
+func naiveInterpolateAtZero(ctx *big.IntContext, xSamples, ySamples []*big.Int, order *big.Int) (*big.Int, error) {
+	result, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.SetUInt64(0); err != nil {
+		return nil, err
+	}
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	for j := range xSamples {
+		basis, err := ctx.GetInt()
+		if err != nil {
+			return nil, err
+		}
+		if err := basis.SetUInt64(1); err != nil {
+			return nil, err
+		}
+
+		for k := range xSamples {
+			if j == k {
+				continue
+			}
+
+			denom, err := ctx.GetInt()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := denom.Sub(xSamples[k], xSamples[j]); err != nil {
+				return nil, err
+			}
+
+			if err := denom.ModInverse(ctx, denom, order); err != nil {
+				return nil, err
+			}
+
+			if err := basis.ModMul(ctx, basis, xSamples[k], order); err != nil {
+				return nil, err
+			}
+
+			if err := basis.ModMul(ctx, basis, denom, order); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := basis.ModMul(ctx, basis, ySamples[j], order); err != nil {
+			return nil, err
+		}
+
+		if err := result.Add(result, basis); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := result.Mod(ctx, result, order); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type interpolationFixture struct {
+	ctx       *big.IntContext
+	order     *big.Int
+	field     *big.Field
+	xSamples  []*big.Int
+	ySamples  [][]*big.Int
+	intercept []*big.Int
+}
+
+func newInterpolationFixture(tb testing.TB, bits, threshold, chunks int) interpolationFixture {
+	tb.Helper()
+
+	group, err := NewSchnorrGroup(bits)
+	require.NoError(tb, err)
+
+	ctx, err := big.NewIntContext()
+	require.NoError(tb, err)
+
+	field, err := big.NewField(group.Q)
+	require.NoError(tb, err)
+
+	f := interpolationFixture{
+		ctx:       ctx,
+		order:     group.Q,
+		field:     field,
+		xSamples:  make([]*big.Int, threshold),
+		ySamples:  make([][]*big.Int, chunks),
+		intercept: make([]*big.Int, chunks),
+	}
+
+	require.NoError(tb, randInts(f.xSamples, big.One(), group.Q, true))
+
+	for chunk := 0; chunk < chunks; chunk++ {
+		poly, err := newPolynomial(nil, threshold-1, field)
+		require.NoError(tb, err)
+
+		f.intercept[chunk] = poly.coefficients[0]
+		f.ySamples[chunk] = make([]*big.Int, threshold)
+
+		for i, x := range f.xSamples {
+			f.ySamples[chunk][i], err = poly.evaluate(ctx, x)
+			require.NoError(tb, err)
+		}
+	}
+
+	return f
+}
+
+func TestLagrangeCoefficients(t *testing.T) {
+	f := newInterpolationFixture(t, 128, 7, 4)
+	defer f.ctx.Destroy()
+
+	coefficients, err := lagrangeCoefficients(f.ctx, f.xSamples, f.field)
+	require.NoError(t, err)
+
+	for chunk := range f.ySamples {
+		value, err := interpolateAtZero(f.ctx, coefficients, f.ySamples[chunk], f.field)
+		require.NoError(t, err)
+		require.Equal(t, 0, value.Cmp(f.intercept[chunk]))
+
+		naive, err := naiveInterpolateAtZero(f.ctx, f.xSamples, f.ySamples[chunk], f.order)
+		require.NoError(t, err)
+		require.Equal(t, 0, value.Cmp(naive))
+	}
+
+	// duplicate abscissae have no inverse
+	_, err = lagrangeCoefficients(f.ctx, []*big.Int{f.xSamples[0], f.xSamples[0]}, f.field)
+	require.Error(t, err)
+}
+
+const (
+	benchmarkInterpolationChunks = 256
+)
+
+func BenchmarkInterpolateNaive_1024_10(b *testing.B) {
+	f := newInterpolationFixture(b, 1024, 10, benchmarkInterpolationChunks)
+	defer f.ctx.Destroy()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for chunk := range f.ySamples {
+			_, err := naiveInterpolateAtZero(f.ctx, f.xSamples, f.ySamples[chunk], f.order)
+			require.NoError(b, err)
+		}
+	}
+}
+
+func BenchmarkInterpolatePrecomputed_1024_10(b *testing.B) {
+	f := newInterpolationFixture(b, 1024, 10, benchmarkInterpolationChunks)
+	defer f.ctx.Destroy()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		coefficients, err := lagrangeCoefficients(f.ctx, f.xSamples, f.field)
+		require.NoError(b, err)
+
+		for chunk := range f.ySamples {
+			_, err := interpolateAtZero(f.ctx, coefficients, f.ySamples[chunk], f.field)
+			require.NoError(b, err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+const (
+	merkleLeafPrefix  = 0x00
+	merkleNodePrefix  = 0x01
+	merkleCountPrefix = 0x02
+)
+
+var (
+	ErrInvalidProof = errors.New("commitments are not authenticated by the Merkle root")
+)
+
+// CommitmentProof represents the commitments of a chunk together with the
+// authentication path from the leaf of the chunk to the root of the Merkle tree.
+type CommitmentProof struct {
+	// Commitments is the vector of commitments of the chunk.
+	Commitments []*big.Int
+
+	// Path is the vector of the sibling hashes from the leaf up to the root.
+	Path [][]byte
+}
+
+// merkleLeaf returns the leaf hash of a chunk binding.
+// Leaves and inner nodes are hashed with different prefixes, so that an inner
+// node can never be presented as a leaf.
+func merkleLeaf(binding []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleLeafPrefix})
+	h.Write(binding)
+
+	return h.Sum(nil)
+}
+
+func merkleNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleNodePrefix})
+	h.Write(left)
+	h.Write(right)
+
+	return h.Sum(nil)
+}
+
+// merkleCount returns the root committing both to the tree root and to the
+// number of its leaves, so that a tree cannot be presented with fewer chunks.
+func merkleCount(chunks int, node []byte) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(chunks))
+
+	h := sha256.New()
+	h.Write([]byte{merkleCountPrefix})
+	h.Write(buf)
+	h.Write(node)
+
+	return h.Sum(nil)
+}
+
+// merkleDepth returns the length of the authentication paths of a tree with
+// the provided number of leaves.
+func merkleDepth(chunks int) int {
+	depth := 0
+	for 1<<depth < chunks {
+		depth++
+	}
+
+	return depth
+}
+
+// merkleTree builds the Merkle tree whose leaves are the provided chunk bindings,
+// and returns its root together with the authentication path of every leaf.
+// The root commits to the number of leaves.
+// The number of leaves is padded to a power of two with empty leaves, so that
+// the direction of every step of a path is given by the bits of the chunk index.
+func merkleTree(bindings [][]byte) ([]byte, [][][]byte) {
+	width := 1
+	for width < len(bindings) {
+		width <<= 1
+	}
+
+	level := make([][]byte, width)
+	for i := range level {
+		if i < len(bindings) {
+			level[i] = merkleLeaf(bindings[i])
+		} else {
+			level[i] = merkleLeaf(nil)
+		}
+	}
+
+	paths := make([][][]byte, len(bindings))
+
+	for depth := 0; len(level) > 1; depth++ {
+		for chunkIdx := range paths {
+			sibling := (chunkIdx >> depth) ^ 1
+			paths[chunkIdx] = append(paths[chunkIdx], level[sibling])
+		}
+
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			next[i] = merkleNode(level[2*i], level[2*i+1])
+		}
+
+		level = next
+	}
+
+	return merkleCount(len(bindings), level[0]), paths
+}
+
+// merkleRoot computes the root reached from the leaf with index chunkIdx of a
+// tree with the provided number of leaves following the authentication path.
+func merkleRoot(leaf []byte, chunkIdx, chunks int, path [][]byte) ([]byte, error) {
+	if chunkIdx < 0 || chunkIdx >= chunks || len(path) != merkleDepth(chunks) {
+		return nil, ErrInvalidProof
+	}
+
+	node := leaf
+
+	for depth, sibling := range path {
+		if len(sibling) != sha256.Size {
+			return nil, ErrInvalidProof
+		}
+
+		if (chunkIdx>>depth)&1 == 0 {
+			node = merkleNode(node, sibling)
+		} else {
+			node = merkleNode(sibling, node)
+		}
+	}
+
+	return merkleCount(chunks, node), nil
+}
+
+// proofs returns the commitment proof of every chunk, or nil if the shares
+// have not been obtained with the MerkleCommitments option.
+func (s *Shares) proofs() []CommitmentProof {
+	if s.Root == nil {
+		return nil
+	}
+
+	proofs := make([]CommitmentProof, len(s.Commitments))
+
+	for chunkIdx := range proofs {
+		proofs[chunkIdx].Commitments = s.Commitments[chunkIdx]
+
+		if chunkIdx < len(s.Paths) {
+			proofs[chunkIdx].Path = s.Paths[chunkIdx]
+		}
+	}
+
+	return proofs
+}
+
+// validateProof checks that the commitments of the proof are the ones of the
+// chunk with index chunkIdx of the split of the transcript, which is made of
+// the provided number of chunks.
+func (p *Pedersen) validateProof(chunkIdx, chunks int, proof CommitmentProof, t *Transcript) error {
+	if len(proof.Commitments) != p.threshold {
+		return ErrInsufficientCommitments
+	}
+
+	for _, commitment := range proof.Commitments {
+		if commitment == nil {
+			return ErrNilCommitment
+		}
+	}
+
+	binding, err := chunkBinding(t.SplitID, t.Encoding, chunkIdx, proof.Commitments)
+	if err != nil {
+		return err
+	}
+
+	root, err := merkleRoot(merkleLeaf(binding), chunkIdx, chunks, proof.Path)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(root, t.Root) != 1 {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
+
+// validatePaths checks that the authentication paths of the shares lead to their root.
+func (p *Pedersen) validatePaths(s *Shares) error {
+	if s.Root == nil {
+		return nil
+	}
+
+	if len(s.Paths) != len(s.Commitments) {
+		return ErrInvalidProof
+	}
+
+	t := s.Transcript()
+
+	for chunkIdx, proof := range s.proofs() {
+		if err := p.validateProof(chunkIdx, len(s.Commitments), proof, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VerifyProof verifies that the commitments of the proof are the commitments of
+// the chunk with index chunkIdx of the split of the transcript, which must carry
+// the Merkle root of the split. The number of chunks of the split, that is the
+// number of proofs of every share, is committed to by the root.
+// Once verified, the commitments can be provided to [Pedersen.Verify].
+func (p *Pedersen) VerifyProof(chunkIdx, chunks int, proof CommitmentProof, transcript *Transcript) error {
+	if transcript == nil {
+		return ErrNilTranscript
+	}
+
+	if transcript.Root == nil || len(transcript.SplitID) == 0 {
+		return ErrInvalidProof
+	}
+
+	return p.validateProof(chunkIdx, chunks, proof, transcript)
+}
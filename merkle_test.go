@@ -0,0 +1,191 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleTree(t *testing.T) {
+	for leaves := 1; leaves <= 9; leaves++ {
+		bindings := make([][]byte, leaves)
+		for i := range bindings {
+			bindings[i] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+		}
+
+		root, paths := merkleTree(bindings)
+		require.Len(t, paths, leaves)
+
+		for chunkIdx, binding := range bindings {
+			computed, err := merkleRoot(merkleLeaf(binding), chunkIdx, leaves, paths[chunkIdx])
+			require.NoError(t, err)
+			require.Equal(t, root, computed, "leaves %d, chunk %d", leaves, chunkIdx)
+
+			// the root commits to the number of leaves
+			if chunkIdx < leaves-1 && len(paths[chunkIdx]) == merkleDepth(leaves-1) {
+				computed, err := merkleRoot(merkleLeaf(binding), chunkIdx, leaves-1, paths[chunkIdx])
+				require.NoError(t, err)
+				require.NotEqual(t, root, computed)
+			}
+
+			if leaves > 1 {
+				// a leaf cannot be authenticated at another position
+				other := (chunkIdx + 1) % leaves
+				computed, err := merkleRoot(merkleLeaf(binding), other, leaves, paths[chunkIdx])
+				require.NoError(t, err)
+				require.NotEqual(t, root, computed)
+			}
+		}
+
+		// the index must be lower than the number of leaves
+		_, err := merkleRoot(merkleLeaf(bindings[0]), leaves, leaves, paths[0])
+		require.ErrorIs(t, err, ErrInvalidProof)
+
+		// the path length must match the number of leaves
+		_, err = merkleRoot(merkleLeaf(bindings[0]), 0, 2*leaves, paths[0])
+		require.ErrorIs(t, err, ErrInvalidProof)
+	}
+}
+
+func TestMerkleCommitments(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(5, 3, CyclicGroup(group), MerkleCommitments())
+	require.NoError(t, err)
+	defer p.Close()
+	require.True(t, p.IsMerkle())
+
+	secret := bytes.Repeat([]byte("a secret split into many chunks "), 4)
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+	require.Greater(t, len(shares.Commitments), 2)
+	require.NotNil(t, shares.Root)
+	require.Len(t, shares.Paths, len(shares.Commitments))
+	require.NoError(t, p.VerifyShares(shares))
+
+	// only the split identifier and the root are distributed
+	transcript := &Transcript{
+		SplitID:  shares.SplitID,
+		Root:     shares.Root,
+		Encoding: shares.Encoding,
+	}
+
+	subset, err := shares.Subset(4, 0, 2)
+	require.NoError(t, err)
+
+	for _, share := range subset {
+		require.Len(t, share.Proofs, len(shares.Commitments))
+		require.NoError(t, p.VerifyShare(share, transcript))
+
+		for chunkIdx, proof := range share.Proofs {
+			require.NoError(t, p.VerifyProof(chunkIdx, len(share.Proofs), proof, transcript))
+			require.NoError(t, p.Verify(share.Abscissa, share.Parts[chunkIdx], proof.Commitments))
+		}
+	}
+
+	require.NoError(t, p.VerifyShareholders(subset, transcript))
+
+	reconstructed, err := p.CombineShareholders(subset, transcript)
+	require.NoError(t, err)
+	assert.Equal(t, secret, reconstructed)
+
+	// the full transcript is accepted as well
+	reconstructed, err = p.CombineShareholders(subset, shares.Transcript())
+	require.NoError(t, err)
+	assert.Equal(t, secret, reconstructed)
+
+	t.Run("proof at another position", func(t *testing.T) {
+		require.ErrorIs(t, p.VerifyProof(1, len(subset[0].Proofs), subset[0].Proofs[0], transcript), ErrInvalidProof)
+
+		share := subset[0]
+		share.Proofs = append([]CommitmentProof(nil), share.Proofs...)
+		share.Proofs[0], share.Proofs[1] = share.Proofs[1], share.Proofs[0]
+
+		require.ErrorIs(t, p.VerifyShare(share, transcript), ErrInvalidProof)
+	})
+
+	t.Run("tampered path", func(t *testing.T) {
+		share := subset[1]
+		share.Proofs = append([]CommitmentProof(nil), share.Proofs...)
+
+		path := append([][]byte(nil), share.Proofs[0].Path...)
+		path[0] = bytes.Repeat([]byte{0xff}, len(path[0]))
+		share.Proofs[0].Path = path
+
+		_, err := p.CombineShareholders([]Share{subset[0], share, subset[2]}, transcript)
+		require.ErrorIs(t, err, ErrInvalidProof)
+	})
+
+	t.Run("missing proofs", func(t *testing.T) {
+		share := subset[1]
+		share.Proofs = nil
+
+		err := p.VerifyShareholders([]Share{subset[0], share, subset[2]}, transcript)
+		require.ErrorIs(t, err, ErrWrongSharesLen)
+	})
+
+	t.Run("truncated proofs", func(t *testing.T) {
+		truncated := make([]Share, len(subset))
+		for i, share := range subset {
+			share.Parts = share.Parts[:len(share.Parts)-1]
+			share.Proofs = share.Proofs[:len(share.Proofs)-1]
+			truncated[i] = share
+		}
+
+		require.ErrorIs(t, p.VerifyShareholders(truncated, transcript), ErrInvalidProof)
+
+		_, err := p.CombineShareholders(truncated, transcript)
+		require.ErrorIs(t, err, ErrInvalidProof)
+
+		require.ErrorIs(t, p.VerifyShare(truncated[0], transcript), ErrInvalidProof)
+		require.ErrorIs(t, p.VerifyProof(0, len(truncated[0].Proofs), truncated[0].Proofs[0], transcript), ErrInvalidProof)
+
+		for i, share := range subset {
+			share.Parts = nil
+			share.Proofs = nil
+			truncated[i] = share
+		}
+
+		require.Error(t, p.VerifyShareholders(truncated, transcript))
+
+		_, err = p.CombineShareholders(truncated, transcript)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong root", func(t *testing.T) {
+		other, err := p.Split(secret, shares.Abscissae)
+		require.NoError(t, err)
+
+		forged := &Transcript{
+			SplitID:  shares.SplitID,
+			Root:     other.Root,
+			Encoding: shares.Encoding,
+		}
+
+		require.ErrorIs(t, p.VerifyShareholders(subset, forged), ErrInvalidProof)
+		require.ErrorIs(t, p.VerifyProof(0, len(subset[0].Proofs), subset[0].Proofs[0], forged), ErrInvalidProof)
+
+		tampered := *shares
+		tampered.Root = other.Root
+		require.ErrorIs(t, p.VerifyShares(&tampered), ErrInvalidProof)
+
+		tampered = *shares
+		tampered.Paths = append([][][]byte(nil), shares.Paths...)
+		tampered.Paths[0], tampered.Paths[1] = tampered.Paths[1], tampered.Paths[0]
+		require.ErrorIs(t, p.VerifyShares(&tampered), ErrInvalidProof)
+	})
+
+	t.Run("transcript without root", func(t *testing.T) {
+		require.ErrorIs(t, p.VerifyProof(0, len(subset[0].Proofs), subset[0].Proofs[0], &Transcript{SplitID: shares.SplitID}), ErrInvalidProof)
+	})
+}
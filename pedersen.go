@@ -16,6 +16,7 @@ import (
 
 const (
 	defaultGroupPrimeBitLen = 128
+	defaultBatchSize        = 64
 	minThreshold            = 2
 )
 
@@ -52,13 +53,107 @@ func ConcLimit(concLimit int) Option {
 	}
 }
 
+// The BatchVerification option sets the maximum number of secret parts of
+// a shareholder that are verified at once, by checking a random linear combination
+// of them. When a batch does not verify, its secret parts are verified individually
+// for finding the wrong one.
+// If a size less than 2 is provided, every secret part is verified individually.
+func BatchVerification(size int) Option {
+	return func(p *Pedersen) {
+		if size < 1 {
+			size = 1
+		}
+
+		p.batchSize = size
+	}
+}
+
+// The ConstantTime option enables the constant-time mode, which is meant for
+// environments where an attacker can measure the running time of the operations.
+// In this mode the commitments are computed with the OpenSSL constant-time
+// modular exponentiation instead of the fixed-base precomputation tables of the
+// group, whose lookups depend on the digits of the secret exponents.
+// Regardless of this option, the secret chunks, the polynomial coefficients and
+// the values derived from them carry the OpenSSL constant-time flag and are
+// processed without secret-dependent branches.
+// The constant-time mode makes splitting and verification slower.
+func ConstantTime() Option {
+	return func(p *Pedersen) {
+		p.constantTime = true
+	}
+}
+
+// The MerkleCommitments option arranges the commitments of the chunks of every
+// split as the leaves of a Merkle tree, whose root is set in [Shares.Root].
+// Only the split identifier and the root need to be distributed to every
+// shareholder, while each share carries the commitments of its chunks together
+// with their authentication paths (see [Share.Proofs]).
+func MerkleCommitments() Option {
+	return func(p *Pedersen) {
+		p.merkle = true
+	}
+}
+
+// The LegacyTranscripts option accepts the transcripts of splits produced by
+// previous versions, which carry neither the split identifier nor the bindings
+// of the commitments, so that their chunks are not bound to their position.
+// Without this option such transcripts are rejected with [ErrUnboundTranscript],
+// so that the bindings of a split cannot be removed for reordering its chunks
+// or for mixing it with another split.
+func LegacyTranscripts() Option {
+	return func(p *Pedersen) {
+		p.legacyTranscripts = true
+	}
+}
+
+// The WeightedRoster option splits secrets among the weighted shareholders of
+// the roster (see [Pedersen.SplitWeighted]). The number of parts must be the
+// sum of the weights, and the threshold counts the weight of the shareholders.
+func WeightedRoster(roster *Roster) Option {
+	return func(p *Pedersen) {
+		p.roster = roster
+	}
+}
+
+// The Encoding option sets the encoding of the secret into the chunks that are split
+// into secret parts. [CompactEncoding] is used by default, while [LegacyEncoding]
+// produces shares that can be combined by previous versions.
+// The encoding is recorded in the shares, so that shares of either encoding
+// can always be combined.
+func Encoding(encoding ChunkEncoding) Option {
+	return func(p *Pedersen) {
+		p.encoding = encoding
+	}
+}
+
 // A Pedersen struct used for splitting, reconstructing, and verifying secrets.
 type Pedersen struct {
 	group *Group
 
-	threshold int
-	parts     int
-	concLimit int
+	// field performs the arithmetic modulo the order Q of the group.
+	field *big.Field
+
+	threshold    int
+	parts        int
+	concLimit    int
+	batchSize    int
+	constantTime bool
+	merkle       bool
+	encoding     ChunkEncoding
+
+	// legacyTranscripts reports whether transcripts without bindings are accepted.
+	legacyTranscripts bool
+
+	// roster holds the weights of the weighted shareholders, if any.
+	roster *Roster
+
+	// safePrime reports whether p=2q+1.
+	safePrime bool
+
+	// tables holds the precomputation tables for the generators of the group.
+	tables *fixedBaseTables
+
+	pool *contextPool
 }
 
 func (p *Pedersen) validate() error {
@@ -70,6 +165,14 @@ func (p *Pedersen) validate() error {
 		return ErrInsufficientSharesParts
 	}
 
+	if !p.encoding.valid() {
+		return ErrInvalidEncoding
+	}
+
+	if p.roster != nil && p.roster.Parts() != p.parts {
+		return ErrRosterMismatch
+	}
+
 	return nil
 }
 
@@ -77,9 +180,17 @@ func (p *Pedersen) validate() error {
 // With such a scheme a secret is split into parts shares, of which at least threshold
 // are required to reconstruct the secret.
 // A new randomly generated cyclic group is used if none is provided.
+//
+// By default the commitments g^s*h^t to the secret values are computed with the
+// fixed-base precomputation tables of the group, whose memory accesses depend on
+// the digits of the secret exponents, so they are not constant time and may leak
+// the secrets through cache timing to an attacker sharing the machine.
+// The [ConstantTime] option must be used wherever such an attacker is a concern.
 func NewPedersen(parts, threshold int, options ...Option) (*Pedersen, error) {
 	defaultPedersenOptions := []Option{
 		ConcLimit(defaultConcLimit),
+		BatchVerification(defaultBatchSize),
+		Encoding(defaultChunkEncoding),
 	}
 
 	p := &Pedersen{
@@ -113,9 +224,38 @@ func NewPedersen(parts, threshold int, options ...Option) (*Pedersen, error) {
 		return nil, err
 	}
 
+	field, err := big.NewField(p.group.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	p.field = field
+
+	safePrime, err := p.group.isSafePrime()
+	if err != nil {
+		return nil, err
+	}
+
+	p.safePrime = safePrime
+	p.tables = p.group.fixedBaseTables()
+
+	pool, err := newContextPool(p.group.P, p.concLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	p.pool = pool
+
 	return p, nil
 }
 
+// Close releases the OpenSSL resources held by the Pedersen struct.
+// Operations that are running when Close is called complete normally,
+// while any later operation returns [ErrClosed].
+func (p *Pedersen) Close() error {
+	return p.pool.close()
+}
+
 // GetThreshold returns the threshold of the Pedersen struct.
 func (p *Pedersen) GetThreshold() int {
 	return p.threshold
@@ -137,42 +277,68 @@ func (p *Pedersen) GetConcLimit() int {
 	return p.concLimit
 }
 
-func (p *Pedersen) adjustConcLimit(num int) int {
-	concLimit := p.GetConcLimit()
-	if concLimit > num {
-		return num
-	}
+// IsConstantTime reports whether the constant-time mode of the Pedersen struct is enabled.
+func (p *Pedersen) IsConstantTime() bool {
+	return p.constantTime
+}
 
-	return concLimit
+// IsMerkle reports whether the Pedersen struct arranges the commitments in a Merkle tree.
+func (p *Pedersen) IsMerkle() bool {
+	return p.merkle
 }
 
-func (p *Pedersen) balanceIndices(length, numChunks int) []chunkRange {
-	var ranges []chunkRange
+// AcceptsLegacyTranscripts reports whether the Pedersen struct accepts the
+// transcripts of splits produced by previous versions.
+func (p *Pedersen) AcceptsLegacyTranscripts() bool {
+	return p.legacyTranscripts
+}
 
-	chunkSize := (length + numChunks - 1) / numChunks
+// GetRoster returns the roster of the weighted shareholders, or nil if the
+// WeightedRoster option is not set.
+func (p *Pedersen) GetRoster() *Roster {
+	return p.roster
+}
 
-	for start := 0; start < length; start += chunkSize {
-		end := start + chunkSize
+// GetEncoding returns the encoding of the secret into chunks used by the Pedersen struct.
+func (p *Pedersen) GetEncoding() ChunkEncoding {
+	return p.encoding
+}
 
-		if end > length {
-			end = length
-		}
+// GetBatchSize returns the maximum number of secret parts that are verified at once
+// by the Pedersen struct.
+func (p *Pedersen) GetBatchSize() int {
+	return p.batchSize
+}
 
-		ranges = append(ranges, chunkRange{
-			start: start,
-			end:   end,
-		})
+func (p *Pedersen) adjustConcLimit(num int) int {
+	concLimit := p.GetConcLimit()
+	if concLimit > num {
+		return num
 	}
 
-	return ranges
+	return concLimit
 }
 
+// commit computes the commitment g^s*h^t mod p, using the fixed-base tables
+// of the group whenever the exponents are small enough and the constant-time
+// mode is disabled.
 func (p *Pedersen) commit(
 	mont *big.MontgomeryContext,
 	ctx *big.IntContext,
 	s *big.Int,
 	t *big.Int,
 ) (*big.Int, error) {
+	if !p.constantTime {
+		tables, err := p.tables.get(p.group)
+		if err != nil {
+			return nil, err
+		}
+
+		if tables.supports(s) && tables.supports(t) {
+			return tables.commit(mont, ctx, s, t)
+		}
+	}
+
 	gs, err := big.NewInt()
 	if err != nil {
 		return nil, err
@@ -182,11 +348,22 @@ func (p *Pedersen) commit(
 		return nil, err
 	}
 
-	if err := gs.ModExpMont(mont, ctx, p.group.G, s, p.group.P); err != nil {
+	// the exponents are flagged so that OpenSSL uses the constant-time exponentiation
+	sExp, err := constantTimeCopy(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	tExp, err := constantTimeCopy(ctx, t)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := ht.ModExpMont(mont, ctx, p.group.H, t, p.group.P); err != nil {
+	if err := gs.ModExpMont(mont, ctx, p.group.G, sExp, p.group.P); err != nil {
+		return nil, err
+	}
+
+	if err := ht.ModExpMont(mont, ctx, p.group.H, tExp, p.group.P); err != nil {
 		return nil, err
 	}
 
@@ -196,3 +373,18 @@ func (p *Pedersen) commit(
 
 	return gs, nil
 }
+
+// constantTimeCopy returns a temporary copy of x carrying the constant-time flag.
+func constantTimeCopy(ctx *big.IntContext, x *big.Int) (*big.Int, error) {
+	y, err := ctx.GetInt()
+	if err != nil {
+		return nil, err
+	}
+	y.SetConstantTime()
+
+	if err := y.Set(x); err != nil {
+		return nil, err
+	}
+
+	return y, nil
+}
@@ -12,24 +12,26 @@ import (
 
 type polynomial struct {
 	coefficients []*big.Int
-	order        *big.Int
+	field        *big.Field
 }
 
+// genRandNum returns a random number in the range [min, max).
 func genRandNum(min, max *big.Int) (*big.Int, error) {
-	bg, err := big.NewInt()
+	span, err := big.NewInt()
 	if err != nil {
 		return nil, err
 	}
 
-	if err := bg.Set(max); err != nil {
+	if err := span.Sub(max, min); err != nil {
 		return nil, err
 	}
 
-	if err := bg.Sub(bg, min); err != nil {
+	bg, err := big.NewInt()
+	if err != nil {
 		return nil, err
 	}
 
-	if err := bg.RandRange(max); err != nil {
+	if err := bg.RandRange(span); err != nil {
 		return nil, err
 	}
 
@@ -40,17 +42,10 @@ func genRandNum(min, max *big.Int) (*big.Int, error) {
 	return bg, nil
 }
 
-func newPolynomial(intercept *big.Int, degree int, order *big.Int) (polynomial, error) {
-	var err error
+func newPolynomial(intercept *big.Int, degree int, field *big.Field) (polynomial, error) {
 	p := polynomial{
 		coefficients: make([]*big.Int, degree+1),
-	}
-	p.order, err = big.NewInt()
-	if err != nil {
-		return polynomial{}, err
-	}
-	if err := p.order.Set(order); err != nil {
-		return polynomial{}, err
+		field:        field,
 	}
 
 	min, err := big.NewInt()
@@ -62,6 +57,8 @@ func newPolynomial(intercept *big.Int, degree int, order *big.Int) (polynomial,
 		return polynomial{}, err
 	}
 
+	order := field.Modulus()
+
 	if intercept == nil {
 		p.coefficients[0], _ = genRandNum(min, order)
 	} else {
@@ -81,75 +78,81 @@ func newPolynomial(intercept *big.Int, degree int, order *big.Int) (polynomial,
 		return p, err
 	}
 
+	for _, coefficient := range p.coefficients {
+		coefficient.SetConstantTime()
+	}
+
 	return p, nil
 }
 
+// evaluate computes the polynomial at x with Horner's method, reducing
+// every intermediate value modulo the order of the field.
+// The same operations are performed for every x, including zero.
 func (p *polynomial) evaluate(ctx *big.IntContext, x *big.Int) (*big.Int, error) {
-	zero, err := ctx.GetInt()
-	if err != nil {
-		return nil, err
-	}
-
-	if err := zero.SetUInt64(0); err != nil {
-		return nil, err
-	}
-
 	out, err := big.NewInt()
 	if err != nil {
 		return nil, err
 	}
 
-	if x.Cmp(zero) == 0 {
-		if err := out.Set(p.coefficients[0]); err != nil {
-			return nil, err
-		}
-
-		return out, nil
-	}
-
 	degree := len(p.coefficients) - 1
 
-	if err := out.Set(p.coefficients[degree]); err != nil {
+	if err := p.field.Reduce(ctx, out, p.coefficients[degree]); err != nil {
 		return nil, err
 	}
 
 	for i := degree - 1; i >= 0; i-- {
-		if err := out.Mul(ctx, out, x); err != nil {
+		if err := p.field.Mul(ctx, out, out, x); err != nil {
 			return nil, err
 		}
 
-		if err := out.Add(out, p.coefficients[i]); err != nil {
+		if err := p.field.Add(ctx, out, out, p.coefficients[i]); err != nil {
 			return nil, err
 		}
 	}
 
-	if err := out.Mod(ctx, out, p.order); err != nil {
-		return nil, err
-	}
-
 	return out, nil
 }
 
-func interpolatePolynomial(ctx *big.IntContext, xSamples, ySamples []*big.Int, x, order *big.Int) (*big.Int, error) {
+// lagrangeCoefficients computes the Lagrange basis coefficients at x=0 for the
+// provided abscissae, so that f(0) = Σ coefficients[j]·f(xSamples[j]) mod order
+// for every polynomial f of degree less than len(xSamples).
+// Since the coefficients only depend on the abscissae, they can be reused for
+// interpolating every chunk that has been split with the same abscissae.
+// All the denominators are inverted at once using Montgomery's batch inversion trick,
+// so only one modular inversion is performed.
+func lagrangeCoefficients(ctx *big.IntContext, xSamples []*big.Int, field *big.Field) ([]*big.Int, error) {
 	limit := len(xSamples)
-	result, err := big.NewInt()
-	if err != nil {
-		return nil, err
-	}
+	coefficients := make([]*big.Int, limit)
 
-	if err := result.SetUInt64(0); err != nil {
-		return nil, err
+	if limit == 0 {
+		return coefficients, nil
 	}
 
 	ctx.Attach()
 	defer ctx.Detach()
 
+	// coefficients[j] = Π_{k≠j} x_k and denoms[j] = Π_{k≠j} (x_k - x_j)
+	denoms := make([]*big.Int, limit)
+
 	for j := 0; j < limit; j++ {
-		basis, err := ctx.GetInt()
+		num, err := big.NewInt()
 		if err != nil {
 			return nil, err
 		}
-		if err := basis.SetUInt64(1); err != nil {
+		if err := num.SetUInt64(1); err != nil {
+			return nil, err
+		}
+
+		denom, err := ctx.GetInt()
+		if err != nil {
+			return nil, err
+		}
+		if err := denom.SetUInt64(1); err != nil {
+			return nil, err
+		}
+
+		diff, err := ctx.GetInt()
+		if err != nil {
 			return nil, err
 		}
 
@@ -158,62 +161,112 @@ func interpolatePolynomial(ctx *big.IntContext, xSamples, ySamples []*big.Int, x
 				continue
 			}
 
-			num, err := ctx.GetInt()
-			if err != nil {
+			if err := field.Mul(ctx, num, num, xSamples[k]); err != nil {
 				return nil, err
 			}
 
-			if err := num.Set(xSamples[k]); err != nil {
+			if err := field.Sub(ctx, diff, xSamples[k], xSamples[j]); err != nil {
 				return nil, err
 			}
 
-			if err := num.Sub(num, x); err != nil {
+			if err := field.Mul(ctx, denom, denom, diff); err != nil {
 				return nil, err
 			}
+		}
 
-			denom, err := ctx.GetInt()
-			if err != nil {
-				return nil, err
-			}
+		coefficients[j] = num
+		denoms[j] = denom
+	}
 
-			if err := denom.Set(xSamples[k]); err != nil {
-				return nil, err
-			}
+	// prefix[j] = denoms[0]·...·denoms[j]
+	prefix := make([]*big.Int, limit)
 
-			if err := denom.Sub(denom, xSamples[j]); err != nil {
-				return nil, err
-			}
+	for j := 0; j < limit; j++ {
+		prod, err := ctx.GetInt()
+		if err != nil {
+			return nil, err
+		}
 
-			if err := denom.ModInverse(ctx, denom, order); err != nil {
-				return nil, err
-			}
+		if j == 0 {
+			err = prod.Set(denoms[0])
+		} else {
+			err = field.Mul(ctx, prod, prefix[j-1], denoms[j])
+		}
+		if err != nil {
+			return nil, err
+		}
 
-			if err := num.Mul(ctx, num, denom); err != nil {
-				return nil, err
-			}
+		prefix[j] = prod
+	}
+
+	// inv = (denoms[0]·...·denoms[limit-1])^-1
+	inv, err := ctx.GetInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := field.Inverse(ctx, inv, prefix[limit-1]); err != nil {
+		return nil, err
+	}
+
+	denomInv, err := ctx.GetInt()
+	if err != nil {
+		return nil, err
+	}
 
-			if err := basis.Mul(ctx, basis, num); err != nil {
+	for j := limit - 1; j >= 0; j-- {
+		// denoms[j]^-1 = inv·denoms[0]·...·denoms[j-1]
+		if j > 0 {
+			if err := field.Mul(ctx, denomInv, inv, prefix[j-1]); err != nil {
 				return nil, err
 			}
 
-			if err := basis.Mod(ctx, basis, order); err != nil {
+			// inv = (denoms[0]·...·denoms[j-1])^-1
+			if err := field.Mul(ctx, inv, inv, denoms[j]); err != nil {
 				return nil, err
 			}
-		}
-
-		if err := basis.Mul(ctx, basis, ySamples[j]); err != nil {
+		} else if err := denomInv.Set(inv); err != nil {
 			return nil, err
 		}
 
-		if err := result.Add(result, basis); err != nil {
+		if err := field.Mul(ctx, coefficients[j], coefficients[j], denomInv); err != nil {
 			return nil, err
 		}
 	}
 
-	if err := result.Mod(ctx, result, order); err != nil {
+	return coefficients, nil
+}
+
+// interpolateAtZero computes f(0) from the samples of f, given the Lagrange basis
+// coefficients at x=0 of the samples abscissae.
+func interpolateAtZero(ctx *big.IntContext, coefficients, ySamples []*big.Int, field *big.Field) (*big.Int, error) {
+	result, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.SetUInt64(0); err != nil {
+		return nil, err
+	}
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	term, err := ctx.GetInt()
+	if err != nil {
 		return nil, err
 	}
 
+	for j := range coefficients {
+		if err := field.Mul(ctx, term, coefficients[j], ySamples[j]); err != nil {
+			return nil, err
+		}
+
+		if err := field.Add(ctx, result, result, term); err != nil {
+			return nil, err
+		}
+	}
+
 	return result, nil
 }
 
@@ -0,0 +1,623 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+// Package pvss implements the publicly verifiable secret sharing scheme of
+// Schoenmakers over the Schnorr groups of the pedersen package.
+//
+// Unlike Pedersen verifiable secret sharing, the shares are not delivered
+// privately: the dealer encrypts every share to the public key of its
+// shareholder and publishes the encrypted shares in a [Transcript], together
+// with proofs that they are consistent with the commitments of the sharing
+// polynomial. Anyone holding the transcript can verify that the dealer
+// distributed correct shares, and every shareholder proves the correct
+// decryption of its share during the reconstruction.
+//
+// The public keys of the shareholders are y_i = g^(x_i), while the commitments
+// of the polynomial are C_j = h^(a_j), where g and h are the generators G and H
+// of the group. The secret data is encrypted with AES-256-GCM under a key
+// derived from g^s, where s is the constant term of the polynomial.
+package pvss
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/internal/schnorr"
+)
+
+const (
+	contextString = "PVSS-PEDERSEN-SCHNORR-SHA256-v1"
+)
+
+var (
+	ErrNilTranscript         = errors.New("transcript cannot be nil")
+	ErrInvalidThreshold      = errors.New("threshold must be at least 2 and at most the number of shareholders")
+	ErrWrongTranscriptLen    = errors.New("public keys, encrypted shares and proofs must have the same length")
+	ErrWrongCommitmentsLen   = errors.New("commitments length must be equal to threshold")
+	ErrInvalidShareholder    = errors.New("invalid shareholder index")
+	ErrInvalidEncryptedShare = errors.New("encrypted share is not consistent with the commitments")
+	ErrInvalidDecryptedShare = errors.New("invalid decrypted share")
+	ErrInsufficientShares    = errors.New("decrypted shares cannot be less than threshold")
+	ErrDuplicateShareholder  = errors.New("decrypted shares must belong to distinct shareholders")
+	ErrInvalidPrivateKey     = errors.New("private key does not match the public key of the shareholder")
+	ErrDecryption            = errors.New("secret cannot be decrypted")
+	ErrInvalidElement        = schnorr.ErrInvalidElement
+	ErrDuplicatePublicKey    = errors.New("public keys must be distinct")
+	ErrNilKeyPair            = errors.New("key pair cannot be nil")
+)
+
+// Proof is a non-interactive Chaum-Pedersen proof of equality of discrete logarithms.
+type Proof struct {
+	Challenge *big.Int
+	Response  *big.Int
+}
+
+// KeyPair is the key pair of a shareholder.
+type KeyPair struct {
+	// Private is the private key x.
+	Private *big.Int
+
+	// Public is the public key g^x.
+	Public *big.Int
+}
+
+// Transcript represents the public data of a sharing, which is published by the dealer.
+type Transcript struct {
+	// Threshold is the minimum number of shareholders required for reconstructing the secret.
+	Threshold int
+
+	// PublicKeys is the vector of the public keys of the shareholders, so
+	// PublicKeys[shareholderIdx] is the public key of the shareholder with index shareholderIdx.
+	PublicKeys []*big.Int
+
+	// Commitments is the vector of the commitments h^(a_j) to the coefficients
+	// of the sharing polynomial.
+	Commitments []*big.Int
+
+	// EncryptedShares is the vector of the encrypted shares y_i^p(i), so
+	// EncryptedShares[shareholderIdx] is the encrypted share of the shareholder
+	// with index shareholderIdx.
+	EncryptedShares []*big.Int
+
+	// Proofs is the vector of the proofs that log_h(X_i) = log_y_i(Y_i), where
+	// X_i is the evaluation of the commitments at the abscissa of the shareholder
+	// and Y_i is its encrypted share.
+	Proofs []Proof
+
+	// Ciphertext is the encryption of the secret data.
+	Ciphertext []byte
+}
+
+// DecryptedShare represents the share g^p(i) decrypted by a shareholder, with
+// the proof that log_g(y_i) = log_S_i(Y_i), where S_i is the decrypted share.
+type DecryptedShare struct {
+	// Index is the index of the shareholder.
+	Index int
+
+	// Share is the decrypted share g^p(i).
+	Share *big.Int
+
+	// Proof is the proof of the correct decryption of the share.
+	Proof Proof
+}
+
+func newSuite(group *pedersen.Group) (*schnorr.Suite, error) {
+	return schnorr.NewSuite(group, contextString)
+}
+
+// abscissa returns the abscissa of the shareholder with the provided index.
+func abscissa(index int) (*big.Int, error) {
+	x, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := x.SetUInt64(uint64(index) + 1); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// GenerateKey generates the key pair of a shareholder.
+func GenerateKey(group *pedersen.Group) (*KeyPair, error) {
+	s, err := newSuite(group)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	private, err := s.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+
+	public, err := s.BaseExp(ctx, private)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyPair{
+		Private: private,
+		Public:  public,
+	}, nil
+}
+
+// newAEAD returns the AEAD keyed by the hash of the secret element g^s, and the
+// additional data binding the ciphertext to the first commitment.
+func newAEAD(s *schnorr.Suite, secret, commitment *big.Int) (cipher.AEAD, []byte, error) {
+	key, err := s.EncodeElement(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ad, err := s.EncodeElement(commitment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(s.Digest("key", key))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return aead, ad, nil
+}
+
+// Deal shares the secret data among the shareholders with the provided public
+// keys, `threshold` of which are required to reconstruct it.
+func Deal(group *pedersen.Group, threshold int, publicKeys []*big.Int, secret []byte) (*Transcript, error) {
+	s, err := newSuite(group)
+	if err != nil {
+		return nil, err
+	}
+
+	if threshold < 2 || threshold > len(publicKeys) {
+		return nil, ErrInvalidThreshold
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	if err := validatePublicKeys(ctx, s, publicKeys); err != nil {
+		return nil, err
+	}
+
+	coefficients := make([]*big.Int, threshold)
+	for j := range coefficients {
+		coefficients[j], err = s.RandomScalar()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t := &Transcript{
+		Threshold:       threshold,
+		PublicKeys:      publicKeys,
+		Commitments:     make([]*big.Int, threshold),
+		EncryptedShares: make([]*big.Int, len(publicKeys)),
+		Proofs:          make([]Proof, len(publicKeys)),
+	}
+
+	for j, coefficient := range coefficients {
+		t.Commitments[j], err = s.SecretExp(ctx, s.Group.H, coefficient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, publicKey := range publicKeys {
+		x, err := abscissa(i)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := evalPolynomial(ctx, s, coefficients, x)
+		if err != nil {
+			return nil, err
+		}
+
+		evaluation, err := s.SecretExp(ctx, s.Group.H, value)
+		if err != nil {
+			return nil, err
+		}
+
+		t.EncryptedShares[i], err = s.SecretExp(ctx, publicKey, value)
+		if err != nil {
+			return nil, err
+		}
+
+		proof, err := s.ProveDLEQ(ctx, s.Group.H, evaluation, publicKey, t.EncryptedShares[i], value)
+		if err != nil {
+			return nil, err
+		}
+
+		t.Proofs[i] = Proof{
+			Challenge: proof.Challenge,
+			Response:  proof.Response,
+		}
+	}
+
+	secretElement, err := s.BaseExp(ctx, coefficients[0])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, ad, err := newAEAD(s, secretElement, t.Commitments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	t.Ciphertext = aead.Seal(nil, make([]byte, aead.NonceSize()), secret, ad)
+
+	return t, nil
+}
+
+// evalPolynomial evaluates the polynomial with the provided coefficients at x.
+func evalPolynomial(ctx *big.IntContext, s *schnorr.Suite, coefficients []*big.Int, x *big.Int) (*big.Int, error) {
+	y, err := schnorr.Zero()
+	if err != nil {
+		return nil, err
+	}
+	y.SetConstantTime()
+
+	for j := len(coefficients) - 1; j >= 0; j-- {
+		if err := s.Field.Mul(ctx, y, y, x); err != nil {
+			return nil, err
+		}
+
+		if err := s.Field.Add(ctx, y, y, coefficients[j]); err != nil {
+			return nil, err
+		}
+	}
+
+	return y, nil
+}
+
+// evalCommitments evaluates the commitments at x, which yields h^p(x).
+func evalCommitments(ctx *big.IntContext, s *schnorr.Suite, commitments []*big.Int, x *big.Int) (*big.Int, error) {
+	result := big.One()
+
+	for j := len(commitments) - 1; j >= 0; j-- {
+		var err error
+
+		result, err = s.Exp(ctx, result, x)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err = s.Mul(ctx, result, commitments[j])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func validatePublicKeys(ctx *big.IntContext, s *schnorr.Suite, publicKeys []*big.Int) error {
+	seen := make(map[string]struct{}, len(publicKeys))
+
+	for _, publicKey := range publicKeys {
+		if err := s.ValidateElement(ctx, publicKey); err != nil {
+			return err
+		}
+
+		key := publicKey.Hex()
+		if _, ok := seen[key]; ok {
+			return ErrDuplicatePublicKey
+		}
+		seen[key] = struct{}{}
+	}
+
+	return nil
+}
+
+// validate checks that the transcript is well formed, and that its elements
+// belong to the group.
+func (t *Transcript) validate(ctx *big.IntContext, s *schnorr.Suite) error {
+	if t == nil {
+		return ErrNilTranscript
+	}
+
+	if len(t.EncryptedShares) != len(t.PublicKeys) || len(t.Proofs) != len(t.PublicKeys) {
+		return ErrWrongTranscriptLen
+	}
+
+	if t.Threshold < 2 || t.Threshold > len(t.PublicKeys) {
+		return ErrInvalidThreshold
+	}
+
+	if len(t.Commitments) != t.Threshold {
+		return ErrWrongCommitmentsLen
+	}
+
+	if err := validatePublicKeys(ctx, s, t.PublicKeys); err != nil {
+		return err
+	}
+
+	for _, commitment := range t.Commitments {
+		if err := s.ValidateElement(ctx, commitment); err != nil {
+			return err
+		}
+	}
+
+	for _, share := range t.EncryptedShares {
+		if err := s.ValidateElement(ctx, share); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Verify verifies that every encrypted share of the transcript is consistent
+// with the commitments, so that any threshold shareholders reconstruct the same secret.
+// It requires only public data, so it can be run by anyone.
+func (t *Transcript) Verify(group *pedersen.Group) error {
+	s, err := newSuite(group)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return err
+	}
+	defer ctx.Destroy()
+
+	if err := t.validate(ctx, s); err != nil {
+		return err
+	}
+
+	for i := range t.PublicKeys {
+		if err := t.verifyEncryptedShare(ctx, s, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Transcript) verifyEncryptedShare(ctx *big.IntContext, s *schnorr.Suite, index int) error {
+	x, err := abscissa(index)
+	if err != nil {
+		return err
+	}
+
+	evaluation, err := evalCommitments(ctx, s, t.Commitments, x)
+	if err != nil {
+		return err
+	}
+
+	proof := &schnorr.DLEQProof{
+		Challenge: t.Proofs[index].Challenge,
+		Response:  t.Proofs[index].Response,
+	}
+
+	if s.VerifyDLEQ(ctx, s.Group.H, evaluation, t.PublicKeys[index], t.EncryptedShares[index], proof) != nil {
+		return ErrInvalidEncryptedShare
+	}
+
+	return nil
+}
+
+// DecryptShare decrypts the encrypted share of the shareholder with the provided
+// index, and proves the correctness of the decryption.
+// The encrypted share is verified before being decrypted.
+func (t *Transcript) DecryptShare(group *pedersen.Group, index int, key *KeyPair) (*DecryptedShare, error) {
+	if key == nil {
+		return nil, ErrNilKeyPair
+	}
+
+	s, err := newSuite(group)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	if err := t.validate(ctx, s); err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(t.PublicKeys) {
+		return nil, ErrInvalidShareholder
+	}
+
+	if err := s.ValidateIdentifier(key.Private); err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	publicKey, err := s.BaseExp(ctx, key.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	if publicKey.Cmp(t.PublicKeys[index]) != 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	if err := t.verifyEncryptedShare(ctx, s, index); err != nil {
+		return nil, err
+	}
+
+	// S_i = Y_i^(1/x_i)
+	inverse, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+	inverse.SetConstantTime()
+
+	if err := s.Field.Inverse(ctx, inverse, key.Private); err != nil {
+		return nil, err
+	}
+
+	share, err := s.SecretExp(ctx, t.EncryptedShares[index], inverse)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := s.ProveDLEQ(ctx, s.Group.G, t.PublicKeys[index], share, t.EncryptedShares[index], key.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DecryptedShare{
+		Index: index,
+		Share: share,
+		Proof: Proof{
+			Challenge: proof.Challenge,
+			Response:  proof.Response,
+		},
+	}, nil
+}
+
+func (t *Transcript) verifyDecryptedShare(ctx *big.IntContext, s *schnorr.Suite, share *DecryptedShare) error {
+	if share == nil || share.Index < 0 || share.Index >= len(t.PublicKeys) {
+		return ErrInvalidDecryptedShare
+	}
+
+	if s.ValidateElement(ctx, share.Share) != nil {
+		return ErrInvalidDecryptedShare
+	}
+
+	proof := &schnorr.DLEQProof{
+		Challenge: share.Proof.Challenge,
+		Response:  share.Proof.Response,
+	}
+
+	if s.VerifyDLEQ(ctx,
+		s.Group.G, t.PublicKeys[share.Index],
+		share.Share, t.EncryptedShares[share.Index],
+		proof,
+	) != nil {
+		return ErrInvalidDecryptedShare
+	}
+
+	return nil
+}
+
+// VerifyDecryptedShare verifies that the decrypted share is the decryption of
+// the encrypted share of the shareholder.
+func (t *Transcript) VerifyDecryptedShare(group *pedersen.Group, share *DecryptedShare) error {
+	s, err := newSuite(group)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return err
+	}
+	defer ctx.Destroy()
+
+	if err := t.validate(ctx, s); err != nil {
+		return err
+	}
+
+	return t.verifyDecryptedShare(ctx, s, share)
+}
+
+// Reconstruct verifies the decrypted shares and combines them into the secret
+// element g^s, which decrypts the secret data.
+// [ErrInvalidDecryptedShare] is returned if any decrypted share is invalid.
+func (t *Transcript) Reconstruct(group *pedersen.Group, shares []*DecryptedShare) ([]byte, error) {
+	s, err := newSuite(group)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Destroy()
+
+	if err := t.validate(ctx, s); err != nil {
+		return nil, err
+	}
+
+	if len(shares) < t.Threshold {
+		return nil, ErrInsufficientShares
+	}
+
+	abscissae := make([]*big.Int, len(shares))
+	seen := make(map[int]struct{}, len(shares))
+
+	for i, share := range shares {
+		if err := t.verifyDecryptedShare(ctx, s, share); err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[share.Index]; ok {
+			return nil, ErrDuplicateShareholder
+		}
+		seen[share.Index] = struct{}{}
+
+		abscissae[i], err = abscissa(share.Index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	origin, err := schnorr.Zero()
+	if err != nil {
+		return nil, err
+	}
+
+	// g^s = prod(S_i^lambda_i)
+	secret := big.One()
+
+	for i, share := range shares {
+		lambda, err := s.LagrangeCoefficient(ctx, origin, abscissae[i], abscissae)
+		if err != nil {
+			return nil, err
+		}
+
+		term, err := s.Exp(ctx, share.Share, lambda)
+		if err != nil {
+			return nil, err
+		}
+
+		secret, err = s.Mul(ctx, secret, term)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	aead, ad, err := newAEAD(s, secret, t.Commitments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), t.Ciphertext, ad)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pvss_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/pvss"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getTestSchnorrGroup(t *testing.T) *pedersen.Group {
+	group := &pedersen.Group{}
+
+	for _, v := range []struct {
+		x   **big.Int
+		dec string
+	}{
+		{&group.P, "17634709279010524619"},
+		{&group.Q, "8817354639505262309"},
+		{&group.G, "8414335786771157015"},
+		{&group.H, "15078279289296123424"},
+	} {
+		x, err := big.NewInt()
+		require.NoError(t, err)
+		require.NoError(t, x.SetDecString(v.dec))
+		*v.x = x
+	}
+
+	return group
+}
+
+// dealTestSecret deals the secret among 5 shareholders with threshold 3.
+func dealTestSecret(t *testing.T, group *pedersen.Group, secret []byte) (*pvss.Transcript, []*pvss.KeyPair) {
+	keys := make([]*pvss.KeyPair, 5)
+	publicKeys := make([]*big.Int, len(keys))
+
+	for i := range keys {
+		var err error
+		keys[i], err = pvss.GenerateKey(group)
+		require.NoError(t, err)
+
+		publicKeys[i] = keys[i].Public
+	}
+
+	transcript, err := pvss.Deal(group, 3, publicKeys, secret)
+	require.NoError(t, err)
+
+	return transcript, keys
+}
+
+func decryptTestShares(t *testing.T, group *pedersen.Group, transcript *pvss.Transcript, keys []*pvss.KeyPair, indices ...int) []*pvss.DecryptedShare {
+	shares := make([]*pvss.DecryptedShare, len(indices))
+
+	for i, idx := range indices {
+		var err error
+		shares[i], err = transcript.DecryptShare(group, idx, keys[idx])
+		require.NoError(t, err)
+		require.NoError(t, transcript.VerifyDecryptedShare(group, shares[i]))
+	}
+
+	return shares
+}
+
+func TestPVSS(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+	secret := []byte("publicly verifiable secret")
+
+	transcript, keys := dealTestSecret(t, group, secret)
+
+	// anyone can verify the transcript
+	require.NoError(t, transcript.Verify(group))
+
+	for _, indices := range [][]int{{0, 1, 2}, {4, 2, 1}, {0, 1, 2, 3, 4}} {
+		shares := decryptTestShares(t, group, transcript, keys, indices...)
+
+		reconstructed, err := transcript.Reconstruct(group, shares)
+		require.NoError(t, err)
+		require.Equal(t, secret, reconstructed)
+	}
+}
+
+func TestPVSSInvalid(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+	secret := []byte("publicly verifiable secret")
+
+	transcript, keys := dealTestSecret(t, group, secret)
+	other, _ := dealTestSecret(t, group, secret)
+
+	// an encrypted share replaced by the dealer is detected by anyone
+	tampered := *transcript
+	tampered.EncryptedShares = append([]*big.Int(nil), transcript.EncryptedShares...)
+	tampered.EncryptedShares[1] = transcript.EncryptedShares[2]
+	require.ErrorIs(t, tampered.Verify(group), pvss.ErrInvalidEncryptedShare)
+
+	_, err := tampered.DecryptShare(group, 1, keys[1])
+	require.ErrorIs(t, err, pvss.ErrInvalidEncryptedShare)
+
+	tampered = *transcript
+	tampered.Commitments = other.Commitments
+	require.ErrorIs(t, tampered.Verify(group), pvss.ErrInvalidEncryptedShare)
+
+	tampered = *transcript
+	tampered.Commitments = transcript.Commitments[:2]
+	require.ErrorIs(t, tampered.Verify(group), pvss.ErrWrongCommitmentsLen)
+
+	_, err = transcript.DecryptShare(group, 1, keys[2])
+	require.ErrorIs(t, err, pvss.ErrInvalidPrivateKey)
+
+	_, err = transcript.DecryptShare(group, 5, keys[2])
+	require.ErrorIs(t, err, pvss.ErrInvalidShareholder)
+
+	shares := decryptTestShares(t, group, transcript, keys, 0, 1, 2)
+
+	_, err = transcript.Reconstruct(group, shares[:2])
+	require.ErrorIs(t, err, pvss.ErrInsufficientShares)
+
+	_, err = transcript.Reconstruct(group, []*pvss.DecryptedShare{shares[0], shares[1], shares[0]})
+	require.ErrorIs(t, err, pvss.ErrDuplicateShareholder)
+
+	// a decrypted share claimed by another shareholder
+	claimed := *shares[2]
+	claimed.Index = 3
+	require.ErrorIs(t, transcript.VerifyDecryptedShare(group, &claimed), pvss.ErrInvalidDecryptedShare)
+
+	// a wrong decrypted share cannot be hidden in the reconstruction
+	wrong := *shares[2]
+	wrong.Share = shares[1].Share
+	_, err = transcript.Reconstruct(group, []*pvss.DecryptedShare{shares[0], shares[1], &wrong})
+	require.ErrorIs(t, err, pvss.ErrInvalidDecryptedShare)
+
+	_, err = pvss.Deal(group, 3, []*big.Int{keys[0].Public, keys[1].Public, keys[0].Public}, secret)
+	require.ErrorIs(t, err, pvss.ErrDuplicatePublicKey)
+
+	_, err = pvss.Deal(group, 4, []*big.Int{keys[0].Public, keys[1].Public, keys[2].Public}, secret)
+	require.ErrorIs(t, err, pvss.ErrInvalidThreshold)
+
+	_, err = pvss.Deal(group, 2, []*big.Int{keys[0].Public, big.One()}, secret)
+	require.ErrorIs(t, err, pvss.ErrInvalidElement)
+
+	var nilTranscript *pvss.Transcript
+	require.ErrorIs(t, nilTranscript.Verify(group), pvss.ErrNilTranscript)
+}
@@ -0,0 +1,179 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+var (
+	ErrNilScalar     = errors.New("scalar cannot be nil")
+	ErrInvalidScalar = errors.New("scalar must be in the range [0, q)")
+)
+
+// ScalarShares represents the shares obtained from splitting a scalar, which is
+// an element of the field of integers modulo Q.
+// Unlike [Shares], the scalar is shared as it is, without being encoded into
+// chunks, so there is a single secret part for each shareholder and a single
+// commitments vector.
+type ScalarShares struct {
+	// Abscissae is the abscissae vector, as in [Shares].
+	Abscissae []*big.Int
+
+	// Parts is the vector of secret parts, so Parts[shareholderIdx] is the secret
+	// part of the shareholder with index shareholderIdx.
+	Parts []SecretPart
+
+	// Commitments is the vector of commitments of the scalar.
+	Commitments []*big.Int
+}
+
+// ScalarShare represents the secret part of a scalar associated to a single shareholder.
+type ScalarShare struct {
+	// Index is the index of the shareholder, which ranges from 0 to parts-1.
+	Index int
+
+	// Abscissa is the abscissa related to the shareholder.
+	Abscissa *big.Int
+
+	// Part is the secret part of the shareholder.
+	Part SecretPart
+}
+
+// Returns a string representation of a ScalarShares struct.
+func (s *ScalarShares) String() string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// Returns a string representation of a ScalarShare struct.
+func (s *ScalarShare) String() string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// Shareholder returns the share of the shareholder with index i.
+func (s *ScalarShares) Shareholder(i int) (ScalarShare, error) {
+	if i < 0 || i >= len(s.Parts) || i >= len(s.Abscissae) {
+		return ScalarShare{}, ErrInvalidShareholder
+	}
+
+	return ScalarShare{
+		Index:    i,
+		Abscissa: s.Abscissae[i],
+		Part:     s.Parts[i],
+	}, nil
+}
+
+// Subset returns the shares of the shareholders with the provided indices.
+func (s *ScalarShares) Subset(indices ...int) ([]ScalarShare, error) {
+	shares := make([]ScalarShare, len(indices))
+
+	for i, idx := range indices {
+		share, err := s.Shareholder(idx)
+		if err != nil {
+			return nil, err
+		}
+
+		shares[i] = share
+	}
+
+	return shares, nil
+}
+
+// share returns the scalar share as the share of a secret made of a single chunk.
+func (s ScalarShare) share() Share {
+	return Share{
+		Index:    s.Index,
+		Abscissa: s.Abscissa,
+		Parts:    []SecretPart{s.Part},
+	}
+}
+
+// SplitScalar takes a scalar in the range [0, q) and generates a `parts` number
+// of shares, `threshold` of which are required to reconstruct the scalar.
+// The abscissae are used to evaluate the polynomials at the given points.
+// If abscissae is nil, random abscissae are generated.
+func (p *Pedersen) SplitScalar(scalar *big.Int, abscissae []*big.Int) (*ScalarShares, error) {
+	if scalar == nil {
+		return nil, ErrNilScalar
+	}
+
+	if !p.field.Contains(scalar) {
+		return nil, ErrInvalidScalar
+	}
+
+	abscissae, err := p.splitAbscissae(abscissae)
+	if err != nil {
+		return nil, err
+	}
+
+	// the polynomial takes ownership of its intercept, so the scalar is copied
+	value, err := big.NewInt()
+	if err != nil {
+		return nil, err
+	}
+	value.SetConstantTime()
+
+	if err := value.Set(scalar); err != nil {
+		return nil, err
+	}
+
+	parts, commitments, err := p.splitChunks([]*big.Int{value}, abscissae)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := &ScalarShares{
+		Abscissae:   abscissae,
+		Parts:       make([]SecretPart, len(parts)),
+		Commitments: commitments[0],
+	}
+
+	for shareIdx := range parts {
+		shares.Parts[shareIdx] = parts[shareIdx][0]
+	}
+
+	return shares, nil
+}
+
+// VerifyScalar verifies if the secret part of the provided scalar share is valid,
+// according to the commitments vector of the scalar.
+func (p *Pedersen) VerifyScalar(share ScalarShare, commitments []*big.Int) error {
+	return p.Verify(share.Abscissa, share.Part, commitments)
+}
+
+// CombineScalar combines the provided shareholders shares into the original scalar.
+// Both the scalar and the blinding value are reconstructed, and if they do not
+// open the first commitment [ErrCombineMismatch] is returned.
+func (p *Pedersen) CombineScalar(shares []ScalarShare, commitments []*big.Int) (*big.Int, error) {
+	shareholders := make([]Share, len(shares))
+	for i, share := range shares {
+		shareholders[i] = share.share()
+	}
+
+	// a scalar is a single chunk, so there is no chunk position to bind
+	matrix := [][]*big.Int{commitments}
+
+	if err := p.validateShareholdersShape(shareholders, matrix); err != nil {
+		return nil, err
+	}
+
+	if err := p.validateValues(shareholders, matrix); err != nil {
+		return nil, err
+	}
+
+	values, err := p.combineChunks(shareholders, matrix)
+	if err != nil {
+		return nil, err
+	}
+
+	return values[0], nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+	"github.com/matteoarella/pedersen/big"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPedersenScalar(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+	defer p.Close()
+
+	zero, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, zero.SetUInt64(0))
+
+	random, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, random.RandRange(group.Q))
+
+	qMinus, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, qMinus.Sub(group.Q, big.One()))
+
+	for _, scalar := range []*big.Int{zero, big.One(), random, qMinus} {
+		original := scalar.Hex()
+
+		shares, err := p.SplitScalar(scalar, nil)
+		require.NoError(t, err)
+		require.Len(t, shares.Parts, 5)
+		require.Len(t, shares.Commitments, 3)
+
+		// the scalar is not modified
+		require.Equal(t, original, scalar.Hex())
+
+		for i := range shares.Parts {
+			share, err := shares.Shareholder(i)
+			require.NoError(t, err)
+			require.NoError(t, p.VerifyScalar(share, shares.Commitments))
+		}
+
+		subset, err := shares.Subset(4, 1, 3)
+		require.NoError(t, err)
+
+		combined, err := p.CombineScalar(subset, shares.Commitments)
+		require.NoError(t, err)
+		require.Equal(t, 0, combined.Cmp(scalar))
+	}
+}
+
+func TestPedersenScalarInvalid(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+	defer p.Close()
+
+	negative, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, negative.Sub(negative, big.One()))
+
+	_, err = p.SplitScalar(nil, nil)
+	require.ErrorIs(t, err, pedersen.ErrNilScalar)
+
+	_, err = p.SplitScalar(group.Q, nil)
+	require.ErrorIs(t, err, pedersen.ErrInvalidScalar)
+
+	_, err = p.SplitScalar(negative, nil)
+	require.ErrorIs(t, err, pedersen.ErrInvalidScalar)
+
+	_, err = p.SplitScalar(big.One(), []*big.Int{big.One()})
+	require.ErrorIs(t, err, pedersen.ErrInsufficientAbscissae)
+
+	shares, err := p.SplitScalar(big.One(), nil)
+	require.NoError(t, err)
+
+	otherShares, err := p.SplitScalar(big.One(), shares.Abscissae)
+	require.NoError(t, err)
+
+	subset, err := shares.Subset(0, 1, 2)
+	require.NoError(t, err)
+
+	_, err = p.CombineScalar(subset[:2], shares.Commitments)
+	require.ErrorIs(t, err, pedersen.ErrInsufficientSharesParts)
+
+	_, err = p.CombineScalar([]pedersen.ScalarShare{subset[0], subset[1], subset[0]}, shares.Commitments)
+	require.ErrorIs(t, err, pedersen.ErrDuplicateShareholder)
+
+	_, err = p.CombineScalar(subset, shares.Commitments[:2])
+	require.ErrorIs(t, err, pedersen.ErrInsufficientCommitments)
+
+	// the commitments of another split of the same scalar
+	_, err = p.CombineScalar(subset, otherShares.Commitments)
+	require.ErrorIs(t, err, pedersen.ErrCombineMismatch)
+
+	tampered := subset[0]
+	tampered.Part = pedersen.SecretPart{
+		SShare: subset[0].Part.TShare,
+		TShare: subset[0].Part.SShare,
+	}
+	require.ErrorIs(t, p.VerifyScalar(tampered, shares.Commitments), pedersen.ErrWrongSecretPart)
+
+	_, err = shares.Shareholder(5)
+	require.ErrorIs(t, err, pedersen.ErrInvalidShareholder)
+}
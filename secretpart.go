@@ -8,10 +8,15 @@ package pedersen
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/matteoarella/pedersen/big"
 )
 
+var (
+	ErrInvalidShareholder = errors.New("invalid shareholder index")
+)
+
 // SecretPart represents a secret part associated to a shareholder.
 type SecretPart struct {
 	SShare *big.Int
@@ -39,6 +44,49 @@ type Shares struct {
 	// The first index of Commitments represents the chunk index so Commitments[chunkIdx]
 	// is the vector of commitments related to the chunk with index chunkIdx.
 	Commitments [][]*big.Int
+
+	// SplitID is the unique identifier of the split.
+	SplitID []byte
+
+	// Bindings is the vector of hashes binding the commitments of every chunk
+	// to the split and to the chunk position (see [Transcript]).
+	Bindings [][]byte
+
+	// Root is the root of the Merkle tree whose leaves are the commitments of
+	// the chunks. It is set only when the shares are obtained with the
+	// [MerkleCommitments] option.
+	Root []byte
+
+	// Paths is the vector of authentication paths of the Merkle tree, so
+	// Paths[chunkIdx] authenticates Commitments[chunkIdx] against Root.
+	Paths [][][]byte
+
+	// Encoding is the encoding of the secret into chunks. The zero value is
+	// [CompactEncoding], while shares produced by previous versions must be
+	// given [LegacyEncoding].
+	Encoding ChunkEncoding
+}
+
+// Share represents the secret parts associated to a single shareholder.
+type Share struct {
+	// Index is the index of the shareholder, which ranges from 0 to parts-1.
+	Index int
+
+	// Abscissa is the abscissa related to the shareholder.
+	Abscissa *big.Int
+
+	// Parts is the vector of secret parts of the shareholder, so Parts[chunkIdx]
+	// is the secret part related to the chunk with index chunkIdx.
+	Parts []SecretPart
+
+	// SplitID is the unique identifier of the split the share is obtained from.
+	SplitID []byte
+
+	// Proofs is the vector of the commitments of every chunk, each one with its
+	// authentication path, so Proofs[chunkIdx] authenticates the commitments
+	// of the chunk with index chunkIdx against the Merkle root of the split.
+	// It is set only for shares obtained with the [MerkleCommitments] option.
+	Proofs []CommitmentProof
 }
 
 // Returns a string representation of a SecretPart struct.
@@ -52,3 +100,58 @@ func (s *Shares) String() string {
 	data, _ := json.Marshal(s)
 	return string(data)
 }
+
+// Returns a string representation of a Share struct.
+func (s *Share) String() string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// Shareholder returns the share of the shareholder with index i.
+func (s *Shares) Shareholder(i int) (Share, error) {
+	if i < 0 || i >= len(s.Parts) || i >= len(s.Abscissae) {
+		return Share{}, ErrInvalidShareholder
+	}
+
+	return Share{
+		Index:    i,
+		Abscissa: s.Abscissae[i],
+		Parts:    s.Parts[i],
+		SplitID:  s.SplitID,
+		Proofs:   s.proofs(),
+	}, nil
+}
+
+// Subset returns the shares of the shareholders with the provided indices.
+func (s *Shares) Subset(indices ...int) ([]Share, error) {
+	shares := make([]Share, len(indices))
+
+	for i, idx := range indices {
+		share, err := s.Shareholder(idx)
+		if err != nil {
+			return nil, err
+		}
+
+		shares[i] = share
+	}
+
+	return shares, nil
+}
+
+// shareholders returns the share of every shareholder.
+func (s *Shares) shareholders() []Share {
+	shares := make([]Share, len(s.Parts))
+	proofs := s.proofs()
+
+	for i := range s.Parts {
+		shares[i] = Share{
+			Index:    i,
+			Abscissa: s.Abscissae[i],
+			Parts:    s.Parts[i],
+			SplitID:  s.SplitID,
+			Proofs:   proofs,
+		}
+	}
+
+	return shares
+}
@@ -7,11 +7,11 @@
 package pedersen
 
 import (
+	"crypto/subtle"
 	"errors"
 	"math"
 
 	"github.com/matteoarella/pedersen/big"
-	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -23,21 +23,22 @@ const (
 	zerosInfoSizeBytes = 4
 )
 
-type splitValue struct {
-	index       int
-	secretParts []SecretPart
-	secretComm  []*big.Int
+// chunkPolynomials holds the polynomials used for splitting a chunk:
+// F shares the chunk value and K the blinding value.
+type chunkPolynomials struct {
+	F polynomial
+	K polynomial
 }
 
+// leadingZeros counts the leading zero bytes of buff in constant time,
+// looking at every byte regardless of where the first nonzero one is.
 func leadingZeros(buff []byte) uint64 {
 	sum := uint64(0)
+	leading := 1
 
 	for _, n := range buff {
-		if n == 0 {
-			sum++
-		} else {
-			break
-		}
+		leading &= subtle.ConstantTimeByteEq(n, 0)
+		sum += uint64(leading)
 	}
 
 	return sum
@@ -58,30 +59,36 @@ func bigIntPadding(ctx *big.IntContext, buff []byte) (*big.Int, error) {
 	}
 
 	n := new(big.Int).SetBytes(buff)
+	n.SetConstantTime()
 
 	// append leading zeros info to n
 	if err := n.Lsh(n, zerosInfoSizeBytes*8); err != nil {
 		return nil, err
 	}
 
-	if zerosCount > 0 {
-		if err := n.Or(n, zeros); err != nil {
-			return nil, err
-		}
+	if err := n.Or(n, zeros); err != nil {
+		return nil, err
 	}
 
 	return n, nil
 }
 
-func splitSecret(ctx *big.IntContext, value []byte, max *big.Int) ([]*big.Int, error) {
-	valueLen := len(value)
-
+// legacyChunkSize returns the number of bytes of the secret in every chunk of
+// the legacy encoding.
+func legacyChunkSize(max *big.Int) int {
 	// ensure every chunk value is smaller than max
 	partLen := int(math.Floor(float64(max.BitLen())/float64(8))) - zerosInfoSizeBytes
 	if partLen <= 0 {
 		partLen = 1
 	}
 
+	return partLen
+}
+
+func splitSecret(ctx *big.IntContext, value []byte, max *big.Int) ([]*big.Int, error) {
+	valueLen := len(value)
+	partLen := legacyChunkSize(max)
+
 	partCount := valueLen / partLen
 	if partCount*partLen < valueLen {
 		partCount++
@@ -106,105 +113,103 @@ func splitSecret(ctx *big.IntContext, value []byte, max *big.Int) ([]*big.Int, e
 	return splitted, nil
 }
 
-type chunkRange struct {
-	start, end int
+// Split takes a secret and generates a `parts`
+// number of shares, `threshold` of which are required to reconstruct
+// the secret.
+// If the secret that has to be split is not representable in the cyclic group,
+// the secret is split into chunks, and each chunk is split into secret parts according
+// to Pedersen verifiable secret sharing. The secret is encoded into chunks according
+// to the [Encoding] option, which is recorded in [Shares.Encoding].
+// The abscissae are used to evaluate the polynomials at the given points.
+// If abscissae is nil, random abscissae are generated.
+func (p *Pedersen) Split(secret []byte, abscissae []*big.Int) (*Shares, error) {
+	if len(secret) == 0 {
+		return nil, ErrEmptySecret
+	}
+
+	splitID, err := newSplitID()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.split(secret, abscissae, splitID)
 }
 
-func (p *Pedersen) split(
-	mont *big.MontgomeryContext,
-	ctx *big.IntContext,
-	index int,
-	secret *big.Int,
-	abscissae []*big.Int,
-) (splitValue, error) {
-	ctx.Attach()
-	defer ctx.Detach()
+// split splits the secret as in Split, binding the commitments to the provided
+// split identifier.
+func (p *Pedersen) split(secret []byte, abscissae []*big.Int, splitID []byte) (*Shares, error) {
 
-	F, err := newPolynomial(secret, p.threshold-1, p.group.Q)
+	abscissae, err := p.splitAbscissae(abscissae)
 	if err != nil {
-		return splitValue{}, err
+		return nil, err
 	}
 
-	K, err := newPolynomial(nil, p.threshold-1, p.group.Q)
+	local, err := p.pool.get()
 	if err != nil {
-		return splitValue{}, err
+		return nil, err
 	}
+	defer p.pool.put(local)
 
-	secretParts := make([]SecretPart, p.parts)
-	for i := 0; i < p.parts; i++ {
-		s, err := F.evaluate(ctx, abscissae[i])
-		if err != nil {
-			return splitValue{}, err
-		}
-
-		t, err := K.evaluate(ctx, abscissae[i])
-		if err != nil {
-			return splitValue{}, err
-		}
+	// split secret into many byte slices and process them
+	splitted, err := encodeSecret(local.ctx, p.encoding, secret, p.group.Q)
+	if err != nil {
+		return nil, err
+	}
 
-		secretParts[i] = SecretPart{
-			SShare: s,
-			TShare: t,
-		}
+	parts, commitments, err := p.splitChunks(splitted, abscissae)
+	if err != nil {
+		return nil, err
 	}
 
-	commitments := make([]*big.Int, p.threshold)
+	bindings, err := chunkBindings(splitID, p.encoding, commitments)
+	if err != nil {
+		return nil, err
+	}
 
-	for i := 0; i < p.threshold; i++ {
-		commitment, err := p.commit(mont, ctx, F.coefficients[i], K.coefficients[i])
-		if err != nil {
-			return splitValue{}, err
-		}
+	shares := &Shares{
+		Abscissae:   abscissae,
+		Parts:       parts,
+		Commitments: commitments,
+		SplitID:     splitID,
+		Bindings:    bindings,
+		Encoding:    p.encoding,
+	}
 
-		commitments[i] = commitment
+	if p.merkle {
+		shares.Root, shares.Paths = merkleTree(bindings)
 	}
 
-	return splitValue{
-		index:       index,
-		secretParts: secretParts,
-		secretComm:  commitments,
-	}, nil
+	return shares, nil
 }
 
-// Split takes a secret and generates a `parts`
-// number of shares, `threshold` of which are required to reconstruct
-// the secret.
-// If the secret that has to be split is not representable in the cyclic group,
-// the secret is split into chunks, and each chunk is split into secret parts according
-// to Pedersen verifiable secret sharing.
-// The abscissae are used to evaluate the polynomials at the given points.
-// If abscissae is nil, random abscissae are generated.
-func (p *Pedersen) Split(secret []byte, abscissae []*big.Int) (*Shares, error) {
-	if len(secret) == 0 {
-		return nil, ErrEmptySecret
-	}
-
+// splitAbscissae returns the abscissae used for splitting, which are generated
+// randomly in the range [1, q) if none is provided.
+// Both the provided and the generated abscissae are validated.
+func (p *Pedersen) splitAbscissae(abscissae []*big.Int) ([]*big.Int, error) {
 	if abscissae == nil {
 		abscissae = make([]*big.Int, p.parts)
 
-		err := randInts(abscissae, big.One(), p.group.Q, true)
-		if err != nil {
+		if err := randInts(abscissae, big.One(), p.group.Q, true); err != nil {
 			return nil, err
 		}
-	} else if len(abscissae) < p.parts {
-		return nil, ErrInsufficientAbscissae
 	}
 
-	ctx, err := big.NewIntContext()
-	if err != nil {
-		return nil, err
+	if len(abscissae) < p.parts {
+		return nil, ErrInsufficientAbscissae
 	}
-	defer ctx.Destroy()
 
-	// split secret into many byte slices and process them
-	splitted, err := splitSecret(ctx, secret, p.group.Q)
-	if err != nil {
+	if err := p.validateAbscissae(abscissae[:p.parts]); err != nil {
 		return nil, err
 	}
 
+	return abscissae, nil
+}
+
+// splitChunks splits every chunk into secret parts, one for each abscissa, and
+// computes the commitments of the coefficients of its polynomials.
+// Every chunk must be in the range [0, q).
+func (p *Pedersen) splitChunks(splitted []*big.Int, abscissae []*big.Int) ([][]SecretPart, [][]*big.Int, error) {
 	splittedLen := len(splitted)
-	concLimit := p.adjustConcLimit(splittedLen)
-	chunksIndex := p.balanceIndices(splittedLen, concLimit)
 	parts := make([][]SecretPart, p.parts)
 	commitments := make([][]*big.Int, splittedLen)
 
@@ -212,61 +217,77 @@ func (p *Pedersen) Split(secret []byte, abscissae []*big.Int) (*Shares, error) {
 		parts[shareIdx] = make([]SecretPart, splittedLen)
 	}
 
-	group := errgroup.Group{}
-	group.SetLimit(concLimit)
+	polynomials := make([]chunkPolynomials, splittedLen)
 
-	for _, chunk := range chunksIndex {
-		secrets := splitted[chunk.start:chunk.end]
-		chunk := chunk
+	err := p.runTasks(splittedLen, func(w *workerContext, chunkIdx int) error {
+		if splitted[chunkIdx].Cmp(p.group.Q) >= 0 {
+			return ErrInvalidPrimeSize
+		}
 
-		group.Go(func() error {
-			/* create new IntContext for each goroutine since it's not safe to
-			use the same context concurrently */
-			ctx, err := big.NewIntContext()
-			if err != nil {
-				return err
-			}
-			defer ctx.Destroy()
+		F, err := newPolynomial(splitted[chunkIdx], p.threshold-1, p.field)
+		if err != nil {
+			return err
+		}
 
-			mont, err := big.NewMontgomeryContext()
+		K, err := newPolynomial(nil, p.threshold-1, p.field)
+		if err != nil {
+			return err
+		}
+
+		polynomials[chunkIdx] = chunkPolynomials{F: F, K: K}
+		commitments[chunkIdx] = make([]*big.Int, p.threshold)
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// every chunk is split in threshold commitment tasks followed by
+	// parts evaluation tasks, one for each shareholder
+	chunkTasks := p.threshold + p.parts
+
+	err = p.runTasks(splittedLen*chunkTasks, func(w *workerContext, taskIdx int) error {
+		chunkIdx := taskIdx / chunkTasks
+		idx := taskIdx % chunkTasks
+		poly := polynomials[chunkIdx]
+
+		w.ctx.Attach()
+		defer w.ctx.Detach()
+
+		if idx < p.threshold {
+			commitment, err := p.commit(w.mont, w.ctx, poly.F.coefficients[idx], poly.K.coefficients[idx])
 			if err != nil {
 				return err
 			}
-			defer mont.Destroy()
 
-			if err := mont.Set(p.group.P, ctx); err != nil {
-				return err
-			}
+			commitments[chunkIdx][idx] = commitment
 
-			for idx, secret := range secrets {
-				if secret.Cmp(p.group.Q) > 0 {
-					return ErrInvalidPrimeSize
-				}
+			return nil
+		}
 
-				result, err := p.split(mont, ctx, chunk.start+idx, secret, abscissae)
-				if err != nil {
-					return err
-				}
+		shareIdx := idx - p.threshold
 
-				for shareIdx := 0; shareIdx < p.parts; shareIdx++ {
-					parts[shareIdx][result.index] = result.secretParts[shareIdx]
-				}
+		s, err := poly.F.evaluate(w.ctx, abscissae[shareIdx])
+		if err != nil {
+			return err
+		}
 
-				commitments[result.index] = result.secretComm
-			}
+		t, err := poly.K.evaluate(w.ctx, abscissae[shareIdx])
+		if err != nil {
+			return err
+		}
 
-			return nil
-		})
-	}
+		parts[shareIdx][chunkIdx] = SecretPart{
+			SShare: s,
+			TShare: t,
+		}
 
-	err = group.Wait()
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &Shares{
-		Abscissae:   abscissae,
-		Parts:       parts,
-		Commitments: commitments,
-	}, nil
+	return parts, commitments, nil
 }
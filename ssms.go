@@ -0,0 +1,309 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+	"github.com/matteoarella/pedersen/internal/ida"
+)
+
+const (
+	shortKeySize = 32
+
+	dispersalDomain = "pedersen-dispersal-v1"
+)
+
+var (
+	ErrTooManyParts          = errors.New("parts cannot be more than 255 for short shares")
+	ErrNilFragment           = errors.New("fragments, digests and nonce cannot be nil")
+	ErrWrongFragmentsLen     = errors.New("fragments length and digests length must be equal to parts")
+	ErrInsufficientFragments = errors.New("insufficient valid fragments")
+	ErrDispersalMismatch     = errors.New("dispersal does not belong to the split")
+)
+
+// ShortShares represents the shares obtained from splitting a secret according
+// to Krawczyk's "Secret Sharing Made Short".
+// The secret is encrypted with a random key, the ciphertext is dispersed into
+// parts fragments, of which any threshold are sufficient to recover it, and the
+// key is split with Pedersen verifiable secret sharing.
+// Each shareholder receives its key shares and one fragment, so the total storage
+// is about parts/threshold times the secret size.
+type ShortShares struct {
+	// KeyShares are the Pedersen shares of the encryption key.
+	KeyShares *Shares
+
+	Dispersal
+}
+
+// Dispersal represents the ciphertext of a secret dispersed among the shareholders.
+type Dispersal struct {
+	// Fragments is the vector of ciphertext fragments.
+	// Fragments[shareholderIdx] is the fragment related to the shareholder
+	// with index shareholderIdx.
+	Fragments [][]byte
+
+	// Digests is the vector of SHA-256 digests of the fragments, which is used
+	// for checking the integrity of each fragment before reconstruction.
+	// Digests[shareholderIdx] is the digest of Fragments[shareholderIdx].
+	Digests [][]byte
+
+	// Nonce is the nonce used for encrypting the secret.
+	Nonce []byte
+
+	// Length is the length of the ciphertext.
+	Length int
+}
+
+// SplitID returns the split identifier the dispersal is bound to, which is the
+// split identifier of the key shares obtained with [Pedersen.SplitShort].
+// Since the split identifier is bound to the commitments of the key shares, the
+// digests, the nonce and the length of the dispersal cannot be replaced together
+// with the fragments.
+func (d *Dispersal) SplitID() []byte {
+	h := sha256.New()
+	buf := make([]byte, 8)
+
+	writeField := func(data []byte) {
+		binary.BigEndian.PutUint64(buf, uint64(len(data)))
+		h.Write(buf)
+		h.Write(data)
+	}
+
+	writeField([]byte(dispersalDomain))
+	writeField(d.Nonce)
+
+	binary.BigEndian.PutUint64(buf, uint64(d.Length))
+	h.Write(buf)
+
+	binary.BigEndian.PutUint64(buf, uint64(len(d.Digests)))
+	h.Write(buf)
+
+	for _, digest := range d.Digests {
+		writeField(digest)
+	}
+
+	return h.Sum(nil)[:splitIDSizeBytes]
+}
+
+// FragmentDigest returns the digest used for checking the integrity of a fragment.
+func FragmentDigest(fragment []byte) []byte {
+	digest := sha256.Sum256(fragment)
+	return digest[:]
+}
+
+// SplitShort takes a secret and generates a `parts` number of short shares,
+// `threshold` of which are required to reconstruct the secret.
+// The abscissae are used to split the encryption key as in [Pedersen.Split].
+func (p *Pedersen) SplitShort(secret []byte, abscissae []*big.Int) (*ShortShares, error) {
+	if len(secret) == 0 {
+		return nil, ErrEmptySecret
+	}
+
+	if p.parts > ida.MaxFragments {
+		return nil, ErrTooManyParts
+	}
+
+	key := make([]byte, shortKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	aead, err := newShortAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	ciphertext := aead.Seal(nil, nonce, secret, nil)
+
+	fragments, err := ida.Encode(ciphertext, p.parts, p.threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	digests := make([][]byte, p.parts)
+	for i, fragment := range fragments {
+		digests[i] = FragmentDigest(fragment)
+	}
+
+	dispersal := Dispersal{
+		Fragments: fragments,
+		Digests:   digests,
+		Nonce:     nonce,
+		Length:    len(ciphertext),
+	}
+
+	keyShares, err := p.split(key, abscissae, dispersal.SplitID())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShortShares{
+		KeyShares: keyShares,
+		Dispersal: dispersal,
+	}, nil
+}
+
+func (p *Pedersen) validateDispersal(s *Dispersal) error {
+	if s == nil {
+		return ErrNilFragment
+	}
+
+	if s.Nonce == nil || s.Digests == nil || s.Fragments == nil {
+		return ErrNilFragment
+	}
+
+	if len(s.Fragments) != p.parts || len(s.Digests) != p.parts {
+		return ErrWrongFragmentsLen
+	}
+
+	return nil
+}
+
+// VerifyDispersal verifies that the dispersal belongs to the split of the
+// transcript, so that its digests can be trusted for checking the fragments.
+// Fragments that are missing must be nil.
+func (p *Pedersen) VerifyDispersal(dispersal *Dispersal, transcript *Transcript) error {
+	if transcript == nil {
+		return ErrNilTranscript
+	}
+
+	if err := p.validateDispersal(dispersal); err != nil {
+		return err
+	}
+
+	return bindDispersal(dispersal, transcript)
+}
+
+// bindDispersal checks that the dispersal is bound to the split identifier of the
+// transcript. Legacy transcripts carry no split identifier the dispersal can be
+// bound to.
+func bindDispersal(d *Dispersal, t *Transcript) error {
+	if t.isLegacy() {
+		return nil
+	}
+
+	if subtle.ConstantTimeCompare(d.SplitID(), t.SplitID) != 1 {
+		return ErrDispersalMismatch
+	}
+
+	return nil
+}
+
+// CombineShort combines the short shares into the original secret.
+// The dispersal must be bound to the split of the key shares.
+// Fragments that are missing (nil) or whose digest does not match are discarded.
+func (p *Pedersen) CombineShort(s *ShortShares) ([]byte, error) {
+	if s == nil {
+		return nil, ErrNilShares
+	}
+
+	if s.KeyShares == nil {
+		return nil, ErrNilShares
+	}
+
+	if err := p.validateDispersal(&s.Dispersal); err != nil {
+		return nil, err
+	}
+
+	if err := bindDispersal(&s.Dispersal, s.KeyShares.Transcript()); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := p.gatherDispersal(&s.Dispersal)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := p.Combine(s.KeyShares)
+	if err != nil {
+		return nil, err
+	}
+
+	return openShort(key, s.Nonce, ciphertext)
+}
+
+// CombineShortShareholders combines the provided shareholders key shares and the
+// dispersed ciphertext into the original secret.
+// Unlike [Pedersen.CombineShort], only the key shares that have been collected need
+// to be provided, in any order.
+func (p *Pedersen) CombineShortShareholders(
+	shares []Share,
+	transcript *Transcript,
+	dispersal *Dispersal,
+) ([]byte, error) {
+	if err := p.VerifyDispersal(dispersal, transcript); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := p.gatherDispersal(dispersal)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := p.CombineShareholders(shares, transcript)
+	if err != nil {
+		return nil, err
+	}
+
+	return openShort(key, dispersal.Nonce, ciphertext)
+}
+
+// gatherDispersal reconstructs the ciphertext from the valid fragments.
+func (p *Pedersen) gatherDispersal(s *Dispersal) ([]byte, error) {
+	fragments := make([][]byte, p.parts)
+	valid := 0
+
+	for i, fragment := range s.Fragments {
+		if fragment == nil || s.Digests[i] == nil {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare(FragmentDigest(fragment), s.Digests[i]) != 1 {
+			continue
+		}
+
+		fragments[i] = fragment
+		valid++
+	}
+
+	if valid < p.threshold {
+		return nil, ErrInsufficientFragments
+	}
+
+	return ida.Decode(fragments, p.threshold, s.Length)
+}
+
+func openShort(key, nonce, ciphertext []byte) ([]byte, error) {
+	aead, err := newShortAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newShortAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
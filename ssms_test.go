@@ -0,0 +1,121 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPedersenShortValid(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	randomSecret := make([]byte, 4096)
+	_, err := rand.Read(randomSecret)
+	require.NoError(t, err)
+
+	p, err := pedersen.NewPedersen(7, 4, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+
+	shares, err := p.SplitShort(randomSecret, nil)
+	require.NoError(t, err)
+	require.Len(t, shares.Fragments, 7)
+	require.Len(t, shares.Digests, 7)
+
+	// each fragment is about 1/threshold of the secret
+	for _, fragment := range shares.Fragments {
+		assert.Less(t, len(fragment), len(randomSecret)/3)
+	}
+
+	err = p.VerifyShares(shares.KeyShares)
+	require.NoError(t, err)
+
+	secret, err := p.CombineShort(shares)
+	require.NoError(t, err)
+	assert.Equal(t, randomSecret, secret)
+
+	// drop some fragments and corrupt another one
+	shares.Fragments[0] = nil
+	shares.Fragments[3] = nil
+	shares.Fragments[5] = append([]byte{}, shares.Fragments[5]...)
+	shares.Fragments[5][0] ^= 0xff
+
+	secret, err = p.CombineShort(shares)
+	require.NoError(t, err)
+	assert.Equal(t, randomSecret, secret)
+}
+
+func TestPedersenShortInvalid(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+
+	_, err = p.SplitShort(nil, nil)
+	require.ErrorIs(t, err, pedersen.ErrEmptySecret)
+
+	_, err = p.CombineShort(nil)
+	require.ErrorIs(t, err, pedersen.ErrNilShares)
+
+	shares, err := p.SplitShort([]byte("secret"), nil)
+	require.NoError(t, err)
+
+	shares.Fragments = shares.Fragments[1:]
+	_, err = p.CombineShort(shares)
+	require.ErrorIs(t, err, pedersen.ErrWrongFragmentsLen)
+
+	shares, err = p.SplitShort([]byte("secret"), nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		shares.Fragments[i] = nil
+	}
+
+	_, err = p.CombineShort(shares)
+	require.ErrorIs(t, err, pedersen.ErrInsufficientFragments)
+}
+
+func TestPedersenShortDispersalBinding(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group))
+	require.NoError(t, err)
+
+	shares, err := p.SplitShort([]byte("secret of a short split"), nil)
+	require.NoError(t, err)
+	require.Equal(t, shares.SplitID(), shares.KeyShares.SplitID)
+	require.NoError(t, p.VerifyDispersal(&shares.Dispersal, shares.KeyShares.Transcript()))
+
+	// a fragment replaced together with its digest
+	shares.Fragments[0] = append([]byte{}, shares.Fragments[0]...)
+	shares.Fragments[0][0] ^= 0xff
+	shares.Digests[0] = pedersen.FragmentDigest(shares.Fragments[0])
+
+	require.ErrorIs(t,
+		p.VerifyDispersal(&shares.Dispersal, shares.KeyShares.Transcript()),
+		pedersen.ErrDispersalMismatch,
+	)
+
+	_, err = p.CombineShort(shares)
+	require.ErrorIs(t, err, pedersen.ErrDispersalMismatch)
+
+	// the dispersal of another split
+	other, err := p.SplitShort([]byte("secret of a short split"), nil)
+	require.NoError(t, err)
+
+	_, err = p.CombineShortShareholders(
+		[]pedersen.Share{},
+		shares.KeyShares.Transcript(),
+		&other.Dispersal,
+	)
+	require.ErrorIs(t, err, pedersen.ErrDispersalMismatch)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+var (
+	ErrInvalidAbscissa   = errors.New("abscissa must be in the range [1, q)")
+	ErrDuplicateAbscissa = errors.New("abscissae must be distinct")
+	ErrInvalidShare      = errors.New("s_share and t_share must be in the range [0, q)")
+	ErrInvalidCommitment = errors.New("commitment must be an element of the subgroup of order q")
+)
+
+// validateAbscissae checks that every abscissa is in the range [1, q) and that
+// the abscissae are distinct.
+// An abscissa equal to zero would give the secret itself to the shareholder, and
+// duplicate abscissae make the interpolation impossible.
+func (p *Pedersen) validateAbscissae(abscissae []*big.Int) error {
+	seen := make(map[string]struct{}, len(abscissae))
+
+	for _, abscissa := range abscissae {
+		if err := p.validateAbscissa(abscissa); err != nil {
+			return err
+		}
+
+		key := abscissa.Hex()
+		if _, ok := seen[key]; ok {
+			return ErrDuplicateAbscissa
+		}
+		seen[key] = struct{}{}
+	}
+
+	return nil
+}
+
+func (p *Pedersen) validateAbscissa(abscissa *big.Int) error {
+	if abscissa == nil {
+		return ErrNilAbscissa
+	}
+
+	if abscissa.Cmp(big.One()) < 0 || abscissa.Cmp(p.group.Q) >= 0 {
+		return ErrInvalidAbscissa
+	}
+
+	return nil
+}
+
+// validatePart checks that both the values of the secret part are in the range [0, q).
+func (p *Pedersen) validatePart(part SecretPart) error {
+	if part.SShare == nil || part.TShare == nil {
+		return ErrNilShare
+	}
+
+	if !p.field.Contains(part.SShare) || !p.field.Contains(part.TShare) {
+		return ErrInvalidShare
+	}
+
+	return nil
+}
+
+// validateCommitment checks that the commitment is an element of the subgroup
+// of order q of ℤ*p.
+// When p=2q+1 the subgroup is made of the quadratic residues modulo p, so the
+// Legendre symbol is checked instead of computing c^q mod p.
+func (p *Pedersen) validateCommitment(w *workerContext, commitment *big.Int) error {
+	if commitment == nil {
+		return ErrNilCommitment
+	}
+
+	if commitment.Cmp(big.One()) < 0 || commitment.Cmp(p.group.P) >= 0 {
+		return ErrInvalidCommitment
+	}
+
+	w.ctx.Attach()
+	defer w.ctx.Detach()
+
+	if p.safePrime {
+		symbol, err := big.Kronecker(w.ctx, commitment, p.group.P)
+		if err != nil {
+			return err
+		}
+
+		if symbol != 1 {
+			return ErrInvalidCommitment
+		}
+
+		return nil
+	}
+
+	order, err := w.ctx.GetInt()
+	if err != nil {
+		return err
+	}
+
+	if err := order.ModExpMont(w.mont, w.ctx, commitment, p.group.Q, p.group.P); err != nil {
+		return err
+	}
+
+	if order.Cmp(big.One()) != 0 {
+		return ErrInvalidCommitment
+	}
+
+	return nil
+}
+
+// validateCommitments checks every commitment of the commitments matrix.
+func (p *Pedersen) validateCommitments(commitments [][]*big.Int) error {
+	return p.runTasks(len(commitments), func(w *workerContext, chunkIdx int) error {
+		for _, commitment := range commitments[chunkIdx] {
+			if err := p.validateCommitment(w, commitment); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// validateValues checks the values of shares whose shape has already been validated:
+// the abscissae must be valid and distinct, the secret parts must be reduced modulo q
+// and the commitments must be elements of the subgroup of order q.
+func (p *Pedersen) validateValues(shares []Share, commitments [][]*big.Int) error {
+	abscissae := make([]*big.Int, len(shares))
+
+	for i, share := range shares {
+		abscissae[i] = share.Abscissa
+
+		for _, part := range share.Parts {
+			if (SecretPart{}) == part {
+				continue
+			}
+
+			if err := p.validatePart(part); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := p.validateAbscissae(abscissae); err != nil {
+		return err
+	}
+
+	return p.validateCommitments(commitments)
+}
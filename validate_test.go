@@ -0,0 +1,215 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen/big"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValue(t *testing.T, f func(z *big.Int) error) *big.Int {
+	t.Helper()
+
+	z, err := big.NewInt()
+	require.NoError(t, err)
+	require.NoError(t, f(z))
+
+	return z
+}
+
+func TestValidateAbscissae(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(3, 2, CyclicGroup(group))
+	require.NoError(t, err)
+	defer p.Close()
+
+	zero := newTestValue(t, func(z *big.Int) error { return z.SetUInt64(0) })
+	two := newTestValue(t, func(z *big.Int) error { return z.SetUInt64(2) })
+	negative := newTestValue(t, func(z *big.Int) error { return z.Sub(zero, two) })
+	qMinus := newTestValue(t, func(z *big.Int) error { return z.Sub(group.Q, big.One()) })
+
+	for _, scenario := range []struct {
+		description string
+		abscissae   []*big.Int
+		err         error
+	}{
+		{description: "valid", abscissae: []*big.Int{big.One(), two, qMinus}},
+		{description: "nil", abscissae: []*big.Int{big.One(), nil, two}, err: ErrNilAbscissa},
+		{description: "zero", abscissae: []*big.Int{big.One(), zero, two}, err: ErrInvalidAbscissa},
+		{description: "negative", abscissae: []*big.Int{big.One(), negative, two}, err: ErrInvalidAbscissa},
+		{description: "order", abscissae: []*big.Int{big.One(), group.Q, two}, err: ErrInvalidAbscissa},
+		{description: "duplicate", abscissae: []*big.Int{two, big.One(), two}, err: ErrDuplicateAbscissa},
+	} {
+		t.Run(scenario.description, func(t *testing.T) {
+			_, err := p.Split([]byte("secret"), scenario.abscissae)
+			if scenario.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, scenario.err)
+			}
+		})
+	}
+}
+
+func TestGeneratedAbscissae(t *testing.T) {
+	// the generated numbers never reach the upper bound
+	three := newTestValue(t, func(z *big.Int) error { return z.SetUInt64(3) })
+
+	for i := 0; i < 256; i++ {
+		n, err := genRandNum(big.One(), three)
+		require.NoError(t, err)
+		require.True(t, n.Cmp(big.One()) >= 0 && n.Cmp(three) < 0, n.String())
+	}
+
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(3, 2, CyclicGroup(group))
+	require.NoError(t, err)
+	defer p.Close()
+
+	abscissae, err := p.splitAbscissae(nil)
+	require.NoError(t, err)
+	require.NoError(t, p.validateAbscissae(abscissae))
+
+	// a chunk equal to the order is rejected
+	_, _, err = p.splitChunks([]*big.Int{group.Q}, abscissae)
+	require.ErrorIs(t, err, ErrInvalidPrimeSize)
+}
+
+func TestValidateValues(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	zero := newTestValue(t, func(z *big.Int) error { return z.SetUInt64(0) })
+	negative := newTestValue(t, func(z *big.Int) error { return z.Sub(zero, big.One()) })
+	pMinus := newTestValue(t, func(z *big.Int) error { return z.Sub(group.P, big.One()) })
+
+	// g*(p-1) = -g is not in the subgroup of order q, since -1 is not
+	commitmentOutside := newTestValue(t, func(z *big.Int) error {
+		ctx, err := big.NewIntContext()
+		if err != nil {
+			return err
+		}
+		defer ctx.Destroy()
+
+		return z.ModMul(ctx, group.G, pMinus, group.P)
+	})
+
+	for _, safePrime := range []bool{true, false} {
+		p, err := NewPedersen(5, 3, CyclicGroup(group))
+		require.NoError(t, err)
+		require.True(t, p.safePrime)
+
+		// without the safe prime shortcut the subgroup is checked by exponentiation
+		p.safePrime = safePrime
+
+		for _, scenario := range []struct {
+			description string
+			tamper      func(shares *Shares)
+			err         error
+		}{
+			{
+				description: "valid",
+				tamper:      func(shares *Shares) {},
+			},
+			{
+				description: "s share equal to order",
+				tamper:      func(shares *Shares) { shares.Parts[1][0].SShare = group.Q },
+				err:         ErrInvalidShare,
+			},
+			{
+				description: "negative t share",
+				tamper:      func(shares *Shares) { shares.Parts[2][0].TShare = negative },
+				err:         ErrInvalidShare,
+			},
+			{
+				description: "zero abscissa",
+				tamper:      func(shares *Shares) { shares.Abscissae[3] = zero },
+				err:         ErrInvalidAbscissa,
+			},
+			{
+				description: "duplicate abscissae",
+				tamper:      func(shares *Shares) { shares.Abscissae[3] = shares.Abscissae[4] },
+				err:         ErrDuplicateAbscissa,
+			},
+			{
+				description: "zero commitment",
+				tamper:      func(shares *Shares) { shares.Commitments[0][1] = zero },
+				err:         ErrInvalidCommitment,
+			},
+			{
+				description: "commitment equal to modulus",
+				tamper:      func(shares *Shares) { shares.Commitments[0][1] = group.P },
+				err:         ErrInvalidCommitment,
+			},
+			{
+				description: "commitment outside the subgroup",
+				tamper:      func(shares *Shares) { shares.Commitments[0][2] = commitmentOutside },
+				err:         ErrInvalidCommitment,
+			},
+		} {
+			t.Run(scenario.description, func(t *testing.T) {
+				shares, err := p.Split([]byte("secret"), nil)
+				require.NoError(t, err)
+
+				scenario.tamper(shares)
+
+				// a dealer producing wrong commitments binds them to the split anyway
+				shares.Bindings, err = chunkBindings(shares.SplitID, shares.Encoding, shares.Commitments)
+				require.NoError(t, err)
+
+				checks := map[string]error{}
+				checks["VerifyShares"] = p.VerifyShares(shares)
+				_, checks["Combine"] = p.Combine(shares)
+
+				subset, err := shares.Subset(1, 2, 3, 4)
+				require.NoError(t, err)
+				checks["VerifyShareholders"] = p.VerifyShareholders(subset, shares.Transcript())
+
+				for name, err := range checks {
+					if scenario.err == nil {
+						require.NoError(t, err, name)
+					} else {
+						require.ErrorIs(t, err, scenario.err, name)
+					}
+				}
+			})
+		}
+
+		require.NoError(t, p.Close())
+	}
+}
+
+func TestVerifyValues(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(5, 3, CyclicGroup(group))
+	require.NoError(t, err)
+	defer p.Close()
+
+	shares, err := p.Split([]byte("secret"), nil)
+	require.NoError(t, err)
+
+	abscissa := shares.Abscissae[0]
+	part := shares.Parts[0][0]
+	commitments := shares.Commitments[0]
+
+	require.NoError(t, p.Verify(abscissa, part, commitments))
+
+	require.ErrorIs(t, p.Verify(group.Q, part, commitments), ErrInvalidAbscissa)
+	require.ErrorIs(t, p.Verify(abscissa, SecretPart{SShare: group.Q, TShare: part.TShare}, commitments), ErrInvalidShare)
+	require.ErrorIs(t, p.Verify(abscissa, SecretPart{}, commitments), ErrNilShare)
+	require.ErrorIs(t, p.Verify(abscissa, part, commitments[1:]), ErrInsufficientCommitments)
+	require.ErrorIs(t, p.Verify(abscissa, part, []*big.Int{commitments[0], group.P, commitments[2]}), ErrInvalidCommitment)
+	require.ErrorIs(t, p.Verify(abscissa, part, []*big.Int{commitments[0], nil, commitments[2]}), ErrNilCommitment)
+}
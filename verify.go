@@ -7,10 +7,14 @@
 package pedersen
 
 import (
+	"crypto/rand"
 	"errors"
 
 	"github.com/matteoarella/pedersen/big"
-	"golang.org/x/sync/errgroup"
+)
+
+const (
+	batchWeightSizeBytes = 8
 )
 
 var (
@@ -22,9 +26,11 @@ var (
 	ErrInsufficientCommitments = errors.New("commitments length cannot be different from threshold")
 	ErrWrongSharesLen          = errors.New("shares parts length and commitments parts length must be equal")
 	ErrWrongSecretPart         = errors.New("wrong secret part")
+	ErrDuplicateShareholder    = errors.New("duplicate shareholder")
 )
 
-// validateShares validates if the provided shares have a correct shape.
+// validateShares validates if the provided shares have a correct shape,
+// and then if their values are valid.
 func (p *Pedersen) validateShares(s *Shares) error {
 	if s == nil {
 		return ErrNilShares
@@ -84,6 +90,116 @@ func (p *Pedersen) validateShares(s *Shares) error {
 		}
 	}
 
+	shares := s.shareholders()
+
+	// the parts and the commitments of shares without bindings are held together,
+	// as done by previous versions, so they are not bound to a distributed transcript
+	if t := s.Transcript(); !t.isLegacy() {
+		if err := p.validateTranscript(shares, t); err != nil {
+			return err
+		}
+	}
+
+	if err := p.validatePaths(s); err != nil {
+		return err
+	}
+
+	return p.validateValues(shares, s.Commitments)
+}
+
+// validateShareholders validates if the provided shareholders shares have a correct shape
+// with respect to the commitments matrix, and then if their values are valid.
+// The commitments matrix of the transcript is returned.
+func (p *Pedersen) validateShareholders(shares []Share, transcript *Transcript) ([][]*big.Int, error) {
+	if transcript == nil {
+		return nil, ErrNilTranscript
+	}
+
+	if len(shares) == 0 {
+		return nil, ErrInsufficientSharesParts
+	}
+
+	commitments, err := transcript.commitments(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.validateShareholdersShape(shares, commitments); err != nil {
+		return nil, err
+	}
+
+	if err := p.validateTranscript(shares, transcript); err != nil {
+		return nil, err
+	}
+
+	if err := p.validateValues(shares, commitments); err != nil {
+		return nil, err
+	}
+
+	return commitments, nil
+}
+
+// validateShareholdersShape validates if the provided shareholders shares have
+// a correct shape with respect to the commitments matrix.
+func (p *Pedersen) validateShareholdersShape(shares []Share, commitments [][]*big.Int) error {
+	if len(shares) == 0 {
+		return ErrInsufficientSharesParts
+	}
+
+	partsCount := len(commitments)
+	seen := make(map[int]struct{}, len(shares))
+
+	for _, share := range shares {
+		if share.Index < 0 || share.Index >= p.parts {
+			return ErrInvalidShareholder
+		}
+
+		if _, ok := seen[share.Index]; ok {
+			return ErrDuplicateShareholder
+		}
+		seen[share.Index] = struct{}{}
+
+		if share.Abscissa == nil {
+			return ErrNilAbscissa
+		}
+
+		if len(share.Parts) != partsCount {
+			return ErrWrongSharesLen
+		}
+	}
+
+	for partIdx := 0; partIdx < partsCount; partIdx++ {
+		if len(commitments[partIdx]) != p.threshold {
+			return ErrInsufficientCommitments
+		}
+
+		for i := 0; i < p.threshold; i++ {
+			if commitments[partIdx][i] == nil {
+				return ErrNilCommitment
+			}
+		}
+
+		parts := 0
+
+		for _, share := range shares {
+			part := share.Parts[partIdx]
+
+			if (SecretPart{}) == part {
+				continue
+			}
+
+			if part.SShare == nil || part.TShare == nil {
+				return ErrNilShare
+			}
+
+			parts++
+		}
+
+		if parts < p.threshold {
+			return ErrInsufficientSharesParts
+		}
+	}
+
 	return nil
 }
 
@@ -95,12 +211,12 @@ func (p *Pedersen) vandermondeAbscissa(ctx *big.IntContext,
 	abscissae[0] = big.One()
 
 	for i := 1; i < p.threshold; i++ {
-		a, err := ctx.GetInt()
+		a, err := big.NewInt()
 		if err != nil {
 			return nil, err
 		}
 
-		if err := a.ModMul(ctx, abscissae[i-1], abscissa, p.group.Q); err != nil {
+		if err := p.field.Mul(ctx, a, abscissae[i-1], abscissa); err != nil {
 			return nil, err
 		}
 
@@ -110,6 +226,37 @@ func (p *Pedersen) vandermondeAbscissa(ctx *big.IntContext,
 	return abscissae, nil
 }
 
+// evalCommitments sets z to the commitment of the secret part at the abscissa
+// whose powers are vandermondeAbscissa, z = c_0 * c_1^x * ... * c_j^{x^j}.
+// The context must be attached by the caller.
+func (p *Pedersen) evalCommitments(mont *big.MontgomeryContext,
+	ctx *big.IntContext,
+	z *big.Int,
+	vandermondeAbscissa []*big.Int,
+	commitments []*big.Int,
+) error {
+	if err := z.Set(commitments[0]); err != nil {
+		return err
+	}
+
+	for j := 1; j < p.threshold; j++ {
+		term, err := ctx.GetInt()
+		if err != nil {
+			return err
+		}
+
+		if err := term.ModExpMont(mont, ctx, commitments[j], vandermondeAbscissa[j], p.group.P); err != nil {
+			return err
+		}
+
+		if err := z.ModMul(ctx, z, term, p.group.P); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (p *Pedersen) verifyWithContext(mont *big.MontgomeryContext,
 	ctx *big.IntContext,
 	vandermondeAbscissa []*big.Int,
@@ -132,26 +279,10 @@ func (p *Pedersen) verifyWithContext(mont *big.MontgomeryContext,
 		return err
 	}
 
-	if err := rhs.Set(commitments[0]); err != nil {
+	if err := p.evalCommitments(mont, ctx, rhs, vandermondeAbscissa, commitments); err != nil {
 		return err
 	}
 
-	// rhs = c_0 * c_1^x * ... * c_j^{x^j}
-	for j := 1; j < p.threshold; j++ {
-		term, err := ctx.GetInt()
-		if err != nil {
-			return err
-		}
-
-		if err := term.ModExpMont(mont, ctx, commitments[j], vandermondeAbscissa[j], p.group.P); err != nil {
-			return err
-		}
-
-		if err := rhs.ModMul(ctx, rhs, term, p.group.P); err != nil {
-			return err
-		}
-	}
-
 	lhs, err := p.commit(mont, ctx, part.SShare, part.TShare)
 	if err != nil {
 		return err
@@ -171,33 +302,47 @@ func (p *Pedersen) verifyWithContext(mont *big.MontgomeryContext,
 
 // Verify verifies if the provided secret part is valid, according to the provided abscissa value and
 // commitments vector.
+// When only the Merkle root of the commitments is distributed, the commitments vector
+// must be authenticated with [Pedersen.VerifyProof] first.
 func (p *Pedersen) Verify(abscissa *big.Int, part SecretPart, commitments []*big.Int) error {
 	if abscissa == nil {
 		return ErrNilAbscissa
 	}
 
-	ctx, err := big.NewIntContext()
-	if err != nil {
+	if part.SShare == nil || part.TShare == nil {
+		return ErrNilShare
+	}
+
+	if len(commitments) != p.threshold {
+		return ErrInsufficientCommitments
+	}
+
+	if err := p.validateAbscissa(abscissa); err != nil {
 		return err
 	}
-	defer ctx.Destroy()
 
-	mont, err := big.NewMontgomeryContext()
-	if err != nil {
+	if err := p.validatePart(part); err != nil {
 		return err
 	}
-	defer mont.Destroy()
 
-	if err := mont.Set(p.group.P, ctx); err != nil {
+	w, err := p.pool.get()
+	if err != nil {
 		return err
 	}
+	defer p.pool.put(w)
+
+	for _, commitment := range commitments {
+		if err := p.validateCommitment(w, commitment); err != nil {
+			return err
+		}
+	}
 
-	vandermondeAbscissa, err := p.vandermondeAbscissa(ctx, abscissa)
+	vandermondeAbscissa, err := p.vandermondeAbscissa(w.ctx, abscissa)
 	if err != nil {
 		return err
 	}
 
-	return p.verifyWithContext(mont, ctx, vandermondeAbscissa, part, commitments)
+	return p.verifyWithContext(w.mont, w.ctx, vandermondeAbscissa, part, commitments)
 }
 
 // VerifyShares verifies if every secret part is valid.
@@ -207,57 +352,313 @@ func (p *Pedersen) VerifyShares(s *Shares) error {
 		return err
 	}
 
-	partsCount := len(s.Parts[0])
-	concLimit := p.adjustConcLimit(partsCount)
-	chunksIndex := p.balanceIndices(p.parts, concLimit)
-	group := errgroup.Group{}
-	group.SetLimit(concLimit)
-
-	for _, chunk := range chunksIndex {
-		chunk := chunk
-
-		group.Go(func() error {
-			ctx, err := big.NewIntContext()
-			if err != nil {
-				return err
+	return p.verifyShareholders(s.shareholders(), s.Commitments)
+}
+
+// VerifyShareholders verifies if every secret part of the provided shareholders
+// shares is valid.
+// Unlike [Pedersen.VerifyShares], only the shares that have been collected need
+// to be provided, in any order.
+func (p *Pedersen) VerifyShareholders(shares []Share, transcript *Transcript) error {
+	commitments, err := p.validateShareholders(shares, transcript)
+	if err != nil {
+		return err
+	}
+
+	return p.verifyShareholders(shares, commitments)
+}
+
+// VerifyShare verifies if every secret part of a single shareholder share is valid.
+// Unlike [Pedersen.VerifyShareholders], the share must contain the secret parts
+// of every chunk.
+func (p *Pedersen) VerifyShare(share Share, transcript *Transcript) error {
+	if transcript == nil {
+		return ErrNilTranscript
+	}
+
+	if share.Abscissa == nil {
+		return ErrNilAbscissa
+	}
+
+	shares := []Share{share}
+
+	commitments, err := transcript.commitments(shares)
+	if err != nil {
+		return err
+	}
+
+	if len(share.Parts) != len(commitments) {
+		return ErrWrongSharesLen
+	}
+
+	for partIdx, part := range share.Parts {
+		if part.SShare == nil || part.TShare == nil {
+			return ErrNilShare
+		}
+
+		if len(commitments[partIdx]) != p.threshold {
+			return ErrInsufficientCommitments
+		}
+
+		for _, commitment := range commitments[partIdx] {
+			if commitment == nil {
+				return ErrNilCommitment
+			}
+		}
+	}
+
+	if err := p.validateTranscript(shares, transcript); err != nil {
+		return err
+	}
+
+	if err := p.validateValues(shares, commitments); err != nil {
+		return err
+	}
+
+	return p.verifyShareholders(shares, commitments)
+}
+
+// verifyTask represents a batch of secret parts of a shareholder that are verified together.
+type verifyTask struct {
+	// share is the position of the shareholder within the shares slice.
+	share  int
+	chunks []int
+}
+
+func (p *Pedersen) verifyTasks(shares []Share, partsCount int) []verifyTask {
+	var tasks []verifyTask
+
+	for shareIdx, share := range shares {
+		var chunks []int
+
+		for partIdx := 0; partIdx < partsCount; partIdx++ {
+			if (SecretPart{}) == share.Parts[partIdx] {
+				continue
+			}
+
+			chunks = append(chunks, partIdx)
+
+			if len(chunks) == p.batchSize {
+				tasks = append(tasks, verifyTask{share: shareIdx, chunks: chunks})
+				chunks = nil
 			}
-			defer ctx.Destroy()
+		}
+
+		if len(chunks) > 0 {
+			tasks = append(tasks, verifyTask{share: shareIdx, chunks: chunks})
+		}
+	}
+
+	return tasks
+}
+
+func (p *Pedersen) verifyShareholders(shares []Share, commitments [][]*big.Int) error {
+	// the Vandermonde abscissa of every shareholder is computed once and shared by
+	// all the tasks related to it
+	vandermonde := make([][]*big.Int, len(shares))
+
+	err := p.runTasks(len(shares), func(w *workerContext, shareIdx int) error {
+		vandermondeAbscissa, err := p.vandermondeAbscissa(w.ctx, shares[shareIdx].Abscissa)
+		if err != nil {
+			return err
+		}
+
+		vandermonde[shareIdx] = vandermondeAbscissa
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tasks := p.verifyTasks(shares, len(commitments))
+
+	return p.runTasks(len(tasks), func(w *workerContext, taskIdx int) error {
+		task := tasks[taskIdx]
+
+		return p.verifyBatch(w.mont, w.ctx, vandermonde[task.share], shares[task.share], task.chunks, commitments)
+	})
+}
+
+// randomWeights returns n random nonzero weights for the small exponents batch test.
+func randomWeights(n int) ([]*big.Int, error) {
+	buf := make([]byte, n*batchWeightSizeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	weights := make([]*big.Int, n)
+
+	for i := 0; i < n; i++ {
+		w, err := big.NewInt()
+		if err != nil {
+			return nil, err
+		}
+
+		w.SetBytes(buf[i*batchWeightSizeBytes : (i+1)*batchWeightSizeBytes])
 
-			mont, err := big.NewMontgomeryContext()
-			if err != nil {
-				return err
+		if w.BitLen() == 0 {
+			if err := w.SetUInt64(1); err != nil {
+				return nil, err
 			}
-			defer mont.Destroy()
+		}
+
+		weights[i] = w
+	}
+
+	return weights, nil
+}
+
+// verifyBatch verifies the secret parts of the provided chunks of a shareholder at once.
+// With random weights r_c, the parts are valid if
+//
+//	g^(Σ r_c s_c) h^(Σ r_c t_c) = Π_j (Π_c C_{c,j}^r_c)^(x^j)
+//
+// which fails with probability at most 2^-64 when any part is wrong.
+// The products Π_c C_{c,j}^r_c are computed with a multi-exponentiation.
+// If the batch does not verify, every part is verified individually for pinpointing
+// the wrong one.
+func (p *Pedersen) verifyBatch(mont *big.MontgomeryContext,
+	ctx *big.IntContext,
+	vandermondeAbscissa []*big.Int,
+	share Share,
+	chunks []int,
+	commitments [][]*big.Int,
+) error {
+	if len(chunks) == 1 {
+		return p.verifyWithContext(mont, ctx, vandermondeAbscissa, share.Parts[chunks[0]], commitments[chunks[0]])
+	}
+
+	ok, err := p.checkBatch(mont, ctx, vandermondeAbscissa, share, chunks, commitments)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		return nil
+	}
+
+	for _, chunkIdx := range chunks {
+		err := p.verifyWithContext(mont, ctx, vandermondeAbscissa, share.Parts[chunkIdx], commitments[chunkIdx])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Pedersen) checkBatch(mont *big.MontgomeryContext,
+	ctx *big.IntContext,
+	vandermondeAbscissa []*big.Int,
+	share Share,
+	chunks []int,
+	commitments [][]*big.Int,
+) (bool, error) {
+	weights, err := randomWeights(len(chunks))
+	if err != nil {
+		return false, err
+	}
+
+	ctx.Attach()
+	defer ctx.Detach()
+
+	sSum, tSum, err := p.batchSums(ctx, weights, share, chunks)
+	if err != nil {
+		return false, err
+	}
+
+	term, err := ctx.GetInt()
+	if err != nil {
+		return false, err
+	}
+
+	lhs, err := p.commit(mont, ctx, sSum, tSum)
+	if err != nil {
+		return false, err
+	}
+
+	rhs, err := ctx.GetInt()
+	if err != nil {
+		return false, err
+	}
 
-			if err := mont.Set(p.group.P, ctx); err != nil {
-				return err
+	bases := make([]*big.Int, len(chunks))
+
+	for j := 0; j < p.threshold; j++ {
+		for i, chunkIdx := range chunks {
+			bases[i] = commitments[chunkIdx][j]
+		}
+
+		if err := term.ModMultiExp(ctx, bases, weights, p.group.P); err != nil {
+			return false, err
+		}
+
+		if j == 0 {
+			if err := rhs.Set(term); err != nil {
+				return false, err
 			}
 
-			for idx := chunk.start; idx < chunk.end; idx++ {
-				// compute Vandermonde abscissa
-				vandermondeAbscissa, err := p.vandermondeAbscissa(ctx, s.Abscissae[idx])
-				if err != nil {
-					return err
-				}
-
-				for partIndex := 0; partIndex < partsCount; partIndex++ {
-					if (SecretPart{}) == s.Parts[idx][partIndex] {
-						continue
-					}
-
-					err := p.verifyWithContext(
-						mont,
-						ctx,
-						vandermondeAbscissa, s.Parts[idx][partIndex], s.Commitments[partIndex])
-					if err != nil {
-						return err
-					}
-				}
+			continue
+		}
+
+		if err := term.ModExpMont(mont, ctx, term, vandermondeAbscissa[j], p.group.P); err != nil {
+			return false, err
+		}
+
+		if err := rhs.ModMul(ctx, rhs, term, p.group.P); err != nil {
+			return false, err
+		}
+	}
+
+	return lhs.ConstantTimeEq(rhs)
+}
+
+// batchSums computes the weighted sums Σ r_c s_c and Σ r_c t_c of the secret parts
+// of the provided chunks. The sums are computed in the field of the exponents,
+// so that they carry the constant-time flag like every other value derived from
+// the secret parts.
+// The returned values are allocated from ctx, which must be attached.
+func (p *Pedersen) batchSums(
+	ctx *big.IntContext,
+	weights []*big.Int,
+	share Share,
+	chunks []int,
+) (*big.Int, *big.Int, error) {
+	sums := make([]*big.Int, 2)
+
+	for i := range sums {
+		sum, err := ctx.GetInt()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err := sum.SetUInt64(0); err != nil {
+			return nil, nil, err
+		}
+
+		sums[i] = sum.SetConstantTime()
+	}
+
+	term, err := ctx.GetInt()
+	if err != nil {
+		return nil, nil, err
+	}
+	term.SetConstantTime()
+
+	for i, chunkIdx := range chunks {
+		part := share.Parts[chunkIdx]
+
+		for j, value := range []*big.Int{part.SShare, part.TShare} {
+			if err := p.field.Mul(ctx, term, weights[i], value); err != nil {
+				return nil, nil, err
 			}
 
-			return nil
-		})
+			if err := p.field.Add(ctx, sums[j], sums[j], term); err != nil {
+				return nil, nil, err
+			}
+		}
 	}
 
-	return group.Wait()
+	return sums[0], sums[1], nil
 }
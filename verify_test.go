@@ -199,7 +199,38 @@ func TestPedersenVerifyValid(t *testing.T) {
 	}
 }
 
-func benchmarkVerifyCase(b *testing.B, groupSize, parts, threshold int) {
+func TestPedersenVerifyBatch(t *testing.T) {
+	group := getTestSchnorrGroup(t)
+
+	randomSecret := make([]byte, 512)
+	_, err := rand.Read(randomSecret)
+	require.NoError(t, err)
+
+	for _, batchSize := range []int{1, 7, 64} {
+		p, err := pedersen.NewPedersen(5, 3, pedersen.CyclicGroup(group), pedersen.BatchVerification(batchSize))
+		require.NoError(t, err)
+		require.Equal(t, batchSize, p.GetBatchSize())
+
+		shares, err := p.Split(randomSecret, nil)
+		require.NoError(t, err)
+
+		err = p.VerifyShares(shares)
+		require.NoError(t, err)
+
+		// tamper with a single secret part
+		chunkIdx := len(shares.Parts[3]) / 2
+		tampered := pedersen.SecretPart{
+			SShare: shares.Parts[3][chunkIdx].TShare,
+			TShare: shares.Parts[3][chunkIdx].SShare,
+		}
+		shares.Parts[3][chunkIdx] = tampered
+
+		err = p.VerifyShares(shares)
+		require.ErrorIs(t, err, pedersen.ErrWrongSecretPart)
+	}
+}
+
+func benchmarkVerifyCase(b *testing.B, groupSize, parts, threshold int, options ...pedersen.Option) {
 	b.Helper()
 
 	group, err := pedersen.NewSchnorrGroup(groupSize)
@@ -209,7 +240,9 @@ func benchmarkVerifyCase(b *testing.B, groupSize, parts, threshold int) {
 	_, err = rand.Read(randomSecret)
 	require.NoError(b, err)
 
-	p, err := pedersen.NewPedersen(parts, threshold, pedersen.CyclicGroup(group))
+	options = append([]pedersen.Option{pedersen.CyclicGroup(group)}, options...)
+
+	p, err := pedersen.NewPedersen(parts, threshold, options...)
 	require.NoError(b, err)
 	require.NotNil(b, p)
 
@@ -248,3 +281,30 @@ func BenchmarkPedersenVerify_2048_7_4(b *testing.B) {
 func BenchmarkPedersenVerify_2048_10_5(b *testing.B) {
 	benchmarkVerifyCase(b, 2048, 10, 5)
 }
+
+func BenchmarkPedersenVerifyUnbatched_1024_10_5(b *testing.B) {
+	benchmarkVerifyCase(b, 1024, 10, 5, pedersen.BatchVerification(1))
+}
+
+func BenchmarkPedersenVerifyUnbatched_2048_10_5(b *testing.B) {
+	benchmarkVerifyCase(b, 2048, 10, 5, pedersen.BatchVerification(1))
+}
+
+func BenchmarkPedersenVerifyPart_1024_10_5(b *testing.B) {
+	group, err := pedersen.NewSchnorrGroup(1024)
+	require.NoError(b, err)
+
+	p, err := pedersen.NewPedersen(10, 5, pedersen.CyclicGroup(group))
+	require.NoError(b, err)
+	defer p.Close()
+
+	shares, err := p.Split([]byte("secret"), nil)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err = p.Verify(shares.Abscissae[i%10], shares.Parts[i%10][0], shares.Commitments[0])
+		require.NoError(b, err)
+	}
+}
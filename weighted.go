@@ -0,0 +1,219 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"errors"
+
+	"github.com/matteoarella/pedersen/big"
+)
+
+var (
+	ErrEmptyRoster     = errors.New("roster does not contain any shareholder")
+	ErrInvalidWeight   = errors.New("shareholder weight must be at least 1")
+	ErrRosterMismatch  = errors.New("roster weights do not sum to the number of parts")
+	ErrMissingRoster   = errors.New("weighted shareholders require the WeightedRoster option")
+	ErrBundleMismatch  = errors.New("bundled shares do not match the indices of the weighted shareholder")
+	ErrDuplicateHolder = errors.New("duplicate weighted shareholder")
+)
+
+// A Roster assigns a weight to every weighted shareholder.
+// Every weighted shareholder is given as many shareholder indices as its weight,
+// and the indices of a weighted shareholder follow the ones of the previous
+// weighted shareholders, so the number of parts is the sum of the weights and
+// the threshold counts the weight of the shareholders.
+type Roster struct {
+	weights []int
+	offsets []int
+	parts   int
+}
+
+// WeightedShare represents the shares bundled to a weighted shareholder.
+type WeightedShare struct {
+	// Holder is the index of the weighted shareholder within the roster.
+	Holder int
+
+	// Shares is the vector of the shares of the shareholder indices assigned
+	// to the weighted shareholder, in ascending order of index.
+	Shares []Share
+}
+
+// NewRoster creates a new roster where the weighted shareholder with index i
+// has weight weights[i].
+func NewRoster(weights ...int) (*Roster, error) {
+	if len(weights) == 0 {
+		return nil, ErrEmptyRoster
+	}
+
+	r := &Roster{
+		weights: append([]int(nil), weights...),
+		offsets: make([]int, len(weights)),
+	}
+
+	for holder, weight := range weights {
+		if weight < 1 {
+			return nil, ErrInvalidWeight
+		}
+
+		r.offsets[holder] = r.parts
+		r.parts += weight
+	}
+
+	return r, nil
+}
+
+// Holders returns the number of weighted shareholders.
+func (r *Roster) Holders() int {
+	return len(r.weights)
+}
+
+// Parts returns the number of parts, which is the sum of the weights.
+func (r *Roster) Parts() int {
+	return r.parts
+}
+
+// Weight returns the weight of the weighted shareholder.
+func (r *Roster) Weight(holder int) (int, error) {
+	if holder < 0 || holder >= len(r.weights) {
+		return 0, ErrInvalidShareholder
+	}
+
+	return r.weights[holder], nil
+}
+
+// Indices returns the shareholder indices assigned to the weighted shareholder.
+func (r *Roster) Indices(holder int) ([]int, error) {
+	weight, err := r.Weight(holder)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]int, weight)
+	for i := range indices {
+		indices[i] = r.offsets[holder] + i
+	}
+
+	return indices, nil
+}
+
+// Bundle arranges the shares of every shareholder index into the shares of
+// the weighted shareholders.
+func (r *Roster) Bundle(s *Shares) ([]WeightedShare, error) {
+	if len(s.Parts) != r.parts || len(s.Abscissae) != r.parts {
+		return nil, ErrRosterMismatch
+	}
+
+	holders := make([]WeightedShare, len(r.weights))
+
+	for holder := range holders {
+		indices, err := r.Indices(holder)
+		if err != nil {
+			return nil, err
+		}
+
+		shares, err := s.Subset(indices...)
+		if err != nil {
+			return nil, err
+		}
+
+		holders[holder] = WeightedShare{
+			Holder: holder,
+			Shares: shares,
+		}
+	}
+
+	return holders, nil
+}
+
+// Unbundle returns the shares bundled to the provided weighted shareholders.
+// Every weighted shareholder must carry the shares of exactly the shareholder
+// indices assigned to it, so that no shareholder can contribute more than its
+// weight towards the threshold.
+func (r *Roster) Unbundle(holders ...WeightedShare) ([]Share, error) {
+	var shares []Share
+
+	seen := make(map[int]struct{}, len(holders))
+
+	for _, holder := range holders {
+		indices, err := r.Indices(holder.Holder)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[holder.Holder]; ok {
+			return nil, ErrDuplicateHolder
+		}
+		seen[holder.Holder] = struct{}{}
+
+		if len(holder.Shares) != len(indices) {
+			return nil, ErrBundleMismatch
+		}
+
+		for i, share := range holder.Shares {
+			if share.Index != indices[i] {
+				return nil, ErrBundleMismatch
+			}
+		}
+
+		shares = append(shares, holder.Shares...)
+	}
+
+	return shares, nil
+}
+
+// SplitWeighted splits the secret among the weighted shareholders of the roster
+// set with the [WeightedRoster] option, and returns the shares of every
+// shareholder index along with the shares bundled to every weighted shareholder.
+// If abscissae is nil, random abscissae are generated for every shareholder index.
+func (p *Pedersen) SplitWeighted(secret []byte, abscissae []*big.Int) (*Shares, []WeightedShare, error) {
+	if p.roster == nil {
+		return nil, nil, ErrMissingRoster
+	}
+
+	shares, err := p.Split(secret, abscissae)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	holders, err := p.roster.Bundle(shares)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return shares, holders, nil
+}
+
+// CombineWeighted combines the shares of the provided weighted shareholders into
+// the original secret. The secret is reconstructed only if the sum of the weights
+// of the shareholders reaches the threshold.
+func (p *Pedersen) CombineWeighted(holders []WeightedShare, transcript *Transcript) ([]byte, error) {
+	if p.roster == nil {
+		return nil, ErrMissingRoster
+	}
+
+	shares, err := p.roster.Unbundle(holders...)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.CombineShareholders(shares, transcript)
+}
+
+// VerifyWeighted verifies if every secret part of the provided weighted
+// shareholders is valid, and if the sum of their weights reaches the threshold.
+func (p *Pedersen) VerifyWeighted(holders []WeightedShare, transcript *Transcript) error {
+	if p.roster == nil {
+		return ErrMissingRoster
+	}
+
+	shares, err := p.roster.Unbundle(holders...)
+	if err != nil {
+		return err
+	}
+
+	return p.VerifyShareholders(shares, transcript)
+}
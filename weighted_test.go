@@ -0,0 +1,140 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen_test
+
+import (
+	"testing"
+
+	"github.com/matteoarella/pedersen"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRoster(t *testing.T) {
+	_, err := pedersen.NewRoster()
+	require.ErrorIs(t, err, pedersen.ErrEmptyRoster)
+
+	_, err = pedersen.NewRoster(2, 0, 1)
+	require.ErrorIs(t, err, pedersen.ErrInvalidWeight)
+
+	roster, err := pedersen.NewRoster(2, 1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, roster.Holders())
+	assert.Equal(t, 6, roster.Parts())
+
+	weight, err := roster.Weight(2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, weight)
+
+	indices, err := roster.Indices(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, indices)
+
+	_, err = roster.Indices(3)
+	require.ErrorIs(t, err, pedersen.ErrInvalidShareholder)
+}
+
+func TestPedersenWeighted(t *testing.T) {
+	group, err := pedersen.NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	roster, err := pedersen.NewRoster(2, 1, 1, 1)
+	require.NoError(t, err)
+
+	_, err = pedersen.NewPedersen(4, 3, pedersen.CyclicGroup(group), pedersen.WeightedRoster(roster))
+	require.ErrorIs(t, err, pedersen.ErrRosterMismatch)
+
+	p, err := pedersen.NewPedersen(roster.Parts(), 3, pedersen.CyclicGroup(group), pedersen.WeightedRoster(roster))
+	require.NoError(t, err)
+	defer p.Close()
+	require.Equal(t, roster, p.GetRoster())
+
+	secret := []byte("secret of weighted shareholders")
+
+	shares, holders, err := p.SplitWeighted(secret, nil)
+	require.NoError(t, err)
+	require.Len(t, holders, roster.Holders())
+	require.Len(t, shares.Parts, roster.Parts())
+
+	require.Len(t, holders[0].Shares, 2)
+	assert.Equal(t, 0, holders[0].Shares[0].Index)
+	assert.Equal(t, 1, holders[0].Shares[1].Index)
+	require.Len(t, holders[3].Shares, 1)
+	assert.Equal(t, 4, holders[3].Shares[0].Index)
+
+	transcript := shares.Transcript()
+
+	for _, subset := range [][]pedersen.WeightedShare{
+		{holders[0], holders[2]},
+		{holders[3], holders[1], holders[2]},
+		holders,
+	} {
+		require.NoError(t, p.VerifyWeighted(subset, transcript))
+
+		reconstructed, err := p.CombineWeighted(subset, transcript)
+		require.NoError(t, err)
+		assert.Equal(t, secret, reconstructed)
+	}
+
+	t.Run("weight below threshold", func(t *testing.T) {
+		subset := []pedersen.WeightedShare{holders[1], holders[3]}
+
+		require.ErrorIs(t, p.VerifyWeighted(subset, transcript), pedersen.ErrInsufficientSharesParts)
+
+		_, err := p.CombineWeighted(subset, transcript)
+		require.ErrorIs(t, err, pedersen.ErrInsufficientSharesParts)
+	})
+
+	t.Run("duplicate shareholder", func(t *testing.T) {
+		_, err := p.CombineWeighted([]pedersen.WeightedShare{holders[1], holders[1], holders[2]}, transcript)
+		require.ErrorIs(t, err, pedersen.ErrDuplicateHolder)
+	})
+
+	t.Run("shares of another shareholder", func(t *testing.T) {
+		// a unit shareholder claiming the shares of the weighted one
+		forged := pedersen.WeightedShare{Holder: 1, Shares: holders[0].Shares}
+
+		_, err := p.CombineWeighted([]pedersen.WeightedShare{forged, holders[2]}, transcript)
+		require.ErrorIs(t, err, pedersen.ErrBundleMismatch)
+
+		// a weighted shareholder claiming the same index twice
+		forged = pedersen.WeightedShare{
+			Holder: 0,
+			Shares: []pedersen.Share{holders[0].Shares[0], holders[0].Shares[0]},
+		}
+
+		_, err = p.CombineWeighted([]pedersen.WeightedShare{forged, holders[2]}, transcript)
+		require.ErrorIs(t, err, pedersen.ErrBundleMismatch)
+
+		// a weighted shareholder with part of its shares
+		partial := pedersen.WeightedShare{Holder: 0, Shares: holders[0].Shares[:1]}
+		require.ErrorIs(t, p.VerifyWeighted([]pedersen.WeightedShare{partial, holders[2], holders[3]}, transcript),
+			pedersen.ErrBundleMismatch)
+	})
+
+	t.Run("unknown shareholder", func(t *testing.T) {
+		unknown := pedersen.WeightedShare{Holder: 4, Shares: holders[3].Shares}
+
+		_, err := p.CombineWeighted([]pedersen.WeightedShare{holders[0], unknown}, transcript)
+		require.ErrorIs(t, err, pedersen.ErrInvalidShareholder)
+	})
+
+	t.Run("missing roster", func(t *testing.T) {
+		unweighted, err := pedersen.NewPedersen(roster.Parts(), 3, pedersen.CyclicGroup(group))
+		require.NoError(t, err)
+		defer unweighted.Close()
+
+		_, _, err = unweighted.SplitWeighted(secret, nil)
+		require.ErrorIs(t, err, pedersen.ErrMissingRoster)
+
+		_, err = unweighted.CombineWeighted(holders, transcript)
+		require.ErrorIs(t, err, pedersen.ErrMissingRoster)
+
+		require.ErrorIs(t, unweighted.VerifyWeighted(holders, transcript), pedersen.ErrMissingRoster)
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/matteoarella/pedersen/big"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrClosed = errors.New("pedersen struct is closed")
+)
+
+// workerContext holds the contexts used by a single worker, which are
+// reused by every task the worker runs.
+type workerContext struct {
+	ctx *big.IntContext
+
+	// mont is the Montgomery context for P, which is shared by every
+	// worker since it is never modified after being set.
+	mont *big.MontgomeryContext
+}
+
+// contextPool is a concurrency-safe pool of worker contexts, which are
+// reused across calls for avoiding the allocation of new OpenSSL contexts
+// and the initialization of the Montgomery context for each call.
+type contextPool struct {
+	mu     sync.Mutex
+	mont   *big.MontgomeryContext
+	free   []*big.IntContext
+	size   int
+	inUse  int
+	closed bool
+}
+
+// newContextPool creates a pool that keeps at most size idle contexts.
+func newContextPool(modulus *big.Int, size int) (*contextPool, error) {
+	ctx, err := big.NewIntContext()
+	if err != nil {
+		return nil, err
+	}
+
+	mont, err := big.NewMontgomeryContext()
+	if err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+
+	if err := mont.Set(modulus, ctx); err != nil {
+		mont.Destroy()
+		ctx.Destroy()
+		return nil, err
+	}
+
+	return &contextPool{
+		mont: mont,
+		free: []*big.IntContext{ctx},
+		size: size,
+	}, nil
+}
+
+// get returns an idle worker context, or a new one if none is available.
+func (c *contextPool) get() (*workerContext, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+
+	var ctx *big.IntContext
+
+	if n := len(c.free); n > 0 {
+		ctx = c.free[n-1]
+		c.free = c.free[:n-1]
+	} else {
+		/* every worker needs its own IntContext since it's not safe to
+		use the same context concurrently */
+		var err error
+
+		ctx, err = big.NewIntContext()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.inUse++
+
+	return &workerContext{
+		ctx:  ctx,
+		mont: c.mont,
+	}, nil
+}
+
+// put gives back a worker context obtained from get.
+func (c *contextPool) put(w *workerContext) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inUse--
+
+	if c.closed || len(c.free) >= c.size {
+		w.ctx.Destroy()
+	} else {
+		c.free = append(c.free, w.ctx)
+	}
+
+	c.release()
+}
+
+// close releases the idle contexts, while the contexts in use are released
+// as soon as they are given back.
+func (c *contextPool) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	c.closed = true
+
+	for _, ctx := range c.free {
+		ctx.Destroy()
+	}
+
+	c.free = nil
+	c.release()
+
+	return nil
+}
+
+// release destroys the shared Montgomery context once the pool is closed
+// and no worker is using it. It must be called with the lock held.
+func (c *contextPool) release() {
+	if c.closed && c.inUse == 0 && c.mont != nil {
+		c.mont.Destroy()
+		c.mont = nil
+	}
+}
+
+// runTasks runs the tasks numbered from 0 to n-1 on a pool of at most
+// concLimit workers. Tasks are handed out one at a time to the first idle
+// worker, so that all the workers are kept busy until the last task even
+// when tasks have different costs.
+// After the first error no more tasks are started and the error is returned.
+func (p *Pedersen) runTasks(n int, task func(w *workerContext, i int) error) error {
+	workers := p.adjustConcLimit(n)
+	group, groupCtx := errgroup.WithContext(context.Background())
+	next := int64(-1)
+
+	for i := 0; i < workers; i++ {
+		group.Go(func() error {
+			w, err := p.pool.get()
+			if err != nil {
+				return err
+			}
+			defer p.pool.put(w)
+
+			for groupCtx.Err() == nil {
+				taskIdx := int(atomic.AddInt64(&next, 1))
+				if taskIdx >= n {
+					return nil
+				}
+
+				if err := task(w, taskIdx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return group.Wait()
+}
@@ -0,0 +1,170 @@
+// Copyright (c) Pedersen authors.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://opensource.org/licenses/MIT.
+
+package pedersen
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTasks(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	for _, concLimit := range []int{1, 3, 16} {
+		p, err := NewPedersen(5, 3, CyclicGroup(group), ConcLimit(concLimit))
+		require.NoError(t, err)
+
+		counts := make([]int32, 100)
+
+		err = p.runTasks(len(counts), func(w *workerContext, i int) error {
+			atomic.AddInt32(&counts[i], 1)
+			return nil
+		})
+		require.NoError(t, err)
+
+		for _, count := range counts {
+			require.Equal(t, int32(1), count)
+		}
+
+		// no task is started after the first error
+		errTask := errors.New("task error")
+		started := int32(0)
+
+		err = p.runTasks(10000, func(w *workerContext, i int) error {
+			atomic.AddInt32(&started, 1)
+			return errTask
+		})
+		require.ErrorIs(t, err, errTask)
+		require.LessOrEqual(t, started, int32(2*concLimit))
+
+		// no worker is started without tasks
+		require.NoError(t, p.runTasks(0, nil))
+	}
+}
+
+func TestContextPool(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	pool, err := newContextPool(group.P, 2)
+	require.NoError(t, err)
+
+	first, err := pool.get()
+	require.NoError(t, err)
+	second, err := pool.get()
+	require.NoError(t, err)
+	third, err := pool.get()
+	require.NoError(t, err)
+
+	require.Same(t, first.mont, second.mont)
+	require.NotSame(t, first.ctx, second.ctx)
+
+	pool.put(first)
+	pool.put(second)
+	pool.put(third)
+	require.Len(t, pool.free, 2)
+
+	// idle contexts are reused
+	reused, err := pool.get()
+	require.NoError(t, err)
+	require.Same(t, second.ctx, reused.ctx)
+
+	require.NoError(t, pool.close())
+	require.Empty(t, pool.free)
+	require.NotNil(t, pool.mont)
+
+	// the Montgomery context is released with the last context in use
+	pool.put(reused)
+	require.Nil(t, pool.mont)
+
+	_, err = pool.get()
+	require.ErrorIs(t, err, ErrClosed)
+	require.ErrorIs(t, pool.close(), ErrClosed)
+}
+
+func TestPedersenClose(t *testing.T) {
+	group, err := NewSchnorrGroup(128)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(5, 3, CyclicGroup(group))
+	require.NoError(t, err)
+
+	shares, err := p.Split([]byte("secret"), nil)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			share := i % p.GetParts()
+			assert.NoError(t, p.Verify(shares.Abscissae[share], shares.Parts[share][0], shares.Commitments[0]))
+		}(i)
+	}
+	wg.Wait()
+
+	require.NoError(t, p.Close())
+	require.ErrorIs(t, p.Close(), ErrClosed)
+
+	_, err = p.Split([]byte("secret"), nil)
+	require.ErrorIs(t, err, ErrClosed)
+
+	_, err = p.Combine(shares)
+	require.ErrorIs(t, err, ErrClosed)
+
+	require.ErrorIs(t, p.VerifyShares(shares), ErrClosed)
+	require.ErrorIs(t, p.Verify(shares.Abscissae[0], shares.Parts[0][0], shares.Commitments[0]), ErrClosed)
+}
+
+func TestSplitShortSecretHighThreshold(t *testing.T) {
+	group, err := NewSchnorrGroup(512)
+	require.NoError(t, err)
+
+	p, err := NewPedersen(100, 20, CyclicGroup(group))
+	require.NoError(t, err)
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	shares, err := p.Split(secret, nil)
+	require.NoError(t, err)
+	require.Len(t, shares.Commitments, 1)
+	require.Len(t, shares.Commitments[0], 20)
+
+	require.NoError(t, p.VerifyShares(shares))
+
+	subset, err := shares.Subset(99, 3, 57, 12, 0, 41, 8, 76, 23, 64, 5, 90, 33, 17, 48, 2, 81, 60, 29, 11)
+	require.NoError(t, err)
+
+	combined, err := p.CombineShareholders(subset, shares.Transcript())
+	require.NoError(t, err)
+	require.Equal(t, secret, combined)
+}
+
+func BenchmarkSplitShortSecret_512_100_20(b *testing.B) {
+	group, err := NewSchnorrGroup(512)
+	require.NoError(b, err)
+
+	p, err := NewPedersen(100, 20, CyclicGroup(group))
+	require.NoError(b, err)
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := p.Split(secret, nil)
+		require.NoError(b, err)
+	}
+}